@@ -3,6 +3,7 @@ module github.com/jodydadescott/shelly-manager
 go 1.20
 
 require (
+	github.com/fatih/color v1.15.0
 	github.com/gorilla/websocket v1.5.0
 	github.com/hashicorp/go-multierror v1.1.1
 	github.com/hashicorp/mdns v1.0.5
@@ -10,11 +11,11 @@ require (
 	github.com/jinzhu/copier v0.3.5
 	github.com/spf13/cobra v1.7.0
 	go.uber.org/zap v1.24.0
+	golang.org/x/crypto v0.8.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
-	github.com/fatih/color v1.15.0 // indirect
 	github.com/hashicorp/errwrap v1.0.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
@@ -23,6 +24,6 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
-	golang.org/x/net v0.0.0-20210410081132-afb366fc7cd1 // indirect
-	golang.org/x/sys v0.6.0 // indirect
+	golang.org/x/net v0.9.0 // indirect
+	golang.org/x/sys v0.7.0 // indirect
 )
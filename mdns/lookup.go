@@ -0,0 +1,78 @@
+package mdns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/mdns"
+)
+
+const (
+	// ShellyService is the service advertised by Shelly devices. The instance name is the device id.
+	ShellyService = "_shelly._tcp"
+)
+
+// Entry is a device found with mDNS
+type Entry struct {
+	Name string
+	Host string
+	IP   net.IP
+	Port int
+}
+
+// Discover queries the local network for Shelly devices for the duration of the timeout
+func Discover(timeout time.Duration) ([]*Entry, error) {
+
+	entriesCh := make(chan *mdns.ServiceEntry, 16)
+
+	params := mdns.DefaultParams(ShellyService)
+	params.DisableIPv6 = true
+	params.Timeout = timeout
+	params.Entries = entriesCh
+
+	var entries []*Entry
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for entry := range entriesCh {
+			entries = append(entries, &Entry{
+				Name: entry.Name,
+				Host: entry.Host,
+				IP:   entry.AddrV4,
+				Port: entry.Port,
+			})
+		}
+	}()
+
+	err := mdns.Query(params)
+	close(entriesCh)
+	<-done
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// LookupDevice returns the entry of the device with the id, for example shellyplus1-a8032ab12345
+func LookupDevice(deviceID string, timeout time.Duration) (*Entry, error) {
+
+	entries, err := Discover(timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	want := strings.ToLower(deviceID)
+
+	for _, entry := range entries {
+		if entry.IP != nil && strings.Contains(strings.ToLower(entry.Name), want) {
+			return entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("device %s not found with mDNS", deviceID)
+}
@@ -8,8 +8,10 @@ import (
 	"strings"
 
 	"github.com/hokaccha/go-prettyjson"
+	"github.com/jodydadescott/shelly-manager/shelly/crypt"
 	"github.com/jodydadescott/shelly-manager/shelly/plus"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+	"github.com/jodydadescott/shelly-manager/shelly/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
@@ -23,6 +25,11 @@ type Cmd struct {
 	formatArg   string
 	*cobra.Command
 	debugEnabledArg bool
+	showSecretsArg  bool
+	encryptArg      bool
+	passphraseArg   string
+	recipientArgs   []string
+	identityArgs    []string
 }
 
 func NewCmd() *Cmd {
@@ -38,13 +45,31 @@ func NewCmd() *Cmd {
 	d.PersistentFlags().StringVarP(&d.inputArg, "input", "i", "", "input filename")
 	d.PersistentFlags().StringVarP(&d.passwordArg, "password", "p", "", "password; can also be set with env var '"+ShellyPasswordEnvVar+"'")
 	d.PersistentFlags().BoolVarP(&d.debugEnabledArg, "debug", "d", false, "debug to STDERR")
+	d.PersistentFlags().BoolVar(&d.showSecretsArg, "show-secrets", false, "show passwords and other secrets in output instead of masking them")
+	d.PersistentFlags().BoolVar(&d.encryptArg, "encrypt", false, "encrypt output files with the passphrase; secrets are not masked in encrypted output")
+	d.PersistentFlags().StringVar(&d.passphraseArg, "passphrase", "", "passphrase or secret reference (env:, file:, cmd:) to encrypt and decrypt; can also be set with env var '"+ShellyPassphraseEnvVar+"'")
+	d.PersistentFlags().StringArrayVar(&d.recipientArgs, "recipient", nil, "encrypt output files for the public key or the keys in the file; may be repeated")
+	d.PersistentFlags().StringArrayVar(&d.identityArgs, "identity", nil, "identity file with private keys to decrypt input; may be repeated")
 
-	d.AddCommand(plus.NewCmd(d))
+	d.AddCommand(plus.NewCmd(d), crypt.NewCmd(d))
 	return d
 }
 
 func (t *Cmd) WriteObject(obj any) error {
 
+	// Only output files are encrypted. Output to STDOUT such as the manifest of an encrypted backup is
+	// written as is with secrets masked.
+	toFile := t.outputArg != "" && strings.ToLower(t.outputArg) != "stdout"
+	encrypt := t.isEncryptEnabled() && toFile
+
+	if !t.showSecretsArg && !encrypt {
+		masked, err := maskSecrets(obj)
+		if err != nil {
+			return err
+		}
+		obj = masked
+	}
+
 	// If output is STDOUT (default) then default format is Pretty JSON
 
 	switch strings.ToLower(t.outputArg) {
@@ -85,32 +110,134 @@ func (t *Cmd) WriteObject(obj any) error {
 
 	// If output is a file then default format is JSON
 
+	var data []byte
+	var err error
+
 	switch strings.ToLower(t.formatArg) {
 
 	case "json", "":
-		data, err := json.Marshal(obj)
+		data, err = json.Marshal(obj)
+
+	case "prettyjson":
+		data, err = prettyjson.Marshal(obj)
+
+	case "yaml":
+		data, err = yaml.Marshal(obj)
+
+	default:
+		return fmt.Errorf("format type %s is unknown", t.formatArg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !encrypt {
+		return os.WriteFile(t.outputArg, data, 0644)
+	}
+
+	data, err = t.EncryptOutput(data)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(t.outputArg, data, 0600)
+}
+
+func (t *Cmd) isEncryptEnabled() bool {
+	return t.encryptArg || len(t.recipientArgs) > 0
+}
+
+// EncryptOutput encrypts the data if --encrypt or --recipient is set and returns it unchanged otherwise
+func (t *Cmd) EncryptOutput(data []byte) ([]byte, error) {
+
+	if !t.isEncryptEnabled() {
+		return data, nil
+	}
+
+	options := &crypt.EncryptOptions{}
+
+	if t.encryptArg {
+		passphrase, err := t.getPassphrase()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		return os.WriteFile(t.outputArg, data, 0644)
+		if passphrase == "" {
+			return nil, fmt.Errorf("--encrypt requires --passphrase or env var %s", ShellyPassphraseEnvVar)
+		}
+		options.Passphrase = passphrase
+	}
 
-	case "prettyjson":
-		data, err := prettyjson.Marshal(obj)
+	for _, recipient := range t.recipientArgs {
+		keys, err := crypt.ReadRecipients(recipient)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		return os.WriteFile(t.outputArg, data, 0644)
+		options.Recipients = append(options.Recipients, keys...)
+	}
 
-	case "yaml":
-		data, err := yaml.Marshal(obj)
+	return crypt.Encrypt(data, options)
+}
+
+// DecryptInput decrypts the data if it is encrypted and returns it unchanged otherwise
+func (t *Cmd) DecryptInput(data []byte) ([]byte, error) {
+
+	if !crypt.IsEncrypted(data) {
+		return data, nil
+	}
+
+	passphrase, err := t.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	options := &crypt.DecryptOptions{
+		Passphrase: passphrase,
+	}
+
+	for _, identity := range t.identityArgs {
+		keys, err := crypt.ReadIdentities(identity)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		return os.WriteFile(t.outputArg, data, 0644)
+		options.Identities = append(options.Identities, keys...)
+	}
 
+	return crypt.Decrypt(data, options)
+}
+
+func (t *Cmd) getPassphrase() (string, error) {
+
+	if t.passphraseArg != "" {
+		return util.ResolveSecret(t.passphraseArg)
+	}
+
+	return os.Getenv(ShellyPassphraseEnvVar), nil
+}
+
+// maskSecrets returns the object with the secret fields masked. The object is returned unchanged if it
+// does not contain secrets.
+func maskSecrets(obj any) (any, error) {
+
+	if masker, ok := obj.(types.SecretMasker); ok {
+		obj = masker.MaskSecrets()
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	masked, ok, err := util.MaskJSON(data, types.SecretFields)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return obj, nil
 	}
 
-	return fmt.Errorf("format type %s is unknown", t.formatArg)
+	return types.RawObject(masked), nil
 }
 
 func (t *Cmd) WriteStderr(s string) {
@@ -120,11 +247,21 @@ func (t *Cmd) WriteStderr(s string) {
 func (t *Cmd) ReadInput() ([]byte, error) {
 	// Read input from either a file or STDIN
 
+	var data []byte
+	var err error
+
 	if t.inputArg == "" {
-		return io.ReadAll(os.Stdin)
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(t.inputArg)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	return os.ReadFile(t.inputArg)
+	// Encrypted input is decrypted transparently
+	return t.DecryptInput(data)
 }
 
 func (t *Cmd) GetHostname() string {
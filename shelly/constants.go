@@ -1,7 +1,8 @@
 package shelly
 
 const (
-	ShellyHostnameEnvVar = "SHELLY_HOST"
-	ShellyUsernameEnvVar = "SHELLY_USER"
-	ShellyPasswordEnvVar = "SHELLY_PASS"
+	ShellyHostnameEnvVar   = "SHELLY_HOST"
+	ShellyUsernameEnvVar   = "SHELLY_USER"
+	ShellyPasswordEnvVar   = "SHELLY_PASS"
+	ShellyPassphraseEnvVar = "SHELLY_PASSPHRASE"
 )
@@ -0,0 +1,74 @@
+package crypt
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type callback interface {
+	WriteObject(any) error
+}
+
+// KeyInfo is the result of keygen
+type KeyInfo struct {
+	// File the identity was written to
+	File string `json:"file" yaml:"file"`
+	// PublicKey to pass with --recipient
+	PublicKey string `json:"public_key" yaml:"public_key"`
+}
+
+func NewCmd(callback callback) *cobra.Command {
+
+	rootCmd := &cobra.Command{
+		Use:   "crypt",
+		Short: "Keys for encrypted output files",
+	}
+
+	keygenCmd := &cobra.Command{
+		Use:   "keygen <identity-file>",
+		Short: "Creates an identity file and returns its public key",
+		Long: "Creates an X25519 identity file. Output files are encrypted for it with --recipient set to the " +
+			"public key and decrypted with --identity set to the file. The file is not overwritten if it exists.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			key, err := GenerateKey()
+			if err != nil {
+				return err
+			}
+
+			publicKey := FormatPublicKey(key.PublicKey())
+
+			data := fmt.Sprintf("# created: %s\n# public key: %s\n%s\n", time.Now().UTC().Format(time.RFC3339),
+				publicKey, FormatPrivateKey(key))
+
+			f, err := os.OpenFile(args[0], os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+
+			_, err = f.WriteString(data)
+			if err != nil {
+				f.Close()
+				return err
+			}
+
+			err = f.Close()
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(&KeyInfo{
+				File:      args[0],
+				PublicKey: publicKey,
+			})
+		},
+	}
+
+	rootCmd.AddCommand(keygenCmd)
+
+	return rootCmd
+}
@@ -0,0 +1,32 @@
+package crypt
+
+const (
+	// Magic is the first line of an encrypted file
+	Magic = "shelly-manager/encrypted/v1\n"
+
+	// StanzaScrypt wraps the file key with a key derived from a passphrase
+	StanzaScrypt = "scrypt"
+	// StanzaX25519 wraps the file key for the holder of an X25519 private key
+	StanzaX25519 = "x25519"
+
+	// PublicKeyPrefix starts an encoded recipient public key
+	PublicKeyPrefix = "shelly-pub:"
+	// PrivateKeyPrefix starts an encoded identity private key
+	PrivateKeyPrefix = "shelly-key:"
+
+	// ScryptN, ScryptR and ScryptP are the scrypt cost parameters of new files
+	ScryptN = 1 << 15
+	ScryptR = 8
+	ScryptP = 1
+
+	// maxScryptN, maxScryptR, maxScryptP and maxScryptMemory limit the cost a file can ask for when it is
+	// decrypted. The memory scrypt needs is 128 * N * R bytes.
+	maxScryptN      = 1 << 22
+	maxScryptR      = 16
+	maxScryptP      = 4
+	maxScryptMemory = 1 << 30
+
+	fileKeySize = 32
+	saltSize    = 16
+	x25519Info  = "shelly-manager x25519"
+)
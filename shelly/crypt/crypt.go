@@ -0,0 +1,336 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+// IsEncrypted returns true if the data starts with Magic
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(Magic))
+}
+
+// Encrypt encrypts the data with AES-256-GCM under a random file key. The file key is wrapped once for
+// the passphrase and once for each recipient.
+func Encrypt(data []byte, options *EncryptOptions) ([]byte, error) {
+
+	if options == nil || (options.Passphrase == "" && len(options.Recipients) == 0) {
+		return nil, fmt.Errorf("a passphrase or a recipient is required to encrypt")
+	}
+
+	fileKey, err := random(fileKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &header{}
+
+	if options.Passphrase != "" {
+		s, err := scryptStanza(fileKey, options.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		h.Stanzas = append(h.Stanzas, s)
+	}
+
+	for _, recipient := range options.Recipients {
+		s, err := x25519Stanza(fileKey, recipient)
+		if err != nil {
+			return nil, err
+		}
+		h.Stanzas = append(h.Stanzas, s)
+	}
+
+	aead, err := newAEAD(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Nonce, err = random(aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	headerBytes, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []byte(Magic)
+	result = append(result, headerBytes...)
+	result = append(result, '\n')
+
+	// The header is authenticated with the payload
+	return aead.Seal(result, h.Nonce, data, result), nil
+}
+
+// Decrypt decrypts data written by Encrypt with the passphrase or one of the identities
+func Decrypt(data []byte, options *DecryptOptions) ([]byte, error) {
+
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("data is not encrypted")
+	}
+
+	if options == nil {
+		options = &DecryptOptions{}
+	}
+
+	end := bytes.IndexByte(data[len(Magic):], '\n')
+	if end < 0 {
+		return nil, fmt.Errorf("encrypted header is not valid")
+	}
+	end = len(Magic) + end + 1
+
+	h := &header{}
+	err := json.Unmarshal(data[len(Magic):end-1], h)
+	if err != nil {
+		return nil, fmt.Errorf("encrypted header is not valid: %w", err)
+	}
+
+	fileKey, err := unwrapFileKey(h, options)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(h.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted header is not valid: nonce size")
+	}
+
+	result, err := aead.Open(nil, h.Nonce, data[end:], data[:end])
+	if err != nil {
+		return nil, fmt.Errorf("encrypted data is corrupt or was modified")
+	}
+
+	return result, nil
+}
+
+// unwrapFileKey returns the file key of the first stanza that opens with the passphrase or an identity
+func unwrapFileKey(h *header, options *DecryptOptions) ([]byte, error) {
+
+	hasPassphrase := false
+	hasRecipient := false
+
+	for _, s := range h.Stanzas {
+
+		switch s.Type {
+
+		case StanzaScrypt:
+			hasPassphrase = true
+			if options.Passphrase == "" {
+				continue
+			}
+			err := checkScrypt(s)
+			if err != nil {
+				return nil, err
+			}
+			key, err := scrypt.Key([]byte(options.Passphrase), s.Salt, s.N, s.R, s.P, fileKeySize)
+			if err != nil {
+				return nil, err
+			}
+			if fileKey, err := unwrap(key, s); err == nil {
+				return fileKey, nil
+			}
+
+		case StanzaX25519:
+			hasRecipient = true
+			for _, identity := range options.Identities {
+				key, err := x25519Key(identity, s.Ephemeral, identity.PublicKey().Bytes())
+				if err != nil {
+					continue
+				}
+				if fileKey, err := unwrap(key, s); err == nil {
+					return fileKey, nil
+				}
+			}
+		}
+	}
+
+	switch {
+	case hasPassphrase && options.Passphrase != "":
+		return nil, fmt.Errorf("passphrase is not correct")
+	case hasRecipient && len(options.Identities) > 0:
+		return nil, fmt.Errorf("no identity is a recipient of the encrypted data")
+	case hasPassphrase && hasRecipient:
+		return nil, fmt.Errorf("data is encrypted; a passphrase or an identity is required")
+	case hasPassphrase:
+		return nil, fmt.Errorf("data is encrypted with a passphrase; a passphrase is required")
+	case hasRecipient:
+		return nil, fmt.Errorf("data is encrypted for recipients; an identity is required")
+	}
+
+	return nil, fmt.Errorf("encrypted header is not valid: no known stanza")
+}
+
+// checkScrypt returns an error if the scrypt parameters of the stanza are not valid or ask for more than
+// the limits so that a crafted header can not exhaust the CPU or memory before it fails to open
+func checkScrypt(s *stanza) error {
+
+	if len(s.Salt) != saltSize {
+		return fmt.Errorf("encrypted header is not valid: scrypt salt size %d", len(s.Salt))
+	}
+
+	if s.N <= 1 || s.N > maxScryptN || s.N&(s.N-1) != 0 {
+		return fmt.Errorf("encrypted header is not valid: scrypt cost %d", s.N)
+	}
+
+	if s.R < 1 || s.R > maxScryptR {
+		return fmt.Errorf("encrypted header is not valid: scrypt block size %d", s.R)
+	}
+
+	if s.P < 1 || s.P > maxScryptP {
+		return fmt.Errorf("encrypted header is not valid: scrypt parallelization %d", s.P)
+	}
+
+	if 128*s.N*s.R > maxScryptMemory {
+		return fmt.Errorf("encrypted header is not valid: scrypt needs %d bytes of memory", 128*s.N*s.R)
+	}
+
+	return nil
+}
+
+func scryptStanza(fileKey []byte, passphrase string) (*stanza, error) {
+
+	salt, err := random(saltSize)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, ScryptN, ScryptR, ScryptP, fileKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &stanza{
+		Type: StanzaScrypt,
+		Salt: salt,
+		N:    ScryptN,
+		R:    ScryptR,
+		P:    ScryptP,
+	}
+
+	return s, wrap(key, fileKey, s)
+}
+
+func x25519Stanza(fileKey []byte, recipient *ecdh.PublicKey) (*stanza, error) {
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralBytes := ephemeral.PublicKey().Bytes()
+
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(shared, ephemeralBytes, recipient.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	s := &stanza{
+		Type:      StanzaX25519,
+		Ephemeral: ephemeralBytes,
+	}
+
+	return s, wrap(key, fileKey, s)
+}
+
+// x25519Key returns the wrapping key of an X25519 stanza for the identity
+func x25519Key(identity *ecdh.PrivateKey, ephemeral, recipient []byte) ([]byte, error) {
+
+	ephemeralKey, err := ecdh.X25519().NewPublicKey(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := identity.ECDH(ephemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return deriveKey(shared, ephemeral, recipient)
+}
+
+func deriveKey(shared, ephemeral, recipient []byte) ([]byte, error) {
+
+	salt := append(append([]byte{}, ephemeral...), recipient...)
+
+	key := make([]byte, fileKeySize)
+	_, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(x25519Info)), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// wrap encrypts the file key into the stanza
+func wrap(key, fileKey []byte, s *stanza) error {
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	s.Nonce, err = random(aead.NonceSize())
+	if err != nil {
+		return err
+	}
+
+	s.Key = aead.Seal(nil, s.Nonce, fileKey, []byte(s.Type))
+	return nil
+}
+
+// unwrap decrypts the file key from the stanza
+func unwrap(key []byte, s *stanza) ([]byte, error) {
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted header is not valid: nonce size")
+	}
+
+	return aead.Open(nil, s.Nonce, s.Key, []byte(s.Type))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func random(size int) ([]byte, error) {
+
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
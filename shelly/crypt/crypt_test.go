@@ -0,0 +1,182 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var plaintext = []byte(`{"wifi":{"sta":{"ssid":"home","pass":"secret"}}}`)
+
+func TestPassphraseRoundTrip(t *testing.T) {
+
+	data, err := Encrypt(plaintext, &EncryptOptions{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsEncrypted(data) || bytes.Contains(data, []byte("secret")) {
+		t.Fatalf("data is not encrypted: %s", string(data))
+	}
+
+	result, err := Decrypt(data, &DecryptOptions{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(result, plaintext) {
+		t.Errorf("expected %s, got %s", string(plaintext), string(result))
+	}
+}
+
+func TestRecipientRoundTrip(t *testing.T) {
+
+	identity, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Encrypt(plaintext, &EncryptOptions{Recipients: []*ecdh.PublicKey{identity.PublicKey()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Decrypt(data, &DecryptOptions{Identities: []*ecdh.PrivateKey{other, identity}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(result, plaintext) {
+		t.Errorf("expected %s, got %s", string(plaintext), string(result))
+	}
+
+	_, err = Decrypt(data, &DecryptOptions{Identities: []*ecdh.PrivateKey{other}})
+	if err == nil {
+		t.Errorf("an identity that is not a recipient decrypted the data")
+	}
+}
+
+func TestWrongPassphrase(t *testing.T) {
+
+	data, err := Encrypt(plaintext, &EncryptOptions{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Decrypt(data, &DecryptOptions{Passphrase: "battery staple"})
+	if err == nil || !strings.Contains(err.Error(), "passphrase is not correct") {
+		t.Errorf("expected a wrong passphrase error, got %v", err)
+	}
+}
+
+// splitData returns the header and the payload of encrypted data
+func splitData(t *testing.T, data []byte) (*header, []byte) {
+
+	t.Helper()
+
+	rest := data[len(Magic):]
+	end := bytes.IndexByte(rest, '\n')
+
+	h := &header{}
+	err := json.Unmarshal(rest[:end], h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h, rest[end+1:]
+}
+
+// joinData returns encrypted data with the header and the payload
+func joinData(t *testing.T, h *header, payload []byte) []byte {
+
+	t.Helper()
+
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(Magic)
+	data = append(data, b...)
+	data = append(data, '\n')
+
+	return append(data, payload...)
+}
+
+func TestTamperedData(t *testing.T) {
+
+	identity, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Encrypt(plaintext, &EncryptOptions{
+		Passphrase: "correct horse",
+		Recipients: []*ecdh.PublicKey{identity.PublicKey()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, payload := splitData(t, data)
+
+	// A stanza that is removed from the header is detected by the authentication of the header
+	removed := &header{Stanzas: h.Stanzas[:1], Nonce: h.Nonce}
+
+	tamperedPayload := bytes.Clone(payload)
+	tamperedPayload[0] ^= 1
+
+	tamperedNonce := &header{Stanzas: h.Stanzas, Nonce: bytes.Clone(h.Nonce)}
+	tamperedNonce.Nonce[0] ^= 1
+
+	cases := map[string][]byte{
+		"header":  joinData(t, removed, payload),
+		"nonce":   joinData(t, tamperedNonce, payload),
+		"payload": joinData(t, h, tamperedPayload),
+	}
+
+	for name, tampered := range cases {
+		_, err = Decrypt(tampered, &DecryptOptions{Passphrase: "correct horse"})
+		if err == nil {
+			t.Errorf("tampered %s was decrypted", name)
+		}
+	}
+}
+
+func TestScryptLimits(t *testing.T) {
+
+	data, err := Encrypt(plaintext, &EncryptOptions{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, payload := splitData(t, data)
+	s := h.Stanzas[0]
+
+	cases := map[string]func(s *stanza){
+		"n":      func(s *stanza) { s.N = maxScryptN * 2 },
+		"r":      func(s *stanza) { s.R = 1 << 20 },
+		"p":      func(s *stanza) { s.P = 1 << 20 },
+		"memory": func(s *stanza) { s.N = maxScryptN; s.R = maxScryptR },
+		"salt":   func(s *stanza) { s.Salt = s.Salt[:4] },
+	}
+
+	for name, change := range cases {
+
+		crafted := *s
+		change(&crafted)
+
+		_, err = Decrypt(joinData(t, &header{Stanzas: []*stanza{&crafted}, Nonce: h.Nonce}, payload),
+			&DecryptOptions{Passphrase: "correct horse"})
+		if err == nil || !strings.Contains(err.Error(), "encrypted header is not valid") {
+			t.Errorf("%s: expected an invalid header error, got %v", name, err)
+		}
+	}
+}
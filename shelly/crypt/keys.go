@@ -0,0 +1,132 @@
+package crypt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GenerateKey returns a new X25519 identity
+func GenerateKey() (*ecdh.PrivateKey, error) {
+	return ecdh.X25519().GenerateKey(rand.Reader)
+}
+
+// FormatPublicKey returns the recipient string of the public key
+func FormatPublicKey(key *ecdh.PublicKey) string {
+	return PublicKeyPrefix + base64.RawURLEncoding.EncodeToString(key.Bytes())
+}
+
+// FormatPrivateKey returns the identity string of the private key
+func FormatPrivateKey(key *ecdh.PrivateKey) string {
+	return PrivateKeyPrefix + base64.RawURLEncoding.EncodeToString(key.Bytes())
+}
+
+// ParsePublicKey parses a recipient string returned by FormatPublicKey
+func ParsePublicKey(s string) (*ecdh.PublicKey, error) {
+
+	if !strings.HasPrefix(s, PublicKeyPrefix) {
+		return nil, fmt.Errorf("recipient must start with %s", PublicKeyPrefix)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(s, PublicKeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("recipient is not valid: %w", err)
+	}
+
+	return ecdh.X25519().NewPublicKey(b)
+}
+
+// ParsePrivateKey parses an identity string returned by FormatPrivateKey
+func ParsePrivateKey(s string) (*ecdh.PrivateKey, error) {
+
+	if !strings.HasPrefix(s, PrivateKeyPrefix) {
+		return nil, fmt.Errorf("identity must start with %s", PrivateKeyPrefix)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(s, PrivateKeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("identity is not valid: %w", err)
+	}
+
+	return ecdh.X25519().NewPrivateKey(b)
+}
+
+// ReadRecipients returns the public key of a recipient string or the public keys of a file with recipient
+// strings
+func ReadRecipients(value string) ([]*ecdh.PublicKey, error) {
+
+	if strings.HasPrefix(value, PublicKeyPrefix) {
+		key, err := ParsePublicKey(value)
+		if err != nil {
+			return nil, err
+		}
+		return []*ecdh.PublicKey{key}, nil
+	}
+
+	lines, err := readKeyFile(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*ecdh.PublicKey
+	for _, line := range lines {
+		key, err := ParsePublicKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s :: %w", value, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// ReadIdentities returns the private keys of an identity file written by keygen
+func ReadIdentities(filename string) ([]*ecdh.PrivateKey, error) {
+
+	lines, err := readKeyFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*ecdh.PrivateKey
+	for _, line := range lines {
+		key, err := ParsePrivateKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s :: %w", filename, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// readKeyFile returns the lines of the file that are not empty or comments starting with #
+func readKeyFile(filename string) ([]string, error) {
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%s has no keys", filename)
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,43 @@
+package crypt
+
+import (
+	"crypto/ecdh"
+)
+
+// EncryptOptions selects who can decrypt. At least a passphrase or one recipient is required.
+type EncryptOptions struct {
+	// Passphrase from which the key is derived with scrypt
+	Passphrase string
+	// Recipients are the public keys of the holders that can decrypt
+	Recipients []*ecdh.PublicKey
+}
+
+// DecryptOptions are the secrets tried to decrypt
+type DecryptOptions struct {
+	// Passphrase used for scrypt stanzas
+	Passphrase string
+	// Identities are the private keys used for X25519 stanzas
+	Identities []*ecdh.PrivateKey
+}
+
+// header follows Magic and is a single line of JSON
+type header struct {
+	// Stanzas each wrap the file key for a passphrase or a recipient
+	Stanzas []*stanza `json:"stanzas"`
+	// Nonce of the payload
+	Nonce []byte `json:"nonce"`
+}
+
+// stanza is the file key wrapped with AES-GCM
+type stanza struct {
+	Type string `json:"type"`
+	// Salt, N, R and P are the scrypt parameters
+	Salt []byte `json:"salt,omitempty"`
+	N    int    `json:"n,omitempty"`
+	R    int    `json:"r,omitempty"`
+	P    int    `json:"p,omitempty"`
+	// Ephemeral is the ephemeral X25519 public key
+	Ephemeral []byte `json:"ephemeral,omitempty"`
+	Nonce     []byte `json:"nonce"`
+	Key       []byte `json:"key"`
+}
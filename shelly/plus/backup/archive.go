@@ -0,0 +1,247 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Write writes the archive as a gzip compressed tar. The manifest is written first and lists the
+// other files.
+func Write(w io.Writer, archive *Archive) error {
+
+	files := make(map[string][]byte)
+
+	add := func(name string, v any) error {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("%s :: %w", name, err)
+		}
+		files[name] = b
+		return nil
+	}
+
+	err := add(DeviceInfoFile, archive.DeviceInfo)
+	if err != nil {
+		return err
+	}
+
+	files[ConfigFile] = archive.Config
+
+	err = add(WebhooksFile, archive.Webhooks)
+	if err != nil {
+		return err
+	}
+
+	err = add(SchedulesFile, archive.Schedules)
+	if err != nil {
+		return err
+	}
+
+	err = add(KVSFile, archive.KVS)
+	if err != nil {
+		return err
+	}
+
+	err = add(CertsFile, archive.Certs)
+	if err != nil {
+		return err
+	}
+
+	for _, script := range archive.Scripts {
+		name := path.Join(ScriptsDir, strconv.Itoa(script.Info.ID))
+		err = add(name+".json", script.Info)
+		if err != nil {
+			return err
+		}
+		files[name+".js"] = []byte(script.Code)
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive.Manifest.Files = names
+
+	manifest, err := json.MarshalIndent(archive.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	write := func(name string, data []byte) error {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: archive.Manifest.Created,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(data)
+		return err
+	}
+
+	err = write(ManifestFile, manifest)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		err = write(name, files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+// Read reads an archive written by Write
+func Read(r io.Reader) (*Archive, error) {
+
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("archive is not valid: %w", err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+
+	files := make(map[string][]byte)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("archive is not valid: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+
+		files[path.Clean(header.Name)] = data
+	}
+
+	archive := &Archive{}
+
+	get := func(name string, v any) error {
+		data, ok := files[name]
+		if !ok {
+			return fmt.Errorf("archive is missing %s", name)
+		}
+		err := json.Unmarshal(data, v)
+		if err != nil {
+			return fmt.Errorf("%s :: %w", name, err)
+		}
+		return nil
+	}
+
+	err = get(ManifestFile, &archive.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if archive.Manifest.Format > FormatVersion {
+		return nil, fmt.Errorf("archive format %d is not supported; upgrade to restore it", archive.Manifest.Format)
+	}
+
+	err = get(DeviceInfoFile, &archive.DeviceInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	config, ok := files[ConfigFile]
+	if !ok {
+		return nil, fmt.Errorf("archive is missing %s", ConfigFile)
+	}
+	archive.Config = config
+
+	err = get(WebhooksFile, &archive.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	err = get(SchedulesFile, &archive.Schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	err = get(KVSFile, &archive.KVS)
+	if err != nil {
+		return nil, err
+	}
+
+	err = get(CertsFile, &archive.Certs)
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range files {
+
+		dir, file := path.Split(name)
+		if dir != ScriptsDir+"/" || !strings.HasSuffix(file, ".json") {
+			continue
+		}
+
+		script := &Script{}
+		err = get(name, &script.Info)
+		if err != nil {
+			return nil, err
+		}
+
+		code, ok := files[strings.TrimSuffix(name, ".json")+".js"]
+		if !ok {
+			return nil, fmt.Errorf("archive is missing the code of %s", name)
+		}
+		script.Code = string(code)
+
+		archive.Scripts = append(archive.Scripts, script)
+	}
+
+	sort.Slice(archive.Scripts, func(i, j int) bool {
+		return archive.Scripts[i].Info.ID < archive.Scripts[j].Info.ID
+	})
+
+	return archive, nil
+}
+
+// newManifest returns the manifest of a backup of the device taken now
+func newManifest(hostname string, info *DeviceInfo) *Manifest {
+	return &Manifest{
+		Format:     FormatVersion,
+		Created:    time.Now().UTC(),
+		Hostname:   hostname,
+		DeviceID:   info.ID,
+		Model:      info.Model,
+		App:        info.App,
+		Generation: info.Generation,
+		Version:    info.Version,
+		FirmwareID: info.FirmwareID,
+	}
+}
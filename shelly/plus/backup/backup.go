@@ -0,0 +1,113 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/kvs"
+)
+
+// Backup reads everything needed to restore the device: device info, config, webhooks, scheduled jobs,
+// scripts with their code, the Key-Value Store and the certificate metadata
+func Backup(ctx context.Context, device Device, hostname string) (*Archive, error) {
+
+	info, err := device.Shelly().GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := device.Shelly().Call(ctx, "Shelly.GetConfig", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{
+		Manifest:   newManifest(hostname, info),
+		DeviceInfo: info,
+		Config:     config,
+	}
+
+	archive.Manifest.MissingSecrets, err = MissingSecrets(config)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := device.WebHook().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("webhooks :: %w", err)
+	}
+
+	for i := range webhooks.Hooks {
+		archive.Webhooks = append(archive.Webhooks, &webhooks.Hooks[i])
+	}
+
+	jobs, err := device.Schedule().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("schedules :: %w", err)
+	}
+	archive.Schedules = jobs.Jobs
+
+	scripts, err := device.Script().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("scripts :: %w", err)
+	}
+
+	for _, info := range scripts.Scripts {
+
+		code, err := device.Script().GetCode(ctx, info.ID)
+		if err != nil {
+			return nil, fmt.Errorf("script %d :: %w", info.ID, err)
+		}
+
+		archive.Scripts = append(archive.Scripts, &Script{
+			Info: info,
+			Code: code,
+		})
+	}
+
+	items, err := device.KVS().GetMany(ctx, kvs.MatchAll)
+	if err != nil {
+		return nil, fmt.Errorf("kvs :: %w", err)
+	}
+
+	archive.KVS = make(map[string]any)
+	for key, item := range items.Items {
+		archive.KVS[key] = item.Value
+	}
+
+	shellyConfig, err := device.Shelly().GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	archive.Certs = &Certs{
+		Note:  CertsNote,
+		Certs: []*Cert{},
+	}
+
+	if shellyConfig.Mqtt != nil {
+		archive.Certs.Certs = append(archive.Certs.Certs, &Cert{
+			Component:     "mqtt",
+			SslCa:         shellyConfig.Mqtt.SslCa,
+			UseClientCert: shellyConfig.Mqtt.UseClientCert,
+		})
+	}
+
+	if shellyConfig.Websocket != nil {
+		archive.Certs.Certs = append(archive.Certs.Certs, &Cert{
+			Component: "ws",
+			SslCa:     shellyConfig.Websocket.SslCa,
+		})
+	}
+
+	for _, hook := range archive.Webhooks {
+		if hook.SslCa != nil && hook.ID != nil {
+			archive.Certs.Certs = append(archive.Certs.Certs, &Cert{
+				Component: fmt.Sprintf("webhook:%d", *hook.ID),
+				SslCa:     hook.SslCa,
+			})
+		}
+	}
+
+	return archive, nil
+}
@@ -0,0 +1,226 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// identityFields keep the value of the target when a config is retargeted. The first element is the
+// component and the second the path of the field within it.
+var identityFields = [][2]string{
+	{"sys", "device.mac"},
+	{"sys", "device.fw_id"},
+	{"mqtt", "client_id"},
+}
+
+// nameFields keep the value of the target when a config is cloned and the value of the source when a
+// device is replaced
+var nameFields = [][2]string{
+	{"sys", "device.name"},
+}
+
+// addressComponents are the network components with a static IPv4 config
+var addressComponents = [][2]string{
+	{"wifi", "sta"},
+	{"wifi", "sta1"},
+	{"eth", ""},
+}
+
+// addressFields of the address components keep the value of the target when a config is cloned so it
+// keeps its own address. They keep the value of the source when a device is replaced.
+var addressFields = []string{"ipv4mode", "ip", "netmask", "gw", "nameserver"}
+
+// Clone reads the config, webhooks, scheduled jobs and scripts of the source device. The Key-Value Store
+// and certificate metadata are not part of a clone. Use Retarget before the archive is restored onto
+// another device.
+func Clone(ctx context.Context, source Device, hostname string) (*Archive, error) {
+
+	archive, err := Backup(ctx, source, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	archive.KVS = nil
+	archive.Certs = nil
+
+	return archive, nil
+}
+
+// Retarget returns the archive prepared for the target device. The MAC, firmware id and MQTT client id
+// of the source are replaced with the values in the current config of the target so they are not changed.
+// Unless options.Replace is set the same is done for the name and the static address config. The id and
+// MAC of the source device are replaced with those of the target in all strings of the config, webhooks,
+// scheduled jobs and script code, for example in MQTT topic prefixes and webhook URLs.
+func Retarget(archive *Archive, target *DeviceInfo, targetConfig json.RawMessage, options *RetargetOptions) (*Archive, error) {
+
+	if options == nil {
+		options = &RetargetOptions{}
+	}
+
+	var config map[string]any
+	err := json.Unmarshal(archive.Config, &config)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", ConfigFile, err)
+	}
+
+	var current map[string]any
+	err = json.Unmarshal(targetConfig, &current)
+	if err != nil {
+		return nil, fmt.Errorf("config of the target :: %w", err)
+	}
+
+	for _, field := range identityFields {
+		keepField(config, current, field[0], field[1])
+	}
+
+	if !options.Replace {
+
+		for _, field := range nameFields {
+			keepField(config, current, field[0], field[1])
+		}
+
+		for _, component := range addressComponents {
+			for _, field := range addressFields {
+				keepField(config, current, component[0], joinPath(component[1], field))
+			}
+		}
+	}
+
+	replacer := newIDReplacer(archive.DeviceInfo, target)
+
+	result := &Archive{
+		Manifest:   archive.Manifest,
+		DeviceInfo: archive.DeviceInfo,
+		KVS:        archive.KVS,
+		Certs:      archive.Certs,
+	}
+
+	result.Config, err = json.Marshal(rewriteStrings(config, replacer))
+	if err != nil {
+		return nil, err
+	}
+
+	err = rewriteJSON(archive.Webhooks, &result.Webhooks, replacer)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", WebhooksFile, err)
+	}
+
+	err = rewriteJSON(archive.Schedules, &result.Schedules, replacer)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", SchedulesFile, err)
+	}
+
+	for _, script := range archive.Scripts {
+		result.Scripts = append(result.Scripts, &Script{
+			Info: script.Info,
+			Code: replacer.Replace(script.Code),
+		})
+	}
+
+	return result, nil
+}
+
+// newIDReplacer replaces the id and the MAC of the source with those of the target. The MAC is replaced
+// in lower and upper case.
+func newIDReplacer(source, target *DeviceInfo) *strings.Replacer {
+
+	var pairs []string
+
+	add := func(from, to string) {
+		if from != "" && to != "" && from != to {
+			pairs = append(pairs, from, to)
+		}
+	}
+
+	add(source.ID, target.ID)
+	add(strings.ToLower(source.MAC), strings.ToLower(target.MAC))
+	add(strings.ToUpper(source.MAC), strings.ToUpper(target.MAC))
+
+	return strings.NewReplacer(pairs...)
+}
+
+// rewriteJSON copies from into to through JSON and replaces the strings on the way
+func rewriteJSON(from, to any, replacer *strings.Replacer) error {
+
+	b, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	var generic any
+	err = json.Unmarshal(b, &generic)
+	if err != nil {
+		return err
+	}
+
+	b, err = json.Marshal(rewriteStrings(generic, replacer))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, to)
+}
+
+// rewriteStrings returns the value with the replacer applied to all strings at any depth
+func rewriteStrings(value any, replacer *strings.Replacer) any {
+
+	switch v := value.(type) {
+
+	case string:
+		return replacer.Replace(v)
+
+	case map[string]any:
+		for key, item := range v {
+			v[key] = rewriteStrings(item, replacer)
+		}
+		return v
+
+	case []any:
+		for i, item := range v {
+			v[i] = rewriteStrings(item, replacer)
+		}
+		return v
+	}
+
+	return value
+}
+
+// keepField sets the field at the dot separated path of the component to the value in the current
+// config. The field is removed if the current config does not have it.
+func keepField(config, current map[string]any, key, path string) {
+
+	parts := strings.Split(path, ".")
+	last := parts[len(parts)-1]
+
+	m, ok := config[key].(map[string]any)
+	if !ok {
+		return
+	}
+
+	from, _ := current[key].(map[string]any)
+
+	for _, part := range parts[:len(parts)-1] {
+		m, ok = m[part].(map[string]any)
+		if !ok {
+			return
+		}
+		from, _ = from[part].(map[string]any)
+	}
+
+	value, ok := from[last]
+	if !ok {
+		delete(m, last)
+		return
+	}
+
+	m[last] = value
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
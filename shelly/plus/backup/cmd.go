@@ -0,0 +1,524 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/inventory"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+)
+
+const secretArgUsage = "password the device does not return as path=value, for example wifi.sta.pass=env:WIFI_PASS; may be repeated"
+
+type callback interface {
+	GetHostname() string
+	WriteObject(any) error
+	WriteStderr(string)
+	EncryptOutput([]byte) ([]byte, error)
+	DecryptInput([]byte) ([]byte, error)
+	Device() (Device, error)
+	DeviceFor(hostname string) (Device, error)
+	ShellyFor(hostname string) (*shelly.Client, func(), error)
+}
+
+// NewBackupCmd returns the command that writes a backup archive of the device to a file or the backup store
+func NewBackupCmd(callback callback) *cobra.Command {
+
+	var dirArg string
+	var inventoryDirArg string
+
+	backupCmd := &cobra.Command{
+		Use:   "backup [file]",
+		Short: "Writes a backup archive of the device",
+		Long: "Writes a gzip compressed tar with the device info, config, webhooks, scheduled jobs, scripts with " +
+			"their code, the Key-Value Store and certificate metadata. The manifest records the model, firmware " +
+			"and time of the backup and is returned. Certificates and keys can not be read from the device. The " +
+			"archive is encrypted with --encrypt or --recipient. Without a file the archive is saved in the backup " +
+			"store where replace finds it. The device is recorded in the inventory.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			device, err := callback.Device()
+			if err != nil {
+				return err
+			}
+
+			archive, err := Backup(cmd.Context(), device, callback.GetHostname())
+			if err != nil {
+				return err
+			}
+
+			var buf bytes.Buffer
+			err = Write(&buf, archive)
+			if err != nil {
+				return err
+			}
+
+			// The archive is written as is unless encryption is selected
+			data, err := callback.EncryptOutput(buf.Bytes())
+			if err != nil {
+				return err
+			}
+
+			var file string
+
+			if len(args) > 0 {
+
+				file = args[0]
+				err = os.WriteFile(file, data, 0600)
+				if err != nil {
+					return err
+				}
+
+			} else {
+
+				store, err := NewStore(dirArg)
+				if err != nil {
+					return err
+				}
+
+				file, err = store.Save(archive.Manifest.DeviceID, archive.Manifest.Created, data)
+				if err != nil {
+					return err
+				}
+
+				callback.WriteStderr("saved " + file)
+			}
+
+			err = recordBackup(inventoryDirArg, archive, file)
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(archive.Manifest)
+		},
+	}
+
+	backupCmd.PersistentFlags().StringVar(&dirArg, "dir", "", "backup store directory; default is shelly-manager/backups in the user config dir")
+	backupCmd.PersistentFlags().StringVar(&inventoryDirArg, "inventory-dir", "", "inventory directory; default is shelly-manager in the user config dir")
+
+	return backupCmd
+}
+
+// NewRestoreCmd returns the command that restores a backup archive to the device
+func NewRestoreCmd(callback callback) *cobra.Command {
+
+	var forceArg bool
+	var autorebootArg bool
+	var secretArgs []string
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Restores a backup archive to the device",
+		Long: "Restores a backup archive written by backup. The Key-Value Store, config, scripts, scheduled jobs " +
+			"and webhooks are written in that order and Wi-Fi and Ethernet changes are written last. Existing " +
+			"scripts, scheduled jobs and webhooks are replaced. The restore is refused if the archive is from a " +
+			"different model unless --force is set. Encrypted archives are decrypted with --passphrase or --identity. " +
+			"Passwords the device does not return, such as wifi.sta.pass, are set with --set; a Wi-Fi or MQTT " +
+			"config without its password is left unchanged.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			secrets, err := ParseSecrets(secretArgs)
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			data, err = callback.DecryptInput(data)
+			if err != nil {
+				return err
+			}
+
+			archive, err := Read(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+
+			device, err := callback.Device()
+			if err != nil {
+				return err
+			}
+
+			report, restoreErr := Restore(cmd.Context(), device, archive, &RestoreOptions{
+				Force:    forceArg,
+				Hostname: callback.GetHostname(),
+				Dial:     callback.ShellyFor,
+				Secrets:  secrets,
+			})
+			if report == nil {
+				return restoreErr
+			}
+
+			err = afterRestore(cmd, callback, device, report, autorebootArg && restoreErr == nil)
+			if err != nil {
+				return err
+			}
+
+			err = callback.WriteObject(report)
+			if err != nil {
+				return err
+			}
+
+			return restoreErr
+		},
+	}
+
+	restoreCmd.PersistentFlags().BoolVar(&forceArg, "force", false, "restore even if the archive is from a different model or the device was changed since the plan was made")
+	restoreCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+	restoreCmd.PersistentFlags().StringArrayVar(&secretArgs, "set", nil, secretArgUsage)
+
+	return restoreCmd
+}
+
+// NewCloneCmd returns the command that configures a device like another device
+func NewCloneCmd(callback callback) *cobra.Command {
+
+	var fromArg string
+	var toArg string
+	var dryRunArg bool
+	var forceArg bool
+	var autorebootArg bool
+	var secretArgs []string
+
+	cloneCmd := &cobra.Command{
+		Use:   "clone",
+		Short: "Configures a device like another device",
+		Long: "Reads the config, webhooks, scheduled jobs and scripts of the source device and applies them to the " +
+			"target. The name, MAC, firmware id, MQTT client id and static addresses of the target are kept and the " +
+			"id and MAC of the source are replaced with those of the target, for example in MQTT topic prefixes. " +
+			"The config changes are shown before they are applied; --dry-run only shows them. Existing scripts, " +
+			"scheduled jobs and webhooks of the target are replaced. Passwords the device does not return, such as " +
+			"wifi.sta.pass, are set with --set; a Wi-Fi or MQTT config without its password is left unchanged.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if fromArg == "" {
+				fromArg = callback.GetHostname()
+			}
+
+			if fromArg == "" || toArg == "" {
+				return fmt.Errorf("--from and --to are required")
+			}
+
+			secrets, err := ParseSecrets(secretArgs)
+			if err != nil {
+				return err
+			}
+
+			source, err := callback.DeviceFor(fromArg)
+			if err != nil {
+				return err
+			}
+			defer source.Close()
+
+			archive, err := Clone(cmd.Context(), source, fromArg)
+			if err != nil {
+				return fmt.Errorf("%s :: %w", fromArg, err)
+			}
+
+			target, err := callback.DeviceFor(toArg)
+			if err != nil {
+				return err
+			}
+			defer target.Close()
+
+			info, err := target.Shelly().GetDeviceInfo(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("%s :: %w", toArg, err)
+			}
+
+			targetConfig, err := target.Shelly().Call(cmd.Context(), "Shelly.GetConfig", nil)
+			if err != nil {
+				return fmt.Errorf("%s :: %w", toArg, err)
+			}
+
+			archive, err = Retarget(archive, info, targetConfig, nil)
+			if err != nil {
+				return err
+			}
+
+			plan, err := PlanRestore(cmd.Context(), target, archive, secrets)
+			if err != nil {
+				return err
+			}
+
+			_, warnings, err := restoreConfig(archive, secrets)
+			if err != nil {
+				return err
+			}
+
+			preview := fmt.Sprintf("%s\nscripts: %d, schedules: %d, webhooks: %d replace those of %s",
+				shelly.FormatPlan(plan), len(archive.Scripts), len(archive.Schedules), len(archive.Webhooks), toArg)
+
+			if dryRunArg {
+				for _, warning := range warnings {
+					callback.WriteStderr("warning: " + warning)
+				}
+				callback.WriteStderr(preview)
+				return nil
+			}
+
+			callback.WriteStderr(preview)
+
+			report, restoreErr := Restore(cmd.Context(), target, archive, &RestoreOptions{
+				Force:    forceArg,
+				Hostname: toArg,
+				Dial:     callback.ShellyFor,
+				Plan:     plan,
+				Secrets:  secrets,
+			})
+			if report == nil {
+				return restoreErr
+			}
+
+			err = afterRestore(cmd, callback, target, report, autorebootArg && restoreErr == nil)
+			if err != nil {
+				return err
+			}
+
+			err = callback.WriteObject(report)
+			if err != nil {
+				return err
+			}
+
+			return restoreErr
+		},
+	}
+
+	cloneCmd.PersistentFlags().StringVar(&fromArg, "from", "", "hostname of the source device; default is the hostname")
+	cloneCmd.PersistentFlags().StringVar(&toArg, "to", "", "hostname of the target device")
+	cloneCmd.PersistentFlags().BoolVar(&dryRunArg, "dry-run", false, "only show the changes")
+	cloneCmd.PersistentFlags().BoolVar(&forceArg, "force", false, "clone even if the devices are different models or the target was changed since the plan was made")
+	cloneCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+	cloneCmd.PersistentFlags().StringArrayVar(&secretArgs, "set", nil, secretArgUsage)
+
+	return cloneCmd
+}
+
+// NewReplaceCmd returns the command that restores the latest backup of a failed device onto its replacement
+func NewReplaceCmd(callback callback) *cobra.Command {
+
+	var oldIDArg string
+	var newHostArg string
+	var dirArg string
+	var inventoryDirArg string
+	var dryRunArg bool
+	var forceArg bool
+	var autorebootArg bool
+	var secretArgs []string
+
+	replaceCmd := &cobra.Command{
+		Use:   "replace",
+		Short: "Restores the latest backup of a failed device onto its replacement",
+		Long: "Restores the latest backup of the old device from the backup store onto the new device. The name and " +
+			"static addresses of the old device are kept and its id and MAC are replaced with those of the new " +
+			"device, for example in MQTT topic prefixes and webhook URLs. The config changes are shown before they " +
+			"are applied; --dry-run only shows them. The inventory records the replacement and a checklist of " +
+			"external references that may still point at the old device is returned. Passwords the device does not " +
+			"return, such as wifi.sta.pass, are set with --set; a Wi-Fi or MQTT config without its password is left " +
+			"unchanged.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if newHostArg == "" {
+				newHostArg = callback.GetHostname()
+			}
+
+			if oldIDArg == "" || newHostArg == "" {
+				return fmt.Errorf("--old-id and --new-host are required")
+			}
+
+			secrets, err := ParseSecrets(secretArgs)
+			if err != nil {
+				return err
+			}
+
+			inventoryStore, err := inventory.New(&inventory.Config{Dir: inventoryDirArg})
+			if err != nil {
+				return err
+			}
+
+			file, err := latestBackup(dirArg, inventoryStore, oldIDArg)
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			data, err = callback.DecryptInput(data)
+			if err != nil {
+				return err
+			}
+
+			archive, err := Read(bytes.NewReader(data))
+			if err != nil {
+				return fmt.Errorf("%s :: %w", file, err)
+			}
+
+			target, err := callback.DeviceFor(newHostArg)
+			if err != nil {
+				return err
+			}
+			defer target.Close()
+
+			info, err := target.Shelly().GetDeviceInfo(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("%s :: %w", newHostArg, err)
+			}
+
+			if info.ID == oldIDArg {
+				return fmt.Errorf("%s is the old device %s", newHostArg, oldIDArg)
+			}
+
+			targetConfig, err := target.Shelly().Call(cmd.Context(), "Shelly.GetConfig", nil)
+			if err != nil {
+				return fmt.Errorf("%s :: %w", newHostArg, err)
+			}
+
+			retargeted, err := Retarget(archive, info, targetConfig, &RetargetOptions{Replace: true})
+			if err != nil {
+				return err
+			}
+
+			plan, err := PlanRestore(cmd.Context(), target, retargeted, secrets)
+			if err != nil {
+				return err
+			}
+
+			_, warnings, err := restoreConfig(retargeted, secrets)
+			if err != nil {
+				return err
+			}
+
+			replaceReport := &ReplaceReport{
+				OldID:     oldIDArg,
+				NewID:     info.ID,
+				Backup:    file,
+				Checklist: Checklist(archive, info, newHostArg, secrets),
+			}
+
+			preview := fmt.Sprintf("%s\nbackup %s of %s; scripts: %d, schedules: %d, webhooks: %d replace those of %s",
+				shelly.FormatPlan(plan), file, oldIDArg, len(retargeted.Scripts), len(retargeted.Schedules),
+				len(retargeted.Webhooks), newHostArg)
+
+			if dryRunArg {
+				for _, warning := range warnings {
+					callback.WriteStderr("warning: " + warning)
+				}
+				callback.WriteStderr(preview)
+				return callback.WriteObject(replaceReport)
+			}
+
+			callback.WriteStderr(preview)
+
+			report, restoreErr := Restore(cmd.Context(), target, retargeted, &RestoreOptions{
+				Force:    forceArg,
+				Hostname: newHostArg,
+				Dial:     callback.ShellyFor,
+				Plan:     plan,
+				Secrets:  secrets,
+			})
+			if report == nil {
+				return restoreErr
+			}
+			replaceReport.Restore = report
+
+			hostname := newHostArg
+			if report.Config != nil && report.Config.Network != nil && report.Config.Network.Verified {
+				hostname = report.Config.Network.Address
+			}
+
+			if restoreErr == nil {
+				err = recordReplace(inventoryStore, archive, info, hostname)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = afterRestore(cmd, callback, target, report, autorebootArg && restoreErr == nil)
+			if err != nil {
+				return err
+			}
+
+			callback.WriteStderr("check the external references to the old device:")
+			for _, item := range replaceReport.Checklist {
+				callback.WriteStderr("[ ] " + item)
+			}
+
+			err = callback.WriteObject(replaceReport)
+			if err != nil {
+				return err
+			}
+
+			return restoreErr
+		},
+	}
+
+	replaceCmd.PersistentFlags().StringVar(&oldIDArg, "old-id", "", "device id of the failed device")
+	replaceCmd.PersistentFlags().StringVar(&newHostArg, "new-host", "", "hostname of the replacement; default is the hostname")
+	replaceCmd.PersistentFlags().StringVar(&dirArg, "dir", "", "backup store directory; default is shelly-manager/backups in the user config dir")
+	replaceCmd.PersistentFlags().StringVar(&inventoryDirArg, "inventory-dir", "", "inventory directory; default is shelly-manager in the user config dir")
+	replaceCmd.PersistentFlags().BoolVar(&dryRunArg, "dry-run", false, "only show the changes and the checklist")
+	replaceCmd.PersistentFlags().BoolVar(&forceArg, "force", false, "replace even if the devices are different models or the new device was changed since the plan was made")
+	replaceCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+	replaceCmd.PersistentFlags().StringArrayVar(&secretArgs, "set", nil, secretArgUsage)
+
+	return replaceCmd
+}
+
+// afterRestore shows the warnings and reboots the device if required
+func afterRestore(cmd *cobra.Command, callback callback, device Device, report *RestoreReport, autoreboot bool) error {
+
+	for _, warning := range report.Warnings {
+		callback.WriteStderr("warning: " + warning)
+	}
+
+	var network *shelly.ShellyNetworkReport
+	if report.Config != nil {
+		network = report.Config.Network
+	}
+
+	moved := network != nil && network.Verified && network.Address != network.Previous
+	if moved {
+		callback.WriteStderr(fmt.Sprintf("device moved to %s (found with %s)", network.Address, network.Method))
+	}
+
+	if report.RestartRequired {
+		if autoreboot {
+			callback.WriteStderr("reboot is required; rebooting ...")
+			err := reboot(cmd, callback, device, network, moved)
+			if err != nil {
+				return err
+			}
+		} else {
+			callback.WriteStderr("reboot is required!")
+		}
+	}
+
+	return nil
+}
+
+// reboot reboots the device at its new address if it moved
+func reboot(cmd *cobra.Command, callback callback, device Device, network *shelly.ShellyNetworkReport, moved bool) error {
+
+	if !moved {
+		return device.Shelly().Reboot(cmd.Context())
+	}
+
+	client, closer, err := callback.ShellyFor(network.Address)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	return client.Reboot(cmd.Context())
+}
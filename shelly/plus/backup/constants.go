@@ -0,0 +1,36 @@
+package backup
+
+const (
+	// FormatVersion version of the archive layout. Archives with a newer version are refused.
+	FormatVersion = 1
+
+	// ManifestFile name of the manifest in the archive
+	ManifestFile = "manifest.json"
+	// DeviceInfoFile name of the result of Shelly.GetDeviceInfo in the archive
+	DeviceInfoFile = "device_info.json"
+	// ConfigFile name of the result of Shelly.GetConfig in the archive
+	ConfigFile = "config.json"
+	// WebhooksFile name of the webhooks in the archive
+	WebhooksFile = "webhooks.json"
+	// SchedulesFile name of the scheduled jobs in the archive
+	SchedulesFile = "schedules.json"
+	// KVSFile name of the Key-Value Store contents in the archive
+	KVSFile = "kvs.json"
+	// CertsFile name of the certificate metadata in the archive
+	CertsFile = "certs.json"
+	// ScriptsDir directory of the scripts in the archive. Each script has <id>.json with its
+	// config and <id>.js with its code.
+	ScriptsDir = "scripts"
+
+	// UserCA is the value of ssl_ca that makes the device verify the server with the uploaded user CA
+	UserCA = "user_ca.pem"
+
+	// StoreTimeFormat names the backups in the store so they sort by age
+	StoreTimeFormat = "20060102T150405Z"
+	// StoreFileExt extension of the backups in the store
+	StoreFileExt = ".tar.gz"
+
+	// CertsNote is recorded in the certificate metadata. The device does not return uploaded certificates
+	// or keys so they can not be part of the archive.
+	CertsNote = "certificates and keys can not be read from the device; upload them again after a restore (see pki provision)"
+)
@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Checklist returns the external references that may still point at the old device after it was
+// replaced by the target. Hostname is the address of the replacement. Secrets are the passwords given for
+// the restore; the passwords that are missing from the backup and not given must be set by hand.
+func Checklist(archive *Archive, target *DeviceInfo, hostname string, secrets map[string]string) []string {
+
+	old := archive.DeviceInfo
+
+	var config map[string]any
+	_ = json.Unmarshal(archive.Config, &config)
+
+	replacer := newIDReplacer(old, target)
+
+	checklist := []string{
+		fmt.Sprintf("DHCP reservations, firewall rules and network access lists for MAC %s; the new MAC is %s", old.MAC, target.MAC),
+		fmt.Sprintf("home automation integrations and dashboards that use device id %s; the new id is %s", old.ID, target.ID),
+		"webhooks and scripts on other devices and services that call the old device",
+	}
+
+	if archive.Manifest.Hostname != "" && archive.Manifest.Hostname != hostname {
+		checklist = append(checklist, fmt.Sprintf("DNS records and monitoring for %s; the replacement is at %s", archive.Manifest.Hostname, hostname))
+	}
+
+	missing, _ := MissingSecrets(archive.Config)
+	for _, path := range missing {
+		if _, ok := secrets[path]; !ok {
+			checklist = append(checklist, fmt.Sprintf("%s is not part of the backup and was not restored; set it on the replacement or replace again with --set %s=env:NAME", path, path))
+		}
+	}
+
+	if enabled(config, "mqtt") {
+		prefix, _ := getValue(config, "mqtt", "topic_prefix").(string)
+		if prefix != "" && replacer.Replace(prefix) != prefix {
+			checklist = append(checklist, fmt.Sprintf("MQTT subscribers and retained messages of topic prefix %s; the new prefix is %s", prefix, replacer.Replace(prefix)))
+		} else {
+			checklist = append(checklist, fmt.Sprintf("MQTT broker ACLs and sessions for client id %s", old.ID))
+		}
+		if user, _ := getValue(config, "mqtt", "user").(string); user != "" {
+			checklist = append(checklist, fmt.Sprintf("MQTT broker credentials of user %s if they are bound to the client id or certificate of %s", user, old.ID))
+		}
+	}
+
+	if enabled(config, "ws") {
+		checklist = append(checklist, fmt.Sprintf("outbound websocket server registrations for device id %s", old.ID))
+	}
+
+	if enabled(config, "cloud") {
+		checklist = append(checklist, fmt.Sprintf("Shelly Cloud: remove device %s from the account and add %s", old.ID, target.ID))
+	}
+
+	if archive.Certs != nil {
+		for _, cert := range archive.Certs.Certs {
+			if cert.UseClientCert || (cert.SslCa != nil && *cert.SslCa == UserCA) {
+				checklist = append(checklist, fmt.Sprintf("certificates are not part of the backup; upload them again (pki provision) and revoke the client certificate of %s", old.ID))
+				break
+			}
+		}
+	}
+
+	return checklist
+}
+
+// enabled returns true if the component has enable set to true
+func enabled(config map[string]any, key string) bool {
+	value, _ := getValue(config, key, "enable").(bool)
+	return value
+}
+
+func getValue(config map[string]any, key, field string) any {
+	m, ok := config[key].(map[string]any)
+	if !ok {
+		return nil
+	}
+	return m[field]
+}
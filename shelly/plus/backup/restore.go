@@ -0,0 +1,402 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+// Restore writes the archive to the device in dependency order: the Key-Value Store, the config
+// without Wi-Fi and Ethernet, the scripts, the scheduled jobs, the webhooks and last the Wi-Fi and
+// Ethernet config. Existing scripts, scheduled jobs and webhooks are replaced. Scripts get new ids on the
+// device; scheduled jobs that call a script are changed to the new id. The restore is refused if the
+// model of the device differs from the archive unless options.Force is set. Secrets the device does not
+// return are taken from options.Secrets; objects such as wifi.sta whose secret is not given are left
+// unchanged with a warning. The report is returned with the error if a step fails.
+func Restore(ctx context.Context, device Device, archive *Archive, options *RestoreOptions) (*RestoreReport, error) {
+
+	if options == nil {
+		options = &RestoreOptions{}
+	}
+
+	info, err := device.Shelly().GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{
+		DeviceID: info.ID,
+		Certs:    archive.Certs,
+	}
+
+	err = checkCompatible(archive.Manifest, info, options.Force, report)
+	if err != nil {
+		return nil, err
+	}
+
+	_, warnings, err := restoreConfig(archive, options.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	report.Warnings = append(report.Warnings, warnings...)
+
+	err = restoreKVS(ctx, device, archive, report)
+	if err != nil {
+		return report, err
+	}
+
+	plan := options.Plan
+	if plan == nil {
+		plan, err = PlanRestore(ctx, device, archive, options.Secrets)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	local, network := splitPlan(plan)
+
+	report.Config, err = device.Shelly().ApplyPlan(ctx, local, nil, &shelly.ApplyOptions{
+		Force: options.Force,
+	})
+	if err != nil {
+		return report, err
+	}
+	report.RestartRequired = report.Config.RestartRequired
+
+	scripts, err := restoreScripts(ctx, device, archive, report)
+	if err != nil {
+		return report, err
+	}
+
+	// The schedules and webhooks are replaced as a whole so they are refused if they were changed since
+	// the plan was made
+
+	if !options.Force && plan.Revisions != nil {
+		err = device.Shelly().CheckRevisions(ctx, &shelly.ShellyRevisions{ScheduleRev: plan.Revisions.ScheduleRev})
+		if err != nil {
+			return report, err
+		}
+	}
+
+	err = restoreSchedules(ctx, device, archive, scripts, report)
+	if err != nil {
+		return report, err
+	}
+
+	if !options.Force && plan.Revisions != nil {
+		err = device.Shelly().CheckRevisions(ctx, &shelly.ShellyRevisions{WebhookRev: plan.Revisions.WebhookRev})
+		if err != nil {
+			return report, err
+		}
+	}
+
+	err = restoreWebhooks(ctx, device, archive, report)
+	if err != nil {
+		return report, err
+	}
+
+	if len(network.Components) == 0 {
+		return report, nil
+	}
+
+	// The revisions moved with the local components so the network plan is forced
+	networkReport, err := device.Shelly().ApplyPlan(ctx, network, nil, &shelly.ApplyOptions{
+		Force:    true,
+		Hostname: options.Hostname,
+		Dial:     options.Dial,
+	})
+
+	if networkReport != nil {
+		report.Config.Changed = append(report.Config.Changed, networkReport.Changed...)
+		report.Config.Failed = append(report.Config.Failed, networkReport.Failed...)
+		report.Config.Network = networkReport.Network
+		report.RestartRequired = report.RestartRequired || networkReport.RestartRequired
+		report.Config.RestartRequired = report.RestartRequired
+	}
+
+	return report, err
+}
+
+// PlanRestore returns the changes the restore makes to the config of the device with the given secrets. The
+// plan also records the webhook and schedule revisions because the restore replaces them.
+func PlanRestore(ctx context.Context, device Device, archive *Archive, secrets map[string]string) (*shelly.ShellyPlan, error) {
+
+	data, _, err := restoreConfig(archive, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := device.Shelly().GetRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var config *shelly.ShellyConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", ConfigFile, err)
+	}
+
+	plan, err := device.Shelly().Plan(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Revisions.WebhookRev = revisions.WebhookRev
+	plan.Revisions.ScheduleRev = revisions.ScheduleRev
+
+	return plan, nil
+}
+
+// checkCompatible returns an error if the archive was taken from a different model. A different firmware
+// version is reported as a warning.
+func checkCompatible(manifest *Manifest, info *DeviceInfo, force bool, report *RestoreReport) error {
+
+	if manifest.Model != info.Model {
+		if !force {
+			return fmt.Errorf("archive is from model %s and the device is model %s; use force to restore anyway", manifest.Model, info.Model)
+		}
+		report.Warnings = append(report.Warnings, fmt.Sprintf("archive is from model %s and the device is model %s", manifest.Model, info.Model))
+	}
+
+	if manifest.Version != info.Version {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("archive is from firmware %s and the device has firmware %s", manifest.Version, info.Version))
+	}
+
+	return nil
+}
+
+// splitPlan returns a plan without and a plan with only the network components
+func splitPlan(plan *shelly.ShellyPlan) (*shelly.ShellyPlan, *shelly.ShellyPlan) {
+
+	local := &shelly.ShellyPlan{
+		Unchanged:   plan.Unchanged,
+		Unsupported: plan.Unsupported,
+		Revisions:   plan.Revisions,
+	}
+
+	network := &shelly.ShellyPlan{}
+
+	for _, component := range plan.Components {
+
+		target := local
+		if shelly.IsNetworkComponent(component.Key) {
+			target = network
+		}
+
+		target.Components = append(target.Components, component)
+		target.Changes = target.Changes + len(component.Changes)
+		target.RestartRequired = target.RestartRequired || component.RestartRequired
+	}
+
+	return local, network
+}
+
+func restoreKVS(ctx context.Context, device Device, archive *Archive, report *RestoreReport) error {
+
+	var keys []string
+	for key := range archive.KVS {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		_, err := device.KVS().Set(ctx, key, archive.KVS[key])
+		if err != nil {
+			return fmt.Errorf("kvs %s :: %w", key, err)
+		}
+		report.KVS++
+	}
+
+	return nil
+}
+
+// restoreScripts replaces the scripts of the device and returns the new id of each script by its id
+// in the archive
+func restoreScripts(ctx context.Context, device Device, archive *Archive, report *RestoreReport) (map[int]int, error) {
+
+	existing, err := device.Script().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("scripts :: %w", err)
+	}
+
+	for _, info := range existing.Scripts {
+
+		if info.Running {
+			_, err = device.Script().Stop(ctx, info.ID)
+			if err != nil {
+				return nil, fmt.Errorf("script %d :: %w", info.ID, err)
+			}
+		}
+
+		_, err = device.Script().Delete(ctx, info.ID)
+		if err != nil {
+			return nil, fmt.Errorf("script %d :: %w", info.ID, err)
+		}
+	}
+
+	ids := make(map[int]int)
+
+	for _, script := range archive.Scripts {
+
+		name := ""
+		if script.Info.Name != nil {
+			name = *script.Info.Name
+		}
+
+		created, err := device.Script().Create(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("script %d :: %w", script.Info.ID, err)
+		}
+
+		if created.ID == nil {
+			return nil, fmt.Errorf("script %d :: id is missing from response", script.Info.ID)
+		}
+
+		id := *created.ID
+		ids[script.Info.ID] = id
+
+		_, err = device.Script().PutCode(ctx, id, script.Code)
+		if err != nil {
+			return nil, fmt.Errorf("script %d :: %w", script.Info.ID, err)
+		}
+
+		_, err = device.Script().SetConfig(ctx, &types.ScriptConfig{
+			ID:     id,
+			Name:   script.Info.Name,
+			Enable: script.Info.Enable,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("script %d :: %w", script.Info.ID, err)
+		}
+
+		scriptReport := &ScriptReport{
+			Name: script.Info.Name,
+			From: script.Info.ID,
+			To:   id,
+		}
+
+		if script.Info.Running {
+			_, err = device.Script().Start(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("script %d :: %w", script.Info.ID, err)
+			}
+			scriptReport.Started = true
+		}
+
+		report.Scripts = append(report.Scripts, scriptReport)
+	}
+
+	return ids, nil
+}
+
+// restoreSchedules replaces the scheduled jobs of the device. Calls of script methods are changed to the
+// new id of the script.
+func restoreSchedules(ctx context.Context, device Device, archive *Archive, scripts map[int]int, report *RestoreReport) error {
+
+	_, err := device.Schedule().DeleteAll(ctx)
+	if err != nil {
+		return fmt.Errorf("schedules :: %w", err)
+	}
+
+	for i, job := range archive.Schedules {
+
+		create := &ScheduleJob{
+			Enable:   job.Enable,
+			Timespec: job.Timespec,
+		}
+
+		for _, call := range job.Calls {
+			create.Calls = append(create.Calls, &types.ScheduleCall{
+				Method: call.Method,
+				Params: remapScriptID(call.Method, call.Params, scripts),
+			})
+		}
+
+		_, err = device.Schedule().Create(ctx, create)
+		if err != nil {
+			return fmt.Errorf("schedule %d :: %w", i, err)
+		}
+		report.Schedules++
+	}
+
+	return nil
+}
+
+// remapScriptID returns a copy of the params with the id changed to the new id of the script if the
+// method is a script method
+func remapScriptID(method string, params map[string]any, scripts map[int]int) map[string]any {
+
+	if !strings.HasPrefix(strings.ToLower(method), "script.") {
+		return params
+	}
+
+	id, ok := params["id"].(float64)
+	if !ok {
+		return params
+	}
+
+	to, ok := scripts[int(id)]
+	if !ok {
+		return params
+	}
+
+	result := make(map[string]any)
+	for key, value := range params {
+		result[key] = value
+	}
+	result["id"] = to
+
+	return result
+}
+
+// restoreWebhooks replaces the webhooks of the device. The webhooks of the archive are checked before
+// the existing ones are deleted.
+func restoreWebhooks(ctx context.Context, device Device, archive *Archive, report *RestoreReport) error {
+
+	var hooks []*types.WebhookParams
+
+	for i, hook := range archive.Webhooks {
+
+		params := &types.WebhookParams{
+			Event:         hook.Event,
+			Cid:           hook.Cid,
+			Enable:        hook.Enable,
+			Name:          hook.Name,
+			SslCa:         hook.SslCa,
+			URLs:          hook.URLs,
+			ActiveBetween: hook.ActiveBetween,
+			RepeatPeriod:  hook.RepeatPeriod,
+		}
+
+		switch condition := hook.Condition.(type) {
+		case nil:
+		case string:
+			params.Condition = &condition
+		default:
+			return fmt.Errorf("webhook %d :: condition %v is not a string", i, condition)
+		}
+
+		hooks = append(hooks, params)
+	}
+
+	_, err := device.WebHook().DeleteAll(ctx)
+	if err != nil {
+		return fmt.Errorf("webhooks :: %w", err)
+	}
+
+	for i, params := range hooks {
+		_, err = device.WebHook().Create(ctx, params)
+		if err != nil {
+			return fmt.Errorf("webhook %d :: %w", i, err)
+		}
+		report.Webhooks++
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/bluetooth"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/cloud"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/ethernet"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/input"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/kvs"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/light"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/mqtt"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/schedule"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/script"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/switchx"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/system"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/webhook"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/websocket"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/wifi"
+)
+
+const testConfigJSON = `{"sys":{"device":{"name":"kitchen","mac":"A8032AB636EC"},"cfg_rev":10}}`
+
+// testDevice answers the requests of a restore and records the methods that were called
+type testDevice struct {
+	webhookRev int
+	methods    []string
+}
+
+func (t *testDevice) Send(ctx context.Context, request *types.Request) ([]byte, error) {
+
+	t.methods = append(t.methods, request.Method)
+
+	result := `{}`
+
+	switch request.Method {
+
+	case "Shelly.GetDeviceInfo":
+		result = `{"id":"shellyplus1-a8032ab636ec","mac":"A8032AB636EC","model":"SNSW-001X16EU","gen":2,"ver":"0.14.4","app":"Plus1"}`
+
+	case "Sys.GetStatus":
+		result = fmt.Sprintf(`{"cfg_rev":10,"webhook_rev":%d,"schedule_rev":1}`, t.webhookRev)
+
+	case "Shelly.GetConfig":
+		result = testConfigJSON
+
+	case "Script.List":
+		result = `{"scripts":[]}`
+	}
+
+	return []byte(`{"id":1,"result":` + result + `}`), nil
+}
+
+func (t *testDevice) Close()                                             {}
+func (t *testDevice) NewHandle() types.MessageHandler                    { return t }
+func (t *testDevice) Subscribe(handler types.NotificationHandler) func() { return func() {} }
+
+func (t *testDevice) System() *system.Client       { return system.New(t) }
+func (t *testDevice) Bluetooth() *bluetooth.Client { return bluetooth.New(t) }
+func (t *testDevice) Mqtt() *mqtt.Client           { return mqtt.New(t) }
+func (t *testDevice) WiFi() *wifi.Client           { return wifi.New(t) }
+func (t *testDevice) Cloud() *cloud.Client         { return cloud.New(t) }
+func (t *testDevice) Switch() *switchx.Client      { return switchx.New(t) }
+func (t *testDevice) Input() *input.Client         { return input.New(t) }
+func (t *testDevice) Light() *light.Client         { return light.New(t) }
+func (t *testDevice) Websocket() *websocket.Client { return websocket.New(t) }
+func (t *testDevice) Ethernet() *ethernet.Client   { return ethernet.New(t) }
+
+func (t *testDevice) Shelly() *shelly.Client     { return shelly.New(t) }
+func (t *testDevice) WebHook() *webhook.Client   { return webhook.New(t) }
+func (t *testDevice) Schedule() *schedule.Client { return schedule.New(t) }
+func (t *testDevice) Script() *script.Client     { return script.New(t) }
+func (t *testDevice) KVS() *kvs.Client           { return kvs.New(t) }
+
+func (t *testDevice) called(method string) bool {
+	for _, m := range t.methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func testArchive() *Archive {
+	return &Archive{
+		Manifest:   &Manifest{Model: "SNSW-001X16EU", Version: "0.14.4"},
+		DeviceInfo: &DeviceInfo{ID: "shellyplus1-a8032ab636ec"},
+		Config:     json.RawMessage(testConfigJSON),
+	}
+}
+
+func TestRestoreRefusesChangedWebhooks(t *testing.T) {
+
+	device := &testDevice{webhookRev: 1}
+	archive := testArchive()
+
+	plan, err := PlanRestore(context.Background(), device, archive, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone else changes the webhooks after the plan was shown
+	device.webhookRev = 2
+
+	_, err = Restore(context.Background(), device, archive, &RestoreOptions{Plan: plan})
+
+	var conflict *types.ConflictError
+	if !errors.As(err, &conflict) || conflict.Name != "webhook_rev" {
+		t.Fatalf("expected a webhook_rev conflict, got %v", err)
+	}
+
+	for _, method := range []string{"Schedule.DeleteAll", "Webhook.DeleteAll"} {
+		if device.called(method) {
+			t.Errorf("%s was called after the conflict", method)
+		}
+	}
+
+	// Force restores anyway
+
+	_, err = Restore(context.Background(), device, archive, &RestoreOptions{Plan: plan, Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !device.called("Webhook.DeleteAll") {
+		t.Errorf("forced restore did not replace the webhooks")
+	}
+}
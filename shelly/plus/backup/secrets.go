@@ -0,0 +1,217 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+// secretObjects are the objects of the config with a secret the device does not return. The first
+// element is the component and the second the path of the object within it.
+var secretObjects = [][2]string{
+	{"wifi", "ap"},
+	{"wifi", "sta"},
+	{"wifi", "sta1"},
+	{"mqtt", ""},
+}
+
+// MissingSecrets returns the paths of the secrets that are in use by the config but are not part of it
+// because the device does not return them, for example wifi.sta.pass
+func MissingSecrets(config json.RawMessage) ([]string, error) {
+
+	var generic map[string]any
+	err := json.Unmarshal(config, &generic)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", ConfigFile, err)
+	}
+
+	var missing []string
+
+	for _, object := range secretObjects {
+
+		m := getObject(generic, object[0], object[1])
+		if m == nil || !usesSecret(object[0], m) {
+			continue
+		}
+
+		if _, ok := m["pass"].(string); ok {
+			continue
+		}
+
+		missing = append(missing, secretPath(object))
+	}
+
+	return missing, nil
+}
+
+// ParseSecrets returns the secrets given as path=value by their path. The value may be a secret reference
+// such as env:WIFI_PASS.
+func ParseSecrets(values []string) (map[string]string, error) {
+
+	secrets := make(map[string]string)
+
+	for _, value := range values {
+
+		path, secret, ok := strings.Cut(value, "=")
+		if !ok || secret == "" {
+			return nil, fmt.Errorf("secret %q is not path=value", value)
+		}
+
+		if !isSecretPath(path) {
+			return nil, fmt.Errorf("%s is not a secret of the backup; use one of %s", path, strings.Join(secretPaths(), ", "))
+		}
+
+		secrets[path] = secret
+	}
+
+	return secrets, nil
+}
+
+// restoreConfig returns the config of the archive with the missing secrets set to their value in secrets.
+// An object with a missing secret that is not in secrets is removed so the device keeps its current
+// config; a station or access point without its password would cut off the device. A warning is
+// returned for each removed object.
+func restoreConfig(archive *Archive, secrets map[string]string) (json.RawMessage, []string, error) {
+
+	missing, err := MissingSecrets(archive.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(missing) == 0 {
+		return archive.Config, nil, nil
+	}
+
+	var generic map[string]any
+	err = json.Unmarshal(archive.Config, &generic)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s :: %w", ConfigFile, err)
+	}
+
+	var warnings []string
+
+	for _, object := range secretObjects {
+
+		path := secretPath(object)
+		if !contains(missing, path) {
+			continue
+		}
+
+		m := getObject(generic, object[0], object[1])
+
+		if secret, ok := secrets[path]; ok {
+			value, err := util.ResolveSecret(secret)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s :: %w", path, err)
+			}
+			m["pass"] = value
+			continue
+		}
+
+		removeObject(generic, object[0], object[1])
+		warnings = append(warnings, fmt.Sprintf("%s is not part of the backup; %s is left unchanged. Set it with --set %s=env:NAME",
+			path, objectPath(object), path))
+	}
+
+	config, err := json.Marshal(generic)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, warnings, nil
+}
+
+// usesSecret returns true if the object is enabled and needs its password
+func usesSecret(key string, m map[string]any) bool {
+
+	enable, _ := m["enable"].(bool)
+	if !enable {
+		return false
+	}
+
+	if key == "mqtt" {
+		user, _ := m["user"].(string)
+		return user != ""
+	}
+
+	open, _ := m["is_open"].(bool)
+	return !open
+}
+
+// getObject returns the object at the dot separated path of the component or nil
+func getObject(config map[string]any, key, path string) map[string]any {
+
+	m, ok := config[key].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	if path == "" {
+		return m
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		m, ok = m[part].(map[string]any)
+		if !ok {
+			return nil
+		}
+	}
+
+	return m
+}
+
+// removeObject removes the object at the dot separated path of the component
+func removeObject(config map[string]any, key, path string) {
+
+	if path == "" {
+		delete(config, key)
+		return
+	}
+
+	parts := strings.Split(path, ".")
+
+	m := getObject(config, key, strings.Join(parts[:len(parts)-1], "."))
+	if m != nil {
+		delete(m, parts[len(parts)-1])
+	}
+}
+
+// objectPath returns the path of the object including the component
+func objectPath(object [2]string) string {
+	if object[1] == "" {
+		return object[0]
+	}
+	return object[0] + "." + object[1]
+}
+
+// secretPath returns the path of the secret of the object
+func secretPath(object [2]string) string {
+	return objectPath(object) + ".pass"
+}
+
+func secretPaths() []string {
+
+	var paths []string
+	for _, object := range secretObjects {
+		paths = append(paths, secretPath(object))
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+func isSecretPath(path string) bool {
+	return contains(secretPaths(), path)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
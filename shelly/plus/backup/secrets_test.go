@@ -0,0 +1,73 @@
+package backup
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const secretsConfigJSON = `{
+  "mqtt": {"enable": true, "server": "broker:1883", "user": "shelly"},
+  "wifi": {
+    "ap": {"ssid": "ShellyPlus1-A8032AB636EC", "is_open": true, "enable": false},
+    "sta": {"ssid": "home", "is_open": false, "enable": true, "ipv4mode": "dhcp"},
+    "sta1": {"ssid": null, "is_open": true, "enable": false}
+  }
+}`
+
+func TestMissingSecrets(t *testing.T) {
+
+	missing, err := MissingSecrets(json.RawMessage(secretsConfigJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"wifi.sta.pass", "mqtt.pass"}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Errorf("expected %v, actual %v", expected, missing)
+	}
+}
+
+func TestRestoreConfigSecrets(t *testing.T) {
+
+	t.Setenv("TEST_WIFI_PASS", "secret")
+
+	archive := &Archive{Config: json.RawMessage(secretsConfigJSON)}
+
+	data, warnings, err := restoreConfig(archive, map[string]string{"wifi.sta.pass": "env:TEST_WIFI_PASS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config map[string]any
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pass := getObject(config, "wifi", "sta")["pass"]; pass != "secret" {
+		t.Errorf("wifi.sta.pass is %v", pass)
+	}
+
+	// MQTT without its password is left unchanged
+	if _, ok := config["mqtt"]; ok {
+		t.Errorf("mqtt without its password is restored")
+	}
+
+	if len(warnings) != 1 {
+		t.Errorf("expected a warning for mqtt.pass, actual %v", warnings)
+	}
+}
+
+func TestParseSecrets(t *testing.T) {
+
+	_, err := ParseSecrets([]string{"sys.device.name=x"})
+	if err == nil {
+		t.Errorf("a path that is not a secret is accepted")
+	}
+
+	_, err = ParseSecrets([]string{"wifi.sta.pass"})
+	if err == nil {
+		t.Errorf("a secret without a value is accepted")
+	}
+}
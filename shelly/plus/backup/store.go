@@ -0,0 +1,200 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/inventory"
+)
+
+// Store keeps backup archives in a directory per device id. The file name is the time of the backup
+// so the names sort by age.
+type Store struct {
+	dir string
+}
+
+// DefaultStoreDir returns the default backup store directory in the user config dir
+func DefaultStoreDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shelly-manager", "backups"), nil
+}
+
+// NewStore returns the store in dir. Default is DefaultStoreDir().
+func NewStore(dir string) (*Store, error) {
+
+	if dir == "" {
+		var err error
+		dir, err = DefaultStoreDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Save writes the archive data of the device and returns the file
+func (t *Store) Save(deviceID string, created time.Time, data []byte) (string, error) {
+
+	err := checkDeviceID(deviceID)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(t.dir, deviceID)
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, created.UTC().Format(StoreTimeFormat)+StoreFileExt)
+
+	err = os.WriteFile(file, data, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// Latest returns the file of the latest backup of the device
+func (t *Store) Latest(deviceID string) (string, error) {
+
+	err := checkDeviceID(deviceID)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(t.dir, deviceID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("no backup of device %s in %s", deviceID, t.dir)
+		}
+		return "", err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), StoreFileExt) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	if len(names) == 0 {
+		return "", fmt.Errorf("no backup of device %s in %s", deviceID, t.dir)
+	}
+
+	sort.Strings(names)
+
+	return filepath.Join(t.dir, deviceID, names[len(names)-1]), nil
+}
+
+// checkDeviceID returns an error if the device id can not be used as the directory of the device
+func checkDeviceID(deviceID string) error {
+	if deviceID == "" || strings.ContainsAny(deviceID, `/\`) || deviceID == "." || deviceID == ".." {
+		return fmt.Errorf("device id %q is not valid", deviceID)
+	}
+	return nil
+}
+
+// latestBackup returns the latest backup of the device in the store. The last backup recorded in the
+// inventory is used if the store has none.
+func latestBackup(dir string, inventoryStore *inventory.Store, deviceID string) (string, error) {
+
+	err := checkDeviceID(deviceID)
+	if err != nil {
+		return "", err
+	}
+
+	store, err := NewStore(dir)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := store.Latest(deviceID)
+	if err == nil {
+		return file, nil
+	}
+
+	entry, inventoryErr := inventoryStore.Get(deviceID)
+	if inventoryErr != nil || entry == nil || entry.LastBackup == "" {
+		return "", err
+	}
+
+	return entry.LastBackup, nil
+}
+
+// recordBackup records the device and the file of the backup in the inventory
+func recordBackup(inventoryDir string, archive *Archive, file string) error {
+
+	inventoryStore, err := inventory.New(&inventory.Config{Dir: inventoryDir})
+	if err != nil {
+		return err
+	}
+
+	_, err = inventoryStore.Update(archive.Manifest.DeviceID, func(entry *inventory.Entry) {
+		entry.MAC = archive.DeviceInfo.MAC
+		entry.Model = archive.DeviceInfo.Model
+		entry.Name = deviceName(archive.Config)
+		entry.Hostname = archive.Manifest.Hostname
+		entry.Version = archive.DeviceInfo.Version
+		entry.LastBackup = file
+	})
+
+	return err
+}
+
+// recordReplace records that the old device of the archive was replaced by the target
+func recordReplace(inventoryStore *inventory.Store, archive *Archive, target *DeviceInfo, hostname string) error {
+
+	_, err := inventoryStore.Update(archive.DeviceInfo.ID, func(entry *inventory.Entry) {
+		if entry.MAC == "" {
+			entry.MAC = archive.DeviceInfo.MAC
+			entry.Model = archive.DeviceInfo.Model
+		}
+		entry.ReplacedBy = target.ID
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = inventoryStore.Update(target.ID, func(entry *inventory.Entry) {
+		entry.MAC = target.MAC
+		entry.Model = target.Model
+		entry.Name = deviceName(archive.Config)
+		entry.Hostname = hostname
+		entry.Version = target.Version
+		entry.Replaces = archive.DeviceInfo.ID
+		entry.ReplacedBy = ""
+	})
+
+	return err
+}
+
+// deviceName returns sys.device.name of the raw config
+func deviceName(config []byte) string {
+
+	var c struct {
+		Sys struct {
+			Device struct {
+				Name *string `json:"name"`
+			} `json:"device"`
+		} `json:"sys"`
+	}
+
+	if json.Unmarshal(config, &c) != nil || c.Sys.Device.Name == nil {
+		return ""
+	}
+
+	return *c.Sys.Device.Name
+}
@@ -0,0 +1,151 @@
+package backup
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/kvs"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/schedule"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/script"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/webhook"
+)
+
+type DeviceInfo = types.DeviceInfo
+type WebhookHook = types.WebhookHook
+type ScheduleJob = types.ScheduleJob
+type ScriptInfo = types.ScriptInfo
+type ShellyApplyReport = types.ShellyApplyReport
+
+// Device is the set of component clients needed to back up and restore a device
+type Device interface {
+	Shelly() *shelly.Client
+	WebHook() *webhook.Client
+	Schedule() *schedule.Client
+	Script() *script.Client
+	KVS() *kvs.Client
+	Close()
+}
+
+// Manifest describes the archive and the device it was taken from
+type Manifest struct {
+	// Format version of the archive layout
+	Format int `json:"format" yaml:"format"`
+	// Created time the backup was taken
+	Created time.Time `json:"created" yaml:"created"`
+	// Hostname used to reach the device
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	// DeviceID id of the device
+	DeviceID string `json:"device_id" yaml:"device_id"`
+	// Model of the device; a restore is refused on a different model unless forced
+	Model string `json:"model" yaml:"model"`
+	// App name of the device
+	App string `json:"app" yaml:"app"`
+	// Generation of the device
+	Generation float32 `json:"gen" yaml:"gen"`
+	// Version of the firmware
+	Version string `json:"ver" yaml:"ver"`
+	// FirmwareID id of the firmware
+	FirmwareID string `json:"fw_id" yaml:"fw_id"`
+	// Files in the archive
+	Files []string `json:"files" yaml:"files"`
+	// MissingSecrets paths of the secrets in use that the device does not return, for example
+	// wifi.sta.pass. They must be given to a restore.
+	MissingSecrets []string `json:"missing_secrets,omitempty" yaml:"missing_secrets,omitempty"`
+}
+
+// Script is a script with its code
+type Script struct {
+	Info *ScriptInfo `json:"info" yaml:"info"`
+	Code string      `json:"-" yaml:"-"`
+}
+
+// Cert is the certificate related config of a component
+type Cert struct {
+	// Component key, for example mqtt or webhook:1
+	Component string `json:"component" yaml:"component"`
+	// SslCa type of the TCP sockets; user_ca.pem means an uploaded user CA is needed
+	SslCa *string `json:"ssl_ca" yaml:"ssl_ca"`
+	// UseClientCert true if the uploaded client certificate and key are used
+	UseClientCert bool `json:"use_client_cert,omitempty" yaml:"use_client_cert,omitempty"`
+}
+
+// Certs is the certificate metadata of the device
+type Certs struct {
+	Note  string  `json:"note" yaml:"note"`
+	Certs []*Cert `json:"certs" yaml:"certs"`
+}
+
+// Archive is the content of a backup
+type Archive struct {
+	Manifest   *Manifest
+	DeviceInfo *DeviceInfo
+	// Config raw result of Shelly.GetConfig so fields that are not modeled are kept
+	Config    json.RawMessage
+	Webhooks  []*WebhookHook
+	Schedules []*ScheduleJob
+	Scripts   []*Script
+	KVS       map[string]any
+	Certs     *Certs
+}
+
+// RestoreOptions controls a restore
+type RestoreOptions struct {
+	// Force restores onto a device of a different model and even if the config, schedules or webhooks
+	// of the device were changed since the plan was made
+	Force bool
+	// Hostname used to reach the device. It is the first address tried after a network change.
+	Hostname string
+	// Dial is used to reach the device after a network change
+	Dial shelly.Dialer
+	// Plan of the config changes, for example a plan that was shown before the restore. If nil the
+	// plan is made by Restore. The restore is refused if the device changed since the plan was made.
+	Plan *shelly.ShellyPlan
+	// Secrets values of the missing secrets of the archive by their path, for example wifi.sta.pass.
+	// Values may be secret references. Objects with a missing secret that is not given are not restored.
+	Secrets map[string]string
+}
+
+// RetargetOptions controls how an archive is prepared for another device
+type RetargetOptions struct {
+	// Replace keeps the name and the static address config of the source because the target takes its
+	// place
+	Replace bool
+}
+
+// ScriptReport is the result of restoring a script
+type ScriptReport struct {
+	Name *string `json:"name" yaml:"name"`
+	// From id of the script in the archive
+	From int `json:"from" yaml:"from"`
+	// To id of the script on the device
+	To      int  `json:"to" yaml:"to"`
+	Started bool `json:"started,omitempty" yaml:"started,omitempty"`
+}
+
+// RestoreReport is the result of a restore
+type RestoreReport struct {
+	DeviceID        string             `json:"device_id" yaml:"device_id"`
+	Warnings        []string           `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	KVS             int                `json:"kvs" yaml:"kvs"`
+	Config          *ShellyApplyReport `json:"config,omitempty" yaml:"config,omitempty"`
+	Scripts         []*ScriptReport    `json:"scripts,omitempty" yaml:"scripts,omitempty"`
+	Schedules       int                `json:"schedules" yaml:"schedules"`
+	Webhooks        int                `json:"webhooks" yaml:"webhooks"`
+	Certs           *Certs             `json:"certs,omitempty" yaml:"certs,omitempty"`
+	RestartRequired bool               `json:"restart_required" yaml:"restart_required"`
+}
+
+// ReplaceReport is the result of replacing a device
+type ReplaceReport struct {
+	// OldID id of the replaced device
+	OldID string `json:"old_id" yaml:"old_id"`
+	// NewID id of the replacement
+	NewID string `json:"new_id" yaml:"new_id"`
+	// Backup file of the restored backup
+	Backup  string         `json:"backup" yaml:"backup"`
+	Restore *RestoreReport `json:"restore,omitempty" yaml:"restore,omitempty"`
+	// Checklist of external references that may still point at the old device
+	Checklist []string `json:"checklist" yaml:"checklist"`
+}
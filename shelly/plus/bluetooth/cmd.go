@@ -106,6 +106,11 @@ func NewCmd(callback callback) *cobra.Command {
 				}
 			}
 
+			err = config.Validate()
+			if err != nil {
+				return err
+			}
+
 			report, err := client.SetConfig(cmd.Context(), config)
 			if err != nil {
 				return err
@@ -8,9 +8,12 @@ import (
 	"github.com/jodydadescott/shelly-manager/shelly/plus/cloud"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/ethernet"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/input"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/kvs"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/light"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/mqtt"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/msghandlers"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/schedule"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/script"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/switchx"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/system"
@@ -41,6 +44,9 @@ type Client struct {
 	_websocket   *websocket.Client
 	_ethernet    *ethernet.Client
 	_webhook     *webhook.Client
+	_schedule    *schedule.Client
+	_script      *script.Client
+	_kvs         *kvs.Client
 	debugEnabled bool
 	types.MessageHandlerFactory
 }
@@ -171,6 +177,27 @@ func (t *Client) WebHook() *webhook.Client {
 	return t._webhook
 }
 
+func (t *Client) Schedule() *schedule.Client {
+	if t._schedule == nil {
+		t._schedule = schedule.New(t)
+	}
+	return t._schedule
+}
+
+func (t *Client) Script() *script.Client {
+	if t._script == nil {
+		t._script = script.New(t)
+	}
+	return t._script
+}
+
+func (t *Client) KVS() *kvs.Client {
+	if t._kvs == nil {
+		t._kvs = kvs.New(t)
+	}
+	return t._kvs
+}
+
 func (t *Client) Close() {
 
 	zap.L().Debug("(*Client) Close()")
@@ -227,5 +254,17 @@ func (t *Client) Close() {
 		t._webhook.Close()
 	}
 
+	if t._schedule != nil {
+		t._schedule.Close()
+	}
+
+	if t._script != nil {
+		t._script.Close()
+	}
+
+	if t._kvs != nil {
+		t._kvs.Close()
+	}
+
 	t.MessageHandlerFactory.Close()
 }
@@ -3,15 +3,21 @@ package plus
 import (
 	"context"
 
+	"github.com/jodydadescott/shelly-manager/shelly/plus/backup"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/bluetooth"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/cloud"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/doctor"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/ethernet"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/input"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/inventory"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/light"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/mqtt"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/pki"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/profile"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/switchx"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/system"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/webhook"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/websocket"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/wifi"
 	"github.com/spf13/cobra"
@@ -24,6 +30,8 @@ type callback interface {
 	WriteObject(any) error
 	WriteStderr(string)
 	ReadInput() ([]byte, error)
+	EncryptOutput([]byte) ([]byte, error)
+	DecryptInput([]byte) ([]byte, error)
 	IsDebugEnabled() bool
 }
 
@@ -43,9 +51,14 @@ func NewCmd(callback callback) *cobra.Command {
 		callback: callback,
 	}
 
-	d.AddCommand(system.NewCmd(d), shelly.NewCmd(d), wifi.NewCmd(d), bluetooth.NewCmd(d), mqtt.NewCmd(d))
+	shellyCmd := shelly.NewCmd(d)
+	shellyCmd.AddCommand(backup.NewBackupCmd(d), backup.NewRestoreCmd(d), backup.NewCloneCmd(d),
+		backup.NewReplaceCmd(d), profile.NewCmd(d))
+
+	d.AddCommand(system.NewCmd(d), shellyCmd, wifi.NewCmd(d), bluetooth.NewCmd(d), mqtt.NewCmd(d))
 	d.AddCommand(cloud.NewCmd(d), switchx.NewCmd(d), input.NewCmd(d), websocket.NewCmd(d))
-	d.AddCommand(ethernet.NewCmd(d), light.NewCmd(d))
+	d.AddCommand(ethernet.NewCmd(d), light.NewCmd(d), webhook.NewCmd(d), pki.NewCmd(d), doctor.NewCmd(d))
+	d.AddCommand(inventory.NewCmd(d))
 	return d.Command
 }
 
@@ -69,6 +82,65 @@ func (t *Cmd) client() (*Client, error) {
 	return t._client, nil
 }
 
+// Dial returns a new client for the hostname with the same credentials. The caller must close it.
+func (t *Cmd) Dial(hostname string) (pki.Device, error) {
+
+	client, err := New(&Config{
+		Hostname:     hostname,
+		Username:     t.GetUsername(),
+		Password:     t.GetPassword(),
+		DebugEnabled: t.IsDebugEnabled(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// ShellyFor returns a shelly client for the hostname with the same credentials and a function that
+// closes it
+func (t *Cmd) ShellyFor(hostname string) (*shelly.Client, func(), error) {
+
+	client, err := New(&Config{
+		Hostname:     hostname,
+		Username:     t.GetUsername(),
+		Password:     t.GetPassword(),
+		DebugEnabled: t.IsDebugEnabled(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client.Shelly(), client.Close, nil
+}
+
+// Device returns the client of the device for backup and restore
+func (t *Cmd) Device() (backup.Device, error) {
+	client, err := t.client()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// DeviceFor returns a new client for the hostname with the same credentials for backup, restore and
+// clone. The caller must close it.
+func (t *Cmd) DeviceFor(hostname string) (backup.Device, error) {
+
+	client, err := New(&Config{
+		Hostname:     hostname,
+		Username:     t.GetUsername(),
+		Password:     t.GetPassword(),
+		DebugEnabled: t.IsDebugEnabled(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
 func (t *Cmd) LogDebug(s string) {
 	t.WriteStderr(s)
 }
@@ -161,6 +233,14 @@ func (t *Cmd) Websocket() (*websocket.Client, error) {
 	return client.Websocket(), nil
 }
 
+func (t *Cmd) WebHook() (*webhook.Client, error) {
+	client, err := t.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.WebHook(), nil
+}
+
 func (t *Cmd) RebootDevice(ctx context.Context) error {
 	shelly, err := t.Shelly()
 	if err != nil {
@@ -0,0 +1,234 @@
+package debuglog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+// Handler is called for each entry that passes the filter
+type Handler func(*Entry)
+
+type Config struct {
+	Hostname string
+	Filter   *Filter
+}
+
+func New(config *Config) *Client {
+	return &Client{
+		hostname: config.Hostname,
+		filter:   config.Filter,
+	}
+}
+
+// Client consumes the debug logs of a device. Streaming must be enabled in the debug config
+// of the Sys component; see SystemDebug.
+type Client struct {
+	hostname string
+	filter   *Filter
+}
+
+// FollowWebsocket connects to the debug log websocket of the device and calls the handler for each entry
+// until the context is done. The websocket is reconnected if the connection is lost, for example while
+// the device reboots.
+func (t *Client) FollowWebsocket(ctx context.Context, handler Handler) error {
+
+	if t.hostname == "" {
+		return fmt.Errorf("hostname is required")
+	}
+
+	theURL := url.URL{Scheme: WsScheme, Host: t.hostname, Path: WsPath}
+
+	for {
+
+		err := t.followWebsocket(ctx, theURL.String(), handler)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		zap.L().Debug(fmt.Sprintf("debug log websocket error %v; will try again in %v", err, FailWaitDuration))
+
+		select {
+
+		case <-ctx.Done():
+			return nil
+
+		case <-time.After(FailWaitDuration):
+			continue
+		}
+	}
+}
+
+func (t *Client) followWebsocket(ctx context.Context, theURL string, handler Handler) error {
+
+	conn, _, err := gorilla.DefaultDialer.DialContext(ctx, theURL, nil)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for {
+
+		_, b, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range parseWebsocket(b) {
+			if t.filter.Match(entry) {
+				handler(entry)
+			}
+		}
+	}
+}
+
+// ListenUDP opens a local UDP listener on the interface that is used to reach the device. The returned
+// address should be set as the debug UDP address of the device.
+func (t *Client) ListenUDP(port int) (*net.UDPConn, string, error) {
+
+	ip, err := util.LocalIP(t.hostname)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: ip, Port: port})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return conn, conn.LocalAddr().String(), nil
+}
+
+// FollowUDP reads debug log entries from the listener and calls the handler for each entry until the
+// context is done. The listener is closed when the function returns.
+func (t *Client) FollowUDP(ctx context.Context, conn *net.UDPConn, handler Handler) error {
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	b := make([]byte, 65536)
+
+	for {
+
+		n, _, err := conn.ReadFromUDP(b)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, line := range strings.Split(string(b[:n]), "\n") {
+
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			entry := parseUDP(line)
+			if t.filter.Match(entry) {
+				handler(entry)
+			}
+		}
+	}
+}
+
+// parseWebsocket parses a websocket message. The device sends JSON objects with the attributes ts, level
+// and data; anything else is passed through as an info entry.
+func parseWebsocket(b []byte) []*Entry {
+
+	var message struct {
+		Ts    float64 `json:"ts"`
+		Level int     `json:"level"`
+		Data  string  `json:"data"`
+	}
+
+	err := json.Unmarshal(b, &message)
+	if err != nil {
+		return []*Entry{{
+			Time:    time.Now(),
+			Level:   LevelInfo,
+			Message: strings.TrimSpace(string(b)),
+		}}
+	}
+
+	var entries []*Entry
+
+	for _, line := range strings.Split(message.Data, "\n") {
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entries = append(entries, &Entry{
+			Time:    timeFromTs(message.Ts),
+			Level:   message.Level,
+			Message: line,
+		})
+	}
+
+	return entries
+}
+
+// parseUDP parses a UDP log line. The device sends lines in the format
+// '<device id> <sequence> <ts> <level>|<message>'; lines in any other format are passed through as info entries.
+func parseUDP(line string) *Entry {
+
+	entry := &Entry{
+		Time:    time.Now(),
+		Level:   LevelInfo,
+		Message: line,
+	}
+
+	header, message, ok := strings.Cut(line, "|")
+	if !ok {
+		return entry
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) < 2 {
+		return entry
+	}
+
+	level, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return entry
+	}
+
+	entry.Level = level
+	entry.Message = message
+	entry.Device = fields[0]
+
+	if ts, err := strconv.ParseFloat(fields[len(fields)-2], 64); err == nil {
+		entry.Time = timeFromTs(ts)
+	}
+
+	return entry
+}
+
+func timeFromTs(ts float64) time.Time {
+
+	if ts <= 0 {
+		return time.Now()
+	}
+
+	sec, frac := math.Modf(ts)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
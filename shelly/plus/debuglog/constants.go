@@ -0,0 +1,25 @@
+package debuglog
+
+import "time"
+
+const (
+	// WsScheme scheme used to connect to the debug log websocket
+	WsScheme = "ws"
+	// WsPath path of the debug log websocket on the device
+	WsPath = "/debug/log"
+	// DefaultUDPPort default local port used to receive UDP debug logs. Zero selects a free port.
+	DefaultUDPPort = 0
+)
+
+const (
+	LevelError   = 0
+	LevelWarn    = 1
+	LevelInfo    = 2
+	LevelDebug   = 3
+	LevelVerbose = 4
+)
+
+var (
+	// FailWaitDuration is the time to wait before reconnecting to the device after the websocket is lost
+	FailWaitDuration = time.Duration(3) * time.Second
+)
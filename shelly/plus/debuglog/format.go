@@ -0,0 +1,36 @@
+package debuglog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var (
+	levelColors = map[int]*color.Color{
+		LevelError:   color.New(color.FgRed, color.Bold),
+		LevelWarn:    color.New(color.FgYellow),
+		LevelInfo:    color.New(color.FgGreen),
+		LevelDebug:   color.New(color.FgCyan),
+		LevelVerbose: color.New(color.FgHiBlack),
+	}
+	timeColor = color.New(color.FgHiBlack)
+)
+
+// Format returns the entry as a single colored line. Colors are disabled automatically when the
+// output is not a terminal.
+func Format(entry *Entry) string {
+
+	levelColor := levelColors[entry.Level]
+	if levelColor == nil {
+		levelColor = levelColors[LevelVerbose]
+	}
+
+	level := fmt.Sprintf("%-7s", strings.ToUpper(LevelName(entry.Level)))
+
+	return fmt.Sprintf("%s %s %s",
+		timeColor.Sprint(entry.Time.Format("2006-01-02T15:04:05.000")),
+		levelColor.Sprint(level),
+		strings.TrimRight(entry.Message, "\r\n"))
+}
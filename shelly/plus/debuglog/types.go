@@ -0,0 +1,96 @@
+package debuglog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entry single debug log entry
+// https://shelly-api-docs.shelly.cloud/gen2/General/DebugLogs
+type Entry struct {
+	// Time of the entry as reported by the device or the time it was received
+	Time time.Time `json:"ts" yaml:"ts"`
+	// Level of the entry; 0 error, 1 warn, 2 info, 3 debug, 4 verbose
+	Level int `json:"level" yaml:"level"`
+	// Device ID of the device that sent the entry; only present for UDP logs
+	Device string `json:"device,omitempty" yaml:"device,omitempty"`
+	// Message of the entry
+	Message string `json:"data" yaml:"data"`
+}
+
+// Filter selects the entries that are passed to the handler
+type Filter struct {
+	// MaxLevel entries with a level above this are dropped
+	MaxLevel int
+	// Contains if not empty only entries with a message containing the string (case insensitive) are passed
+	Contains string
+}
+
+// Match returns true if the entry passes the filter
+func (t *Filter) Match(entry *Entry) bool {
+
+	if t == nil {
+		return true
+	}
+
+	if entry.Level > t.MaxLevel {
+		return false
+	}
+
+	if t.Contains == "" {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(entry.Message), strings.ToLower(t.Contains))
+}
+
+// LevelName returns the name of the level
+func LevelName(level int) string {
+
+	switch level {
+
+	case LevelError:
+		return "error"
+
+	case LevelWarn:
+		return "warn"
+
+	case LevelInfo:
+		return "info"
+
+	case LevelDebug:
+		return "debug"
+
+	case LevelVerbose:
+		return "verbose"
+
+	}
+
+	return fmt.Sprintf("level%d", level)
+}
+
+// LevelFromString returns the level for the name
+func LevelFromString(s string) (int, error) {
+
+	switch strings.ToLower(s) {
+
+	case "error":
+		return LevelError, nil
+
+	case "warn", "warning":
+		return LevelWarn, nil
+
+	case "info", "":
+		return LevelInfo, nil
+
+	case "debug":
+		return LevelDebug, nil
+
+	case "verbose":
+		return LevelVerbose, nil
+
+	}
+
+	return 0, fmt.Errorf("level %s is unknown; expect error, warn, info, debug or verbose", s)
+}
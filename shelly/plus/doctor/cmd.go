@@ -0,0 +1,58 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+	"github.com/spf13/cobra"
+)
+
+type callback interface {
+	WriteObject(any) error
+	Shelly() (*shelly.Client, error)
+}
+
+func NewCmd(callback callback) *cobra.Command {
+
+	var strictArg bool
+
+	rootCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnostics",
+	}
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Compares the device output with the library types",
+		Long: "Fetches Shelly.GetDeviceInfo, Shelly.GetConfig and Shelly.GetStatus as raw JSON and reports fields " +
+			"the device returns that are not modeled, modeled fields the device does not return and type mismatches",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			report, err := CheckSchema(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+
+			err = callback.WriteObject(report)
+			if err != nil {
+				return err
+			}
+
+			if strictArg && report.Total() > 0 {
+				return fmt.Errorf("schema drift found: %d issues", report.Total())
+			}
+
+			return nil
+		},
+	}
+
+	schemaCmd.PersistentFlags().BoolVar(&strictArg, "strict", false, "return an error if any issue is found")
+
+	rootCmd.AddCommand(schemaCmd)
+	return rootCmd
+}
@@ -0,0 +1,10 @@
+package doctor
+
+const (
+	// KindUnmodeled the device returns a field that the type does not have
+	KindUnmodeled = "unmodeled"
+	// KindMissing the type has a field that the device does not return
+	KindMissing = "missing"
+	// KindTypeMismatch the device returns a value that does not fit the type of the field
+	KindTypeMismatch = "type_mismatch"
+)
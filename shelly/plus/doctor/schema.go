@@ -0,0 +1,277 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+var schemaChecks = []struct {
+	method string
+	value  any
+}{
+	{method: "Shelly.GetDeviceInfo", value: types.DeviceInfo{}},
+	{method: "Shelly.GetConfig", value: types.ShellyConfig{}},
+	{method: "Shelly.GetStatus", value: types.ShellyStatus{}},
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// CheckSchema fetches the device info, config and status as raw JSON and compares them with the types
+func CheckSchema(ctx context.Context, client *shelly.Client) (*SchemaReport, error) {
+
+	info, err := client.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SchemaReport{
+		DeviceID: info.ID,
+		Model:    info.Model,
+		Version:  info.Version,
+	}
+
+	for _, check := range schemaChecks {
+
+		raw, err := client.Call(ctx, check.method, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s :: %w", check.method, err)
+		}
+
+		typ := reflect.TypeOf(check.value)
+
+		issues, err := Compare(raw, typ)
+		if err != nil {
+			return nil, fmt.Errorf("%s :: %w", check.method, err)
+		}
+
+		for _, issue := range issues {
+			switch issue.Kind {
+			case KindUnmodeled:
+				report.Unmodeled++
+			case KindMissing:
+				report.Missing++
+			case KindTypeMismatch:
+				report.TypeMismatch++
+			}
+		}
+
+		report.Methods = append(report.Methods, &MethodReport{
+			Method: check.method,
+			Type:   typ.String(),
+			Issues: issues,
+		})
+	}
+
+	return report, nil
+}
+
+// Compare compares the JSON with the struct definition of typ. Keyed components such as switch:0 are
+// compared with the element type of the matching component map.
+func Compare(raw json.RawMessage, typ reflect.Type) ([]*Issue, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &comparer{}
+	c.compare("", value, typ)
+
+	sort.SliceStable(c.issues, func(i, j int) bool {
+		return c.issues[i].Path < c.issues[j].Path
+	})
+
+	return c.issues, nil
+}
+
+type comparer struct {
+	issues []*Issue
+}
+
+func (t *comparer) add(path, kind string, expected reflect.Type, value any) {
+
+	issue := &Issue{
+		Path: path,
+		Kind: kind,
+	}
+
+	if expected != nil {
+		issue.Expected = expected.String()
+	}
+
+	if value != nil {
+		issue.Actual = jsonKind(value)
+	}
+
+	t.issues = append(t.issues, issue)
+}
+
+func (t *comparer) compare(path string, value any, typ reflect.Type) {
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if value == nil || typ == rawMessageType || typ.Kind() == reflect.Interface {
+		return
+	}
+
+	switch v := value.(type) {
+
+	case map[string]any:
+		switch typ.Kind() {
+
+		case reflect.Struct:
+			t.compareStruct(path, v, typ)
+			return
+
+		case reflect.Map:
+			for key, item := range v {
+				t.compare(joinPath(path, key), item, typ.Elem())
+			}
+			return
+
+		}
+
+	case []any:
+		switch typ.Kind() {
+
+		case reflect.Slice, reflect.Array:
+			for i, item := range v {
+				t.compare(fmt.Sprintf("%s[%d]", path, i), item, typ.Elem())
+			}
+			return
+
+		}
+
+	case json.Number:
+		switch typ.Kind() {
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if !strings.ContainsAny(v.String(), ".eE") {
+				return
+			}
+
+		case reflect.Float32, reflect.Float64:
+			return
+
+		}
+
+	case string:
+		if typ.Kind() == reflect.String {
+			return
+		}
+
+	case bool:
+		if typ.Kind() == reflect.Bool {
+			return
+		}
+
+	}
+
+	t.add(path, KindTypeMismatch, typ, value)
+}
+
+func (t *comparer) compareStruct(path string, value map[string]any, typ reflect.Type) {
+
+	fields := make(map[string]reflect.StructField)
+	components := make(map[string]reflect.Type)
+
+	for i := 0; i < typ.NumField(); i++ {
+
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		if name == "-" {
+			// Keyed component maps such as Switch map[int]*SwitchConfig
+			if field.Type.Kind() == reflect.Map && field.Type.Key().Kind() == reflect.Int {
+				components[strings.ToLower(field.Name)] = field.Type.Elem()
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[name] = field
+	}
+
+	for key, item := range value {
+
+		if field, ok := fields[key]; ok {
+			t.compare(joinPath(path, key), item, field.Type)
+			continue
+		}
+
+		if componentKey, err := types.ParseComponentKey(key); err == nil && componentKey.HasID {
+			if elem, ok := components[componentKey.Type]; ok {
+				t.compare(joinPath(path, key), item, elem)
+				continue
+			}
+		}
+
+		t.add(joinPath(path, key), KindUnmodeled, nil, item)
+	}
+
+	for name, field := range fields {
+
+		if _, ok := value[name]; ok {
+			continue
+		}
+
+		if strings.Contains(field.Tag.Get("json"), ",omitempty") {
+			continue
+		}
+
+		t.add(joinPath(path, name), KindMissing, field.Type, nil)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonKind(value any) string {
+
+	switch value.(type) {
+
+	case map[string]any:
+		return "object"
+
+	case []any:
+		return "array"
+
+	case json.Number:
+		return "number"
+
+	case string:
+		return "string"
+
+	case bool:
+		return "bool"
+
+	}
+
+	return "null"
+}
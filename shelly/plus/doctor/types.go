@@ -0,0 +1,36 @@
+package doctor
+
+// Issue is a difference between the output of the device and the type that models it
+type Issue struct {
+	// Path of the field, for example sys.device.name or switch:0.auto_on
+	Path string `json:"path" yaml:"path"`
+	// Kind is one of unmodeled, missing or type_mismatch
+	Kind string `json:"kind" yaml:"kind"`
+	// Expected Go type of the field
+	Expected string `json:"expected,omitempty" yaml:"expected,omitempty"`
+	// Actual JSON type returned by the device
+	Actual string `json:"actual,omitempty" yaml:"actual,omitempty"`
+}
+
+// MethodReport is the result of comparing the output of one method with its type
+type MethodReport struct {
+	Method string   `json:"method" yaml:"method"`
+	Type   string   `json:"type" yaml:"type"`
+	Issues []*Issue `json:"issues,omitempty" yaml:"issues,omitempty"`
+}
+
+// SchemaReport is the result of comparing the device output with the types
+type SchemaReport struct {
+	DeviceID     string          `json:"device_id" yaml:"device_id"`
+	Model        string          `json:"model" yaml:"model"`
+	Version      string          `json:"version" yaml:"version"`
+	Methods      []*MethodReport `json:"methods" yaml:"methods"`
+	Unmodeled    int             `json:"unmodeled" yaml:"unmodeled"`
+	Missing      int             `json:"missing" yaml:"missing"`
+	TypeMismatch int             `json:"type_mismatch" yaml:"type_mismatch"`
+}
+
+// Total returns the number of issues
+func (t *SchemaReport) Total() int {
+	return t.Unmodeled + t.Missing + t.TypeMismatch
+}
@@ -0,0 +1,13 @@
+package fwserver
+
+import "time"
+
+const (
+	// UrlPath path under which the firmware file is served
+	UrlPath = "/firmware/"
+)
+
+var (
+	// ShutdownTimeout is the maximum time allowed for in flight requests when the server is shut down
+	ShutdownTimeout = time.Duration(5) * time.Second
+)
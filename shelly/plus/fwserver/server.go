@@ -0,0 +1,194 @@
+package fwserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+type Config struct {
+	// Filename of the firmware zip file to serve. Required
+	Filename string
+	// Hostname of the device; used to pick the local address the device can reach. Required
+	Hostname string
+	// Port to listen on; zero selects a free port
+	Port int
+}
+
+// Server is a minimal HTTP server that serves a single firmware file to a device for an offline OTA update.
+// The device pulls the file from the Url passed to Shelly.Update.
+type Server struct {
+	filename string
+	hostname string
+	port     int
+	size     int64
+	done     chan struct{}
+	doneOnce sync.Once
+	server   *http.Server
+	url      string
+}
+
+func New(config *Config) (*Server, error) {
+
+	if config.Filename == "" {
+		return nil, fmt.Errorf("filename is required")
+	}
+
+	if config.Hostname == "" {
+		return nil, fmt.Errorf("hostname is required")
+	}
+
+	info, err := os.Stat(config.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() || info.Size() == 0 {
+		return nil, fmt.Errorf("%s is not a firmware file", config.Filename)
+	}
+
+	return &Server{
+		filename: config.Filename,
+		hostname: config.Hostname,
+		port:     config.Port,
+		size:     info.Size(),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Start starts serving the file on the local interface that is used to reach the device and returns the
+// Url the device should download the firmware from.
+func (t *Server) Start() (string, error) {
+
+	ip, err := util.LocalIP(t.hostname)
+	if err != nil {
+		return "", err
+	}
+
+	listener, err := net.Listen("tcp4", net.JoinHostPort(ip.String(), strconv.Itoa(t.port)))
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(t.filename)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(UrlPath+name, t.serve)
+
+	t.server = &http.Server{
+		Handler: mux,
+	}
+
+	theURL := url.URL{Scheme: "http", Host: listener.Addr().String(), Path: UrlPath + name}
+	t.url = theURL.String()
+
+	go func() {
+		err := t.server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			zap.L().Error(fmt.Sprintf("firmware server error %v", err))
+		}
+	}()
+
+	zap.L().Debug(fmt.Sprintf("serving %s at %s", t.filename, t.url))
+
+	return t.url, nil
+}
+
+// Url returns the Url of the firmware; empty until the server is started
+func (t *Server) Url() string {
+	return t.url
+}
+
+// Done is closed once the complete file has been sent to the device
+func (t *Server) Done() <-chan struct{} {
+	return t.done
+}
+
+// Shutdown stops the server
+func (t *Server) Shutdown(ctx context.Context) error {
+
+	if t.server == nil {
+		return nil
+	}
+
+	return t.server.Shutdown(ctx)
+}
+
+func (t *Server) serve(w http.ResponseWriter, r *http.Request) {
+
+	zap.L().Debug(fmt.Sprintf("firmware requested by %s range %s", r.RemoteAddr, r.Header.Get("Range")))
+
+	f, err := os.Open(t.filename)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.ServeContent(&countingWriter{ResponseWriter: w, server: t, status: http.StatusOK}, r, info.Name(), info.ModTime(), f)
+}
+
+// complete closes done once a response that covers the complete file has been sent
+func (t *Server) complete() {
+	t.doneOnce.Do(func() {
+		close(t.done)
+	})
+}
+
+// countingWriter counts the bytes of the body of a single response. Partial responses and retries are
+// not added up; only a response of the complete file that was written to the end completes the download.
+type countingWriter struct {
+	http.ResponseWriter
+	server  *Server
+	status  int
+	written int64
+}
+
+func (t *countingWriter) WriteHeader(status int) {
+	t.status = status
+	t.ResponseWriter.WriteHeader(status)
+}
+
+// full returns true if the response covers the complete file
+func (t *countingWriter) full() bool {
+
+	switch t.status {
+
+	case http.StatusOK:
+		return true
+
+	case http.StatusPartialContent:
+		contentRange := fmt.Sprintf("bytes 0-%d/%d", t.server.size-1, t.server.size)
+		return t.Header().Get("Content-Range") == contentRange
+	}
+
+	return false
+}
+
+func (t *countingWriter) Write(b []byte) (int, error) {
+
+	n, err := t.ResponseWriter.Write(b)
+	t.written = t.written + int64(n)
+
+	if err == nil && t.written >= t.server.size && t.full() {
+		t.server.complete()
+	}
+
+	return n, err
+}
@@ -139,6 +139,11 @@ func NewCmd(callback callback) *cobra.Command {
 				}
 			}
 
+			err = config.Validate()
+			if err != nil {
+				return err
+			}
+
 			report, err := client.SetConfig(cmd.Context(), *switchID, config)
 			if err != nil {
 				return err
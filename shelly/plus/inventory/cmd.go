@@ -0,0 +1,70 @@
+package inventory
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+type callback interface {
+	WriteObject(any) error
+}
+
+func NewCmd(callback callback) *cobra.Command {
+
+	var dirArg string
+
+	rootCmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Local inventory of backed up and replaced devices",
+	}
+
+	rootCmd.PersistentFlags().StringVar(&dirArg, "dir", "", "inventory directory; default is shelly-manager in the user config dir")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the devices in the inventory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			store, err := New(&Config{Dir: dirArg})
+			if err != nil {
+				return err
+			}
+
+			inventory, err := store.List()
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(inventory)
+		},
+	}
+
+	getCmd := &cobra.Command{
+		Use:   "get <device-id>",
+		Short: "Returns the entry of the device",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			store, err := New(&Config{Dir: dirArg})
+			if err != nil {
+				return err
+			}
+
+			entry, err := store.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			if entry == nil {
+				return fmt.Errorf("device %s is not in the inventory", args[0])
+			}
+
+			return callback.WriteObject(entry)
+		},
+	}
+
+	rootCmd.AddCommand(listCmd, getCmd)
+
+	return rootCmd
+}
@@ -0,0 +1,6 @@
+package inventory
+
+const (
+	// InventoryFile name of the inventory file in the directory
+	InventoryFile = "inventory.json"
+)
@@ -0,0 +1,156 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	// Dir holding the inventory. Default is DefaultDir()
+	Dir string
+}
+
+// Store keeps the inventory of devices in a file. Entries are keyed by device id.
+type Store struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// DefaultDir returns the default inventory directory in the user config dir
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shelly-manager"), nil
+}
+
+func New(config *Config) (*Store, error) {
+
+	t := &Store{
+		dir: config.Dir,
+	}
+
+	if t.dir == "" {
+		dir, err := DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		t.dir = dir
+	}
+
+	return t, nil
+}
+
+// List returns the entries sorted by device id
+func (t *Store) List() (*Inventory, error) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.read()
+}
+
+// Get returns the entry of the device or nil if the device is not in the inventory
+func (t *Store) Get(deviceID string) (*Entry, error) {
+
+	inventory, err := t.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range inventory.Entries {
+		if entry.DeviceID == deviceID {
+			return entry, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Update calls update with the entry of the device and writes the result. A new entry is passed if the
+// device is not in the inventory.
+func (t *Store) Update(deviceID string, update func(entry *Entry)) (*Entry, error) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	inventory, err := t.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *Entry
+	for _, existing := range inventory.Entries {
+		if existing.DeviceID == deviceID {
+			entry = existing
+		}
+	}
+
+	if entry == nil {
+		entry = &Entry{DeviceID: deviceID}
+		inventory.Entries = append(inventory.Entries, entry)
+	}
+
+	update(entry)
+	entry.DeviceID = deviceID
+	entry.Updated = time.Now().UTC()
+
+	err = t.write(inventory)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (t *Store) read() (*Inventory, error) {
+
+	inventory := &Inventory{
+		Entries: []*Entry{},
+	}
+
+	b, err := os.ReadFile(filepath.Join(t.dir, InventoryFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return inventory, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, inventory)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", InventoryFile, err)
+	}
+
+	sort.Slice(inventory.Entries, func(i, j int) bool {
+		return inventory.Entries[i].DeviceID < inventory.Entries[j].DeviceID
+	})
+
+	return inventory, nil
+}
+
+func (t *Store) write(inventory *Inventory) error {
+
+	sort.Slice(inventory.Entries, func(i, j int) bool {
+		return inventory.Entries[i].DeviceID < inventory.Entries[j].DeviceID
+	})
+
+	b, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(t.dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(t.dir, InventoryFile), b, 0600)
+}
@@ -0,0 +1,29 @@
+package inventory
+
+import (
+	"time"
+)
+
+// Entry is a device in the inventory
+type Entry struct {
+	DeviceID string `json:"device_id" yaml:"device_id"`
+	MAC      string `json:"mac" yaml:"mac"`
+	Model    string `json:"model" yaml:"model"`
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	// Version of the firmware when the entry was updated
+	Version string `json:"ver,omitempty" yaml:"ver,omitempty"`
+	// LastBackup file of the latest backup in the backup store
+	LastBackup string `json:"last_backup,omitempty" yaml:"last_backup,omitempty"`
+	// Replaces id of the device this device replaced
+	Replaces string `json:"replaces,omitempty" yaml:"replaces,omitempty"`
+	// ReplacedBy id of the device that replaced this device
+	ReplacedBy string `json:"replaced_by,omitempty" yaml:"replaced_by,omitempty"`
+	// Updated time the entry was last changed
+	Updated time.Time `json:"updated" yaml:"updated"`
+}
+
+// Inventory is the list of known devices
+type Inventory struct {
+	Entries []*Entry `json:"entries" yaml:"entries"`
+}
@@ -0,0 +1,179 @@
+package kvs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ItemsResponse internal use only
+type ItemsResponse struct {
+	Response
+	Result *Items `json:"result,omitempty"`
+}
+
+// ReportResponse internal use only
+type ReportResponse struct {
+	Response
+	Result *Report `json:"result,omitempty"`
+}
+
+func New(messageHandlerFactory MessageHandlerFactory) *Client {
+	return &Client{
+		MessageHandlerFactory: messageHandlerFactory,
+	}
+}
+
+type Client struct {
+	MessageHandlerFactory
+	_messageHandler MessageHandler
+}
+
+func (t *Client) getMessageHandler() MessageHandler {
+	if t._messageHandler != nil {
+		return t._messageHandler
+	}
+
+	t._messageHandler = t.NewHandle()
+	return t._messageHandler
+}
+
+// GetMany returns the keys and values that match the pattern, for example MatchAll. Firmware that pages the
+// result is asked for the remaining pages until all items are returned.
+func (t *Client) GetMany(ctx context.Context, match string) (*Items, error) {
+
+	result := &Items{
+		Items: make(map[string]*Item),
+	}
+
+	offset := 0
+
+	for {
+
+		page, err := t.getMany(ctx, match, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, item := range page.Items {
+			result.Items[key] = item
+		}
+
+		offset = offset + len(page.Items)
+
+		if page.Total == nil || len(page.Items) == 0 || offset >= *page.Total {
+			return result, nil
+		}
+	}
+}
+
+func (t *Client) getMany(ctx context.Context, match string, offset int) (*Items, error) {
+
+	method := Component + ".GetMany"
+
+	params := &Params{
+		Match: &match,
+	}
+
+	if offset > 0 {
+		params.Offset = &offset
+	}
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: params,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ItemsResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// Set sets the value of the key. The key is created if it does not exist.
+func (t *Client) Set(ctx context.Context, key string, value any) (*Report, error) {
+
+	method := Component + ".Set"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			Key:   &key,
+			Value: value,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// Delete deletes the key
+func (t *Client) Delete(ctx context.Context, key string) (*Report, error) {
+
+	method := Component + ".Delete"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			Key: &key,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+func (t *Client) Close() {
+	if t._messageHandler != nil {
+		t._messageHandler.Close()
+	}
+}
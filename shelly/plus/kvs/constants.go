@@ -0,0 +1,7 @@
+package kvs
+
+const (
+	Component = "KVS"
+	// MatchAll matches every key
+	MatchAll = "*"
+)
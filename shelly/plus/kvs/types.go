@@ -0,0 +1,16 @@
+package kvs
+
+import (
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+type Item = types.KVSItem
+type Items = types.KVSItems
+type Params = types.KVSParams
+type Report = types.KVSReport
+
+type Error = types.Error
+type Request = types.Request
+type Response = types.Response
+type MessageHandlerFactory = types.MessageHandlerFactory
+type MessageHandler = types.MessageHandler
@@ -11,6 +11,7 @@ import (
 type Request = types.Request
 type MessageHandlerFactory = types.MessageHandlerFactory
 type MessageHandler = types.MessageHandler
+type NotificationHandler = types.NotificationHandler
 
 type Config struct {
 	Hostname     string
@@ -2,9 +2,12 @@ package ws
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"sync"
 	"time"
 
@@ -18,6 +21,8 @@ const (
 	WsScheme         = "ws"
 	WsPath           = "/rpc"
 	FailWaitDuration = time.Duration(3) * time.Second
+	// SrcPrefix prefix of the src sent with each request. The device sends notifications to the src.
+	SrcPrefix = "shelly-manager-"
 )
 
 var defaultSendTimeout = time.Duration(time.Second * 15)
@@ -28,6 +33,8 @@ type Request = types.Request
 type AuthResponse = types.AuthResponse
 type AuthRequest = types.AuthRequest
 type Response = types.Response
+type Notification = types.Notification
+type NotificationHandler = types.NotificationHandler
 
 type Config interface {
 	GetHostname() string
@@ -45,10 +52,14 @@ type Client struct {
 	handleMap      map[int]*Handle
 	egressMessages chan []byte
 	uniqID         int
+	src            string
+	subscribers    map[int]NotificationHandler
+	subscriberID   int
 	wg             sync.WaitGroup
 	cancel         context.CancelFunc
 	sendTimeout    time.Duration
 	debugEnabled   bool
+	connErr        error
 }
 
 func New(config Config) (MessageHandlerFactory, error) {
@@ -60,6 +71,8 @@ func New(config Config) (MessageHandlerFactory, error) {
 		username:       config.GetUsername(),
 		sendTimeout:    config.GetSendTimeout(),
 		handleMap:      make(map[int]*Handle),
+		subscribers:    make(map[int]NotificationHandler),
+		src:            SrcPrefix + getRandomID(),
 		egressMessages: make(chan []byte, 50),
 		debugEnabled:   config.IsDebugEnabled(),
 	}
@@ -106,6 +119,11 @@ func (t *Client) run() {
 			return
 		}
 
+		if msg.ID == 0 {
+			t.routeNotification(b)
+			return
+		}
+
 		t.mutex.RLock()
 		defer t.mutex.RUnlock()
 
@@ -115,70 +133,92 @@ func (t *Client) run() {
 			return
 		}
 
-		handle.receive <- &responseWrapper{
+		// A handle that is closed or no longer waiting must not block the ingress
+		select {
+		case handle.receive <- &responseWrapper{
 			response: msg,
 			rawBytes: b,
+		}:
+		case <-handle.done:
+		case <-ctx.Done():
 		}
 	}
 
-	handleEgress := func(conn *gorilla.Conn, errs chan error) {
-
-		go func() {
-			for {
-				select {
-				case <-ctx.Done():
-					conn.WriteMessage(gorilla.CloseMessage, gorilla.FormatCloseMessage(gorilla.CloseNormalClosure, ""))
-					return
-
-				case b := <-t.egressMessages:
-
-					if t.debugEnabled {
-						zap.L().Debug(fmt.Sprintf("TX->%s", string(b)))
-					}
-
-					err := conn.WriteMessage(gorilla.BinaryMessage, b)
-					if err != nil {
-						errs <- err
-						return
-					}
-				}
-			}
-
-		}()
-	}
+	handleEgress := func(connCtx context.Context, conn *gorilla.Conn, errs chan error) {
+		for {
+			select {
+			case <-connCtx.Done():
+				conn.WriteMessage(gorilla.CloseMessage, gorilla.FormatCloseMessage(gorilla.CloseNormalClosure, ""))
+				return
 
-	handleIngress := func(conn *gorilla.Conn, errs chan error) {
-		go func() {
-			for {
-				_, b, err := conn.ReadMessage()
+			case b := <-t.egressMessages:
 
 				if t.debugEnabled {
-					zap.L().Debug(fmt.Sprintf("RX->%s", string(b)))
+					zap.L().Debug(fmt.Sprintf("TX->%s", string(b)))
 				}
 
+				err := conn.WriteMessage(gorilla.BinaryMessage, b)
 				if err != nil {
 					errs <- err
 					return
 				}
+			}
+		}
+	}
 
-				routeMessage(b)
+	handleIngress := func(conn *gorilla.Conn, errs chan error) {
+		for {
+			_, b, err := conn.ReadMessage()
+
+			if t.debugEnabled {
+				zap.L().Debug(fmt.Sprintf("RX->%s", string(b)))
 			}
-		}()
+
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			routeMessage(b)
+		}
 	}
 
+	// handle runs the ingress and egress of the connection. The connection is closed and both have
+	// stopped before it returns so that a new connection does not share the egress queue with them.
 	handle := func(conn *gorilla.Conn) error {
+
+		// Each goroutine sends at most one error
 		errs := make(chan error, 2)
-		defer close(errs)
-		handleIngress(conn, errs)
-		handleEgress(conn, errs)
+
+		connCtx, connCancel := context.WithCancel(ctx)
+		var connWg sync.WaitGroup
+
+		connWg.Add(2)
+
+		go func() {
+			defer connWg.Done()
+			handleIngress(conn, errs)
+		}()
+
+		go func() {
+			defer connWg.Done()
+			handleEgress(connCtx, conn, errs)
+		}()
+
+		var err error
 
 		select {
 		case <-ctx.Done():
-			return nil
-		case err := <-errs:
-			return err
+		case err = <-errs:
+			t.setConnErr(err)
 		}
 
+		// Stopping the egress first lets it send the close message; closing the connection stops the ingress
+		connCancel()
+		conn.Close()
+		connWg.Wait()
+
+		return err
 	}
 
 	connect := func() error {
@@ -189,11 +229,14 @@ func (t *Client) run() {
 			return err
 		}
 
+		t.setConnErr(nil)
+
 		return handle(conn)
 	}
 
+	t.wg.Add(1)
+
 	go func() {
-		t.wg.Add(1)
 		defer t.wg.Done()
 
 		for {
@@ -225,6 +268,64 @@ func (t *Client) run() {
 
 }
 
+// setConnErr records the error that ended the connection or nil once connected again
+func (t *Client) setConnErr(err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.connErr = err
+}
+
+// timeoutErr returns the error for a request without a response. The error that ended the connection
+// is returned if the connection was lost, otherwise a timeout. Both are transport errors callers can
+// tell apart from an error returned by the device.
+func (t *Client) timeoutErr() error {
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.connErr != nil {
+		return fmt.Errorf("connection lost waiting for response: %w", t.connErr)
+	}
+
+	return fmt.Errorf("timeout waiting for response: %w", os.ErrDeadlineExceeded)
+}
+
+// routeNotification passes a message without an ID to the subscribers
+func (t *Client) routeNotification(b []byte) {
+
+	notification := &Notification{}
+	err := json.Unmarshal(b, notification)
+	if err != nil || notification.Method == "" {
+		zap.L().Debug("dropping message without ID")
+		return
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	for _, handler := range t.subscribers {
+		handler(notification)
+	}
+}
+
+// Subscribe registers a handler for notifications. The handler is called from the receive loop and
+// must not block. The returned function removes the handler.
+func (t *Client) Subscribe(handler NotificationHandler) func() {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.subscriberID = t.subscriberID + 1
+	id := t.subscriberID
+	t.subscribers[id] = handler
+
+	return func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		delete(t.subscribers, id)
+	}
+}
+
 func (t *Client) NewHandle() MessageHandler {
 
 	zap.L().Debug("(*Client) NewHandle()")
@@ -251,7 +352,6 @@ func (t *Handle) Close() {
 	zap.L().Debug("(*Handle) Close()")
 
 	close(t.done)
-	close(t.receive)
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 	delete(t.handleMap, t.id)
@@ -275,6 +375,7 @@ func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 
 	request = request.Clone()
 	request.ID = t.id
+	request.Src = t.src
 
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
@@ -297,7 +398,7 @@ func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 			return nil, fmt.Errorf("channel closed by client")
 
 		case <-time.After(t.sendTimeout):
-			return nil, fmt.Errorf("timeout waiting for response")
+			return nil, t.timeoutErr()
 
 		}
 
@@ -361,3 +462,9 @@ func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 
 	return response.rawBytes, nil
 }
+
+func getRandomID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,341 @@
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+type Config struct {
+	// Dir holding the CA and the index. Default is DefaultDir()
+	Dir string
+	// KeyType of issued device keys; ecdsa (default) or rsa
+	KeyType string
+	// Validity of issued device certificates. Default is DefaultCertValidity
+	Validity time.Duration
+	// Create the CA if neither the CA cert nor the CA key exist
+	Create bool
+}
+
+// CA is a minimal local certificate authority that issues client certificates for devices. The CA key
+// and the index are kept in the PKI directory; device keys are only sent to the device.
+type CA struct {
+	dir      string
+	keyType  string
+	validity time.Duration
+	created  bool
+	cert     *x509.Certificate
+	certPEM  []byte
+	key      crypto.Signer
+	mutex    sync.Mutex
+}
+
+// DefaultDir returns the default PKI directory in the user config dir
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shelly-manager", "pki"), nil
+}
+
+// New loads the CA from the PKI directory. The CA is created if config.Create is true and neither the CA
+// cert nor the CA key exist. An error is returned if only one of them exists so that an existing CA is
+// never replaced.
+func New(config *Config) (*CA, error) {
+
+	t := &CA{
+		dir:      config.Dir,
+		keyType:  config.KeyType,
+		validity: config.Validity,
+	}
+
+	if t.dir == "" {
+		dir, err := DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		t.dir = dir
+	}
+
+	switch t.keyType {
+
+	case "":
+		t.keyType = KeyTypeECDSA
+
+	case KeyTypeECDSA, KeyTypeRSA:
+
+	default:
+		return nil, fmt.Errorf("key type %s is not supported; expect %s or %s", t.keyType, KeyTypeECDSA, KeyTypeRSA)
+
+	}
+
+	if t.validity <= 0 {
+		t.validity = DefaultCertValidity
+	}
+
+	certExists, err := fileExists(filepath.Join(t.dir, CAFile))
+	if err != nil {
+		return nil, err
+	}
+
+	keyExists, err := fileExists(filepath.Join(t.dir, CAKeyFile))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+
+	case !certExists && !keyExists:
+		if !config.Create {
+			return nil, fmt.Errorf("CA does not exist in %s; create it with pki init", t.dir)
+		}
+		err = t.create()
+
+	case !keyExists:
+		return nil, fmt.Errorf("%s exists but %s is missing in %s", CAFile, CAKeyFile, t.dir)
+
+	case !certExists:
+		return nil, fmt.Errorf("%s exists but %s is missing in %s", CAKeyFile, CAFile, t.dir)
+
+	default:
+		err = t.load()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// fileExists returns true if the file exists
+func fileExists(name string) (bool, error) {
+
+	_, err := os.Stat(name)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func (t *CA) load() error {
+
+	certPEM, err := os.ReadFile(filepath.Join(t.dir, CAFile))
+	if err != nil {
+		return err
+	}
+
+	keyPEM, err := os.ReadFile(filepath.Join(t.dir, CAKeyFile))
+	if err != nil {
+		return err
+	}
+
+	certs, err := util.ParseCertificates(certPEM)
+	if err != nil {
+		return fmt.Errorf("%s :: %w", CAFile, err)
+	}
+
+	key, err := util.ParsePrivateKey(keyPEM)
+	if err != nil {
+		return fmt.Errorf("%s :: %w", CAKeyFile, err)
+	}
+
+	if !util.KeyMatchesCertificate(certs[0], key) {
+		return fmt.Errorf("%s does not match %s", CAKeyFile, CAFile)
+	}
+
+	t.cert = certs[0]
+	t.certPEM = certPEM
+	t.key = key
+
+	zap.L().Debug(fmt.Sprintf("loaded CA %s from %s", t.cert.Subject.String(), t.dir))
+
+	return nil
+}
+
+func (t *CA) create() error {
+
+	zap.L().Debug(fmt.Sprintf("creating CA in %s", t.dir))
+
+	err := os.MkdirAll(t.dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: CACommonName},
+		NotBefore:             now.Add(-ClockSkew),
+		NotAfter:              now.Add(CAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+
+	err = os.WriteFile(filepath.Join(t.dir, CAKeyFile), keyPEM, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(t.dir, CAFile), certPEM, 0644)
+	if err != nil {
+		return err
+	}
+
+	t.cert = cert
+	t.certPEM = certPEM
+	t.key = key
+	t.created = true
+
+	return nil
+}
+
+// CertPEM returns the CA certificate in PEM format
+func (t *CA) CertPEM() []byte {
+	return t.certPEM
+}
+
+// Info returns information about the CA
+func (t *CA) Info() *Info {
+	return &Info{
+		Dir:      t.dir,
+		Subject:  t.cert.Subject.String(),
+		Serial:   t.cert.SerialNumber.Text(16),
+		NotAfter: t.cert.NotAfter,
+		Created:  t.created,
+	}
+}
+
+// Issue creates a new key and a client certificate with the device ID as common name
+func (t *CA) Issue(deviceID string) (*Issued, error) {
+
+	if deviceID == "" {
+		return nil, fmt.Errorf("device ID is required")
+	}
+
+	var key crypto.Signer
+	var keyBlock *pem.Block
+
+	switch t.keyType {
+
+	case KeyTypeRSA:
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		key = rsaKey
+		keyBlock = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}
+
+	default:
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalECPrivateKey(ecKey)
+		if err != nil {
+			return nil, err
+		}
+		key = ecKey
+		keyBlock = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	notAfter := now.Add(t.validity)
+	if notAfter.After(t.cert.NotAfter) {
+		notAfter = t.cert.NotAfter
+	}
+
+	keyUsage := x509.KeyUsageDigitalSignature
+	if t.keyType == KeyTypeRSA {
+		keyUsage = keyUsage | x509.KeyUsageKeyEncipherment
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: deviceID},
+		NotBefore:    now.Add(-ClockSkew),
+		NotAfter:     notAfter,
+		KeyUsage:     keyUsage,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, t.cert, key.Public(), t.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Issued{
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(keyBlock),
+		Entry: &Entry{
+			DeviceID:  deviceID,
+			Serial:    serial.Text(16),
+			NotBefore: template.NotBefore,
+			NotAfter:  template.NotAfter,
+		},
+	}, nil
+}
+
+func newSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
@@ -0,0 +1,221 @@
+package pki
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+)
+
+type callback interface {
+	GetHostname() string
+	WriteObject(any) error
+	WriteStderr(string)
+	Dial(hostname string) (Device, error)
+}
+
+func NewCmd(callback callback) *cobra.Command {
+
+	var dirArg string
+	var keyTypeArg string
+	var validityArg time.Duration
+	var mqttArg bool
+	var mqttCAArg string
+	var websocketArg bool
+	var autorebootArg bool
+	var expiringArg time.Duration
+	var withinArg time.Duration
+
+	rootCmd := &cobra.Command{
+		Use:   "pki",
+		Short: "Local CA for device client certificates",
+	}
+
+	rootCmd.PersistentFlags().StringVar(&dirArg, "dir", "", "PKI directory; default is shelly-manager/pki in the user config dir")
+	rootCmd.PersistentFlags().StringVar(&keyTypeArg, "key-type", KeyTypeECDSA, "type of the device keys; ecdsa or rsa")
+	rootCmd.PersistentFlags().DurationVar(&validityArg, "validity", DefaultCertValidity, "validity of issued device certificates")
+
+	// getCA loads the CA; create is only set by the commands that may create a new CA
+	getCA := func(create bool) (*CA, error) {
+		return New(&Config{
+			Dir:      dirArg,
+			KeyType:  keyTypeArg,
+			Validity: validityArg,
+			Create:   create,
+		})
+	}
+
+	reboot := func(cmd *cobra.Command, device Device, report *ProvisionReport) error {
+
+		if !report.RestartRequired {
+			return nil
+		}
+
+		if autorebootArg {
+			callback.WriteStderr(fmt.Sprintf("%s: reboot is required; rebooting ...", report.DeviceID))
+			return device.Shelly().Reboot(cmd.Context())
+		}
+
+		callback.WriteStderr(fmt.Sprintf("%s: reboot is required!", report.DeviceID))
+		return nil
+	}
+
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Creates the CA if it does not exist and returns its info",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			ca, err := getCA(true)
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(ca.Info())
+		},
+	}
+
+	provisionCmd := &cobra.Command{
+		Use:   "provision",
+		Short: "Issues a client cert for the device and uploads the CA, cert and key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			ca, err := getCA(true)
+			if err != nil {
+				return err
+			}
+
+			hostname := callback.GetHostname()
+
+			device, err := callback.Dial(hostname)
+			if err != nil {
+				return err
+			}
+			defer device.Close()
+
+			report, err := ca.Provision(cmd.Context(), device, &ProvisionParams{
+				Hostname:  hostname,
+				Mqtt:      mqttArg,
+				MqttCA:    mqttCAArg,
+				Websocket: websocketArg,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = reboot(cmd, device, report)
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(report)
+		},
+	}
+
+	provisionCmd.PersistentFlags().BoolVar(&mqttArg, "mqtt", false, "set MQTT to use the client cert")
+	provisionCmd.PersistentFlags().StringVar(&mqttCAArg, "mqtt-ca", "", "set the CA MQTT verifies the broker with; "+MqttCAUser+" selects the user CA. Default leaves it unchanged")
+	provisionCmd.PersistentFlags().BoolVar(&websocketArg, "websocket", false, "set the outbound websocket to verify with the user CA")
+	provisionCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the issued device certs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			ca, err := getCA(false)
+			if err != nil {
+				return err
+			}
+
+			if expiringArg > 0 {
+				entries, err := ca.Expiring(expiringArg)
+				if err != nil {
+					return err
+				}
+				return callback.WriteObject(&Index{Entries: entries})
+			}
+
+			index, err := ca.Index()
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(index)
+		},
+	}
+
+	listCmd.PersistentFlags().DurationVar(&expiringArg, "expiring", 0, "only list certs that expire within the duration")
+
+	rotate := func(cmd *cobra.Command, ca *CA, entry *Entry) (*ProvisionReport, error) {
+
+		device, err := callback.Dial(entry.Hostname)
+		if err != nil {
+			return nil, err
+		}
+		defer device.Close()
+
+		report, err := ca.Provision(cmd.Context(), device, &ProvisionParams{
+			Hostname:  entry.Hostname,
+			Mqtt:      entry.Mqtt,
+			Websocket: entry.Websocket,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if report.DeviceID != entry.DeviceID {
+			callback.WriteStderr(fmt.Sprintf("Warning: %s is now device %s", entry.Hostname, report.DeviceID))
+		}
+
+		return report, reboot(cmd, device, report)
+	}
+
+	rotateCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Issues and uploads new certs for all devices with certs that expire soon",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			ca, err := getCA(false)
+			if err != nil {
+				return err
+			}
+
+			entries, err := ca.Expiring(withinArg)
+			if err != nil {
+				return err
+			}
+
+			var errors *multierror.Error
+			var reports []*ProvisionReport
+
+			for _, entry := range entries {
+
+				if entry.Hostname == "" {
+					errors = multierror.Append(errors, fmt.Errorf("%s :: hostname is unknown", entry.DeviceID))
+					continue
+				}
+
+				report, err := rotate(cmd, ca, entry)
+				if err != nil {
+					errors = multierror.Append(errors, fmt.Errorf("%s :: %w", entry.DeviceID, err))
+					continue
+				}
+
+				reports = append(reports, report)
+			}
+
+			err = callback.WriteObject(reports)
+			if err != nil {
+				return err
+			}
+
+			return errors.ErrorOrNil()
+		},
+	}
+
+	rotateCmd.PersistentFlags().DurationVar(&withinArg, "within", DefaultRenewBefore, "rotate certs that expire within the duration")
+	rotateCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+
+	rootCmd.AddCommand(initCmd, provisionCmd, listCmd, rotateCmd)
+	return rootCmd
+}
@@ -0,0 +1,35 @@
+package pki
+
+import "time"
+
+const (
+	// CAFile name of the CA certificate file in the PKI directory
+	CAFile = "ca.pem"
+	// CAKeyFile name of the CA private key file in the PKI directory
+	CAKeyFile = "ca-key.pem"
+	// IndexFile name of the file that tracks the issued device certificates
+	IndexFile = "index.json"
+	// CACommonName common name of a newly created CA
+	CACommonName = "Shelly Manager CA"
+	// UserCA is the value of ssl_ca that makes the device verify the server with the uploaded user CA
+	UserCA = "user_ca.pem"
+	// MqttCAUser selects the user CA for the MQTT component in ProvisionParams
+	MqttCAUser = "user"
+
+	KeyTypeECDSA = "ecdsa"
+	KeyTypeRSA   = "rsa"
+)
+
+var (
+	// CAValidity is the validity of a newly created CA
+	CAValidity = time.Duration(10*365*24) * time.Hour
+
+	// DefaultCertValidity is the default validity of an issued device certificate
+	DefaultCertValidity = time.Duration(365*24) * time.Hour
+
+	// DefaultRenewBefore is the default duration before expiry at which a device certificate is rotated
+	DefaultRenewBefore = time.Duration(30*24) * time.Hour
+
+	// ClockSkew is subtracted from the start of the validity to allow for devices with a clock that is behind
+	ClockSkew = time.Duration(5) * time.Minute
+)
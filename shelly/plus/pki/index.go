@@ -0,0 +1,107 @@
+package pki
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Index returns the issued device certificates sorted by expiry
+func (t *CA) Index() (*Index, error) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.readIndex()
+}
+
+// Expiring returns the issued device certificates that expire within the given duration
+func (t *CA) Expiring(within time.Duration) ([]*Entry, error) {
+
+	index, err := t.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(within)
+
+	var entries []*Entry
+	for _, entry := range index.Entries {
+		if entry.NotAfter.Before(deadline) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// Record adds the entry to the index. An existing entry for the same device is replaced.
+func (t *CA) Record(entry *Entry) error {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	index, err := t.readIndex()
+	if err != nil {
+		return err
+	}
+
+	entries := []*Entry{entry}
+	for _, existing := range index.Entries {
+		if existing.DeviceID != entry.DeviceID {
+			entries = append(entries, existing)
+		}
+	}
+
+	index.Entries = entries
+
+	return t.writeIndex(index)
+}
+
+func (t *CA) readIndex() (*Index, error) {
+
+	index := &Index{}
+
+	b, err := os.ReadFile(filepath.Join(t.dir, IndexFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return index, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, index)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(index.Entries, func(i, j int) bool {
+		return index.Entries[i].NotAfter.Before(index.Entries[j].NotAfter)
+	})
+
+	return index, nil
+}
+
+func (t *CA) writeIndex(index *Index) error {
+
+	sort.SliceStable(index.Entries, func(i, j int) bool {
+		return index.Entries[i].NotAfter.Before(index.Entries[j].NotAfter)
+	})
+
+	b, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(t.dir, IndexFile+".tmp")
+
+	err = os.WriteFile(tmp, b, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(t.dir, IndexFile))
+}
@@ -0,0 +1,115 @@
+package pki
+
+import (
+	"context"
+	"fmt"
+)
+
+// Provision issues a client certificate for the device and uploads the CA, the key and the certificate.
+// Depending on params the MQTT component and the outbound websocket are set to use them. The
+// certificate is recorded in the index.
+func (t *CA) Provision(ctx context.Context, device Device, params *ProvisionParams) (*ProvisionReport, error) {
+
+	switch params.MqttCA {
+	case "":
+	case MqttCAUser:
+		if !params.Mqtt {
+			return nil, fmt.Errorf("MQTT CA %s requires MQTT", params.MqttCA)
+		}
+	default:
+		return nil, fmt.Errorf("MQTT CA %s is not supported; use %s", params.MqttCA, MqttCAUser)
+	}
+
+	client := device.Shelly()
+
+	info, err := client.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := t.Issue(info.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	issued.Entry.Hostname = params.Hostname
+	issued.Entry.Mqtt = params.Mqtt
+	issued.Entry.Websocket = params.Websocket
+
+	report := &ProvisionReport{
+		DeviceID: issued.Entry.DeviceID,
+		Hostname: issued.Entry.Hostname,
+		Serial:   issued.Entry.Serial,
+		NotAfter: issued.Entry.NotAfter,
+	}
+
+	report.UserCA, err = client.UploadUserCA(ctx, t.certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("user CA :: %w", err)
+	}
+
+	report.TLSClientKey, err = client.UploadTLSClientKey(ctx, issued.KeyPEM, issued.CertPEM)
+	if err != nil {
+		return nil, fmt.Errorf("TLS client key :: %w", err)
+	}
+
+	report.TLSClientCert, err = client.UploadTLSClientCert(ctx, issued.CertPEM, issued.KeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("TLS client cert :: %w", err)
+	}
+
+	err = t.Record(issued.Entry)
+	if err != nil {
+		return nil, err
+	}
+
+	userCA := UserCA
+
+	if params.Mqtt {
+
+		config, err := device.Mqtt().GetConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Mqtt :: %w", err)
+		}
+
+		config.UseClientCert = true
+
+		// The user CA only verifies brokers with a certificate issued by it, so ssl_ca is only
+		// changed when asked
+		if params.MqttCA == MqttCAUser {
+			config.SslCa = &userCA
+		}
+
+		report.Mqtt, err = device.Mqtt().SetConfig(ctx, config)
+		if err != nil {
+			return nil, fmt.Errorf("Mqtt :: %w", err)
+		}
+	}
+
+	if params.Websocket {
+
+		config, err := device.Websocket().GetConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Websocket :: %w", err)
+		}
+
+		config.SslCa = &userCA
+
+		report.Websocket, err = device.Websocket().SetConfig(ctx, config)
+		if err != nil {
+			return nil, fmt.Errorf("Websocket :: %w", err)
+		}
+	}
+
+	for _, certReport := range []*ShellyCertReport{report.UserCA, report.TLSClientKey, report.TLSClientCert} {
+		report.RestartRequired = report.RestartRequired || certReport.RestartRequired
+	}
+
+	for _, setReport := range []*SetReport{report.Mqtt, report.Websocket} {
+		if setReport != nil {
+			report.RestartRequired = report.RestartRequired || setReport.RestartRequired
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,83 @@
+package pki
+
+import (
+	"time"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/mqtt"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/websocket"
+)
+
+type SetReport = types.SetReport
+type ShellyCertReport = types.ShellyCertReport
+
+// Device is the set of component clients needed to provision a device
+type Device interface {
+	Shelly() *shelly.Client
+	Mqtt() *mqtt.Client
+	Websocket() *websocket.Client
+	Close()
+}
+
+// Info describes the CA
+type Info struct {
+	Dir      string    `json:"dir" yaml:"dir"`
+	Subject  string    `json:"subject" yaml:"subject"`
+	Serial   string    `json:"serial" yaml:"serial"`
+	NotAfter time.Time `json:"not_after" yaml:"not_after"`
+	// Created true if the CA was created by this call
+	Created bool `json:"created,omitempty" yaml:"created,omitempty"`
+}
+
+// Entry is an issued device certificate as tracked in the index
+type Entry struct {
+	DeviceID  string    `json:"device_id" yaml:"device_id"`
+	Hostname  string    `json:"hostname" yaml:"hostname"`
+	Serial    string    `json:"serial" yaml:"serial"`
+	NotBefore time.Time `json:"not_before" yaml:"not_before"`
+	NotAfter  time.Time `json:"not_after" yaml:"not_after"`
+	// Mqtt true if the MQTT component was set to use the client certificate
+	Mqtt bool `json:"mqtt" yaml:"mqtt"`
+	// Websocket true if the outbound websocket was set to verify with the user CA
+	Websocket bool `json:"websocket" yaml:"websocket"`
+}
+
+// Index is the list of issued device certificates, one per device
+type Index struct {
+	Entries []*Entry `json:"entries" yaml:"entries"`
+}
+
+// Issued is a newly issued device certificate and key
+type Issued struct {
+	CertPEM []byte
+	KeyPEM  []byte
+	Entry   *Entry
+}
+
+// ProvisionParams selects what is configured on the device in addition to the upload
+type ProvisionParams struct {
+	// Hostname of the device; recorded in the index so the certificate can be rotated later
+	Hostname string
+	// Mqtt enables use_client_cert on the MQTT component
+	Mqtt bool
+	// MqttCA sets ssl_ca of the MQTT component; MqttCAUser selects the uploaded user CA. Empty leaves
+	// ssl_ca unchanged so the broker is verified as before.
+	MqttCA string
+	// Websocket sets ssl_ca to the user CA on the outbound websocket
+	Websocket bool
+}
+
+// ProvisionReport is the result of provisioning a device
+type ProvisionReport struct {
+	DeviceID        string            `json:"device_id" yaml:"device_id"`
+	Hostname        string            `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Serial          string            `json:"serial" yaml:"serial"`
+	NotAfter        time.Time         `json:"not_after" yaml:"not_after"`
+	UserCA          *ShellyCertReport `json:"user_ca,omitempty" yaml:"user_ca,omitempty"`
+	TLSClientCert   *ShellyCertReport `json:"tls_client_cert,omitempty" yaml:"tls_client_cert,omitempty"`
+	TLSClientKey    *ShellyCertReport `json:"tls_client_key,omitempty" yaml:"tls_client_key,omitempty"`
+	Mqtt            *SetReport        `json:"mqtt,omitempty" yaml:"mqtt,omitempty"`
+	Websocket       *SetReport        `json:"websocket,omitempty" yaml:"websocket,omitempty"`
+	RestartRequired bool              `json:"restart_required" yaml:"restart_required"`
+}
@@ -0,0 +1,221 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+)
+
+type callback interface {
+	GetHostname() string
+	WriteObject(any) error
+	WriteStderr(string)
+	Shelly() (*shelly.Client, error)
+	ShellyFor(hostname string) (*shelly.Client, func(), error)
+	RebootDevice(ctx context.Context) error
+}
+
+func NewCmd(callback callback) *cobra.Command {
+
+	var dirArg string
+	var componentArgs []string
+	var descriptionArg string
+	var overwriteArg bool
+	var toArgs []string
+	var dryRunArg bool
+	var autorebootArg bool
+
+	rootCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Local library of named component configs",
+		Long: "Saves the configs of components of a device under a name and applies them to components of the " +
+			"same type on any device. These are local profiles and not the device profiles of set-profile.",
+	}
+
+	rootCmd.PersistentFlags().StringVar(&dirArg, "dir", "", "profile directory; default is shelly-manager/profiles in the user config dir")
+
+	getStore := func() (*Store, error) {
+		return New(&Config{
+			Dir: dirArg,
+		})
+	}
+
+	saveCmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Saves the configs of components of the device as a profile",
+		Long:  "Saves the configs of the components given with --component. The id and name of the components are not saved.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			store, err := getStore()
+			if err != nil {
+				return err
+			}
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			profile, err := Capture(cmd.Context(), client, args[0], componentArgs)
+			if err != nil {
+				return err
+			}
+			profile.Description = descriptionArg
+
+			err = store.Save(profile, overwriteArg)
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(profile)
+		},
+	}
+
+	saveCmd.PersistentFlags().StringArrayVar(&componentArgs, "component", nil, "key of a component to save, for example switch:0; may be repeated")
+	saveCmd.PersistentFlags().StringVar(&descriptionArg, "description", "", "description of the profile")
+	saveCmd.PersistentFlags().BoolVar(&overwriteArg, "overwrite", false, "replace an existing profile of the same name")
+
+	applyCmd := &cobra.Command{
+		Use:   "apply <name>",
+		Short: "Applies a profile to components of the device",
+		Long: "Applies the components of the profile to the components given with --to in the order they were saved, " +
+			"for example a profile saved from switch:0 to switch:1. Without --to the components are applied to the " +
+			"keys they were saved from. Only the fields that differ are written; --dry-run only shows the changes.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			store, err := getStore()
+			if err != nil {
+				return err
+			}
+
+			profile, err := store.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			desired, err := Desired(profile, toArgs)
+			if err != nil {
+				return err
+			}
+
+			err = desired.Validate()
+			if err != nil {
+				return err
+			}
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			plan, err := client.Plan(cmd.Context(), desired)
+			if err != nil {
+				return err
+			}
+
+			if len(plan.Unsupported) > 0 {
+				return fmt.Errorf("device does not have %s", strings.Join(plan.Unsupported, ", "))
+			}
+
+			if dryRunArg {
+				callback.WriteStderr(shelly.FormatPlan(plan))
+				return nil
+			}
+
+			report, applyErr := client.ApplyPlan(cmd.Context(), plan, nil, &shelly.ApplyOptions{
+				Hostname: callback.GetHostname(),
+				Dial:     callback.ShellyFor,
+			})
+			if report == nil {
+				return applyErr
+			}
+
+			if report.RestartRequired {
+				if autorebootArg && applyErr == nil {
+					callback.WriteStderr("reboot is required; rebooting ...")
+					err = callback.RebootDevice(cmd.Context())
+					if err != nil {
+						return err
+					}
+				} else {
+					callback.WriteStderr("reboot is required!")
+				}
+			}
+
+			err = callback.WriteObject(report)
+			if err != nil {
+				return err
+			}
+
+			return applyErr
+		},
+	}
+
+	applyCmd.PersistentFlags().StringArrayVar(&toArgs, "to", nil, "key of the target component, for example switch:1; may be repeated")
+	applyCmd.PersistentFlags().BoolVar(&dryRunArg, "dry-run", false, "only show the changes")
+	applyCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			store, err := getStore()
+			if err != nil {
+				return err
+			}
+
+			summaries, err := store.List()
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(summaries)
+		},
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Returns a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			store, err := getStore()
+			if err != nil {
+				return err
+			}
+
+			profile, err := store.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(profile)
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Deletes a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			store, err := getStore()
+			if err != nil {
+				return err
+			}
+
+			return store.Delete(args[0])
+		},
+	}
+
+	rootCmd.AddCommand(saveCmd, applyCmd, listCmd, showCmd, deleteCmd)
+
+	return rootCmd
+}
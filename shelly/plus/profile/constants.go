@@ -0,0 +1,10 @@
+package profile
+
+const (
+	// FileExt extension of the profile files in the store
+	FileExt = ".json"
+)
+
+// instanceFields are removed from a component config when it is saved as a profile. They describe the
+// instance of the component rather than how it behaves.
+var instanceFields = []string{"id", "name"}
@@ -0,0 +1,130 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/shelly"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+// Capture returns a profile with the configs of the components of the device, for example switch:0. The
+// id and name of each component are not captured.
+func Capture(ctx context.Context, client *shelly.Client, name string, keys []string) (*Profile, error) {
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one component is required")
+	}
+
+	info, err := client.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &Profile{
+		Name:    name,
+		Created: time.Now().UTC(),
+		Source: &Source{
+			DeviceID: info.ID,
+			Model:    info.Model,
+			Version:  info.Version,
+		},
+	}
+
+	for _, key := range keys {
+
+		componentKey, err := types.ParseComponentKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		config, err := client.GetComponentConfig(ctx, componentKey.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s :: %w", key, err)
+		}
+
+		b, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+
+		var configMap map[string]any
+		err = json.Unmarshal(b, &configMap)
+		if err != nil || configMap == nil {
+			return nil, fmt.Errorf("%s :: config is not an object", key)
+		}
+
+		for _, field := range instanceFields {
+			delete(configMap, field)
+		}
+
+		profile.Components = append(profile.Components, &Component{
+			Key:    componentKey.String(),
+			Config: configMap,
+		})
+	}
+
+	return profile, nil
+}
+
+// Desired returns the config that applies the profile. The components are applied to the targets in the
+// order of the profile; without targets they are applied to the keys they were saved from. A target must
+// be a component of the same type, for example a profile saved from switch:0 can be applied to switch:1.
+func Desired(profile *Profile, targets []string) (*ShellyConfig, error) {
+
+	if len(targets) > 0 && len(targets) != len(profile.Components) {
+		return nil, fmt.Errorf("profile %s has %d components and %d targets are given", profile.Name, len(profile.Components), len(targets))
+	}
+
+	desired := make(map[string]any)
+
+	for i, component := range profile.Components {
+
+		from, err := types.ParseComponentKey(component.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		to := from
+		if len(targets) > 0 {
+			to, err = types.ParseComponentKey(targets[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if to.Type != from.Type || to.HasID != from.HasID {
+			return nil, fmt.Errorf("profile component %s can not be applied to %s", from, to)
+		}
+
+		if _, ok := desired[to.String()]; ok {
+			return nil, fmt.Errorf("target %s is given more than once", to)
+		}
+
+		config := make(map[string]any)
+		for key, value := range component.Config {
+			config[key] = value
+		}
+
+		if to.HasID {
+			config["id"] = to.ID
+		}
+
+		desired[to.String()] = config
+	}
+
+	b, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var config *ShellyConfig
+	err = json.Unmarshal(b, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
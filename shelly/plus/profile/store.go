@@ -0,0 +1,171 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Config struct {
+	// Dir holding the profiles. Default is DefaultDir()
+	Dir string
+}
+
+// Store keeps profiles as one file per name
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the default profile directory in the user config dir
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shelly-manager", "profiles"), nil
+}
+
+func New(config *Config) (*Store, error) {
+
+	t := &Store{
+		dir: config.Dir,
+	}
+
+	if t.dir == "" {
+		dir, err := DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		t.dir = dir
+	}
+
+	return t, nil
+}
+
+// Save writes the profile. An existing profile of the same name is only replaced if overwrite is set.
+func (t *Store) Save(profile *Profile, overwrite bool) error {
+
+	file, err := t.file(profile.Name)
+	if err != nil {
+		return err
+	}
+
+	if !overwrite {
+		_, err = os.Stat(file)
+		if err == nil {
+			return fmt.Errorf("profile %s exists", profile.Name)
+		}
+	}
+
+	b, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(t.dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, b, 0600)
+}
+
+// Get returns the profile
+func (t *Store) Get(name string) (*Profile, error) {
+
+	file, err := t.file(name)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("profile %s not found", name)
+		}
+		return nil, err
+	}
+
+	profile := &Profile{}
+	err = json.Unmarshal(b, profile)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", file, err)
+	}
+
+	return profile, nil
+}
+
+// Delete removes the profile
+func (t *Store) Delete(name string) error {
+
+	file, err := t.file(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("profile %s not found", name)
+	}
+
+	return err
+}
+
+// List returns the profiles sorted by name
+func (t *Store) List() ([]*Summary, error) {
+
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []*Summary{}, nil
+		}
+		return nil, err
+	}
+
+	summaries := []*Summary{}
+
+	for _, entry := range entries {
+
+		if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), FileExt) {
+			continue
+		}
+
+		profile, err := t.Get(strings.TrimSuffix(entry.Name(), FileExt))
+		if err != nil {
+			return nil, err
+		}
+
+		summary := &Summary{
+			Name:        profile.Name,
+			Description: profile.Description,
+			Created:     profile.Created,
+			Components:  []string{},
+		}
+
+		for _, component := range profile.Components {
+			summary.Components = append(summary.Components, component.Key)
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries, nil
+}
+
+// file returns the file of the profile. Names are used as file names so they can not contain a path.
+func (t *Store) file(name string) (string, error) {
+
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("profile name %q is not valid", name)
+	}
+
+	return filepath.Join(t.dir, name+FileExt), nil
+}
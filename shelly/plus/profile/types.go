@@ -0,0 +1,46 @@
+package profile
+
+import (
+	"time"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+type ShellyConfig = types.ShellyConfig
+type ShellyPlan = types.ShellyPlan
+type ShellyApplyReport = types.ShellyApplyReport
+
+// Profile is a named set of component configs that can be applied to components of any device
+type Profile struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Created time the profile was saved
+	Created time.Time `json:"created" yaml:"created"`
+	// Source device the profile was saved from
+	Source *Source `json:"source,omitempty" yaml:"source,omitempty"`
+	// Components in the order they were given
+	Components []*Component `json:"components" yaml:"components"`
+}
+
+// Source is the device a profile was saved from
+type Source struct {
+	DeviceID string `json:"device_id" yaml:"device_id"`
+	Model    string `json:"model" yaml:"model"`
+	Version  string `json:"ver" yaml:"ver"`
+}
+
+// Component is the config of a single component. The id and name are not part of it.
+type Component struct {
+	// Key the config was saved from, for example switch:0. It is the default target.
+	Key    string         `json:"key" yaml:"key"`
+	Config map[string]any `json:"config" yaml:"config"`
+}
+
+// Summary is a profile in a list
+type Summary struct {
+	Name        string    `json:"name" yaml:"name"`
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+	Created     time.Time `json:"created" yaml:"created"`
+	// Components keys the configs were saved from
+	Components []string `json:"components" yaml:"components"`
+}
@@ -0,0 +1,212 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ListResponse internal use only
+type ListResponse struct {
+	Response
+	Result *Jobs `json:"result,omitempty"`
+}
+
+// ReportResponse internal use only
+type ReportResponse struct {
+	Response
+	Result *Report `json:"result,omitempty"`
+}
+
+// DeleteParams internal use only
+type DeleteParams struct {
+	ID int `json:"id"`
+}
+
+func New(messageHandlerFactory MessageHandlerFactory) *Client {
+	return &Client{
+		MessageHandlerFactory: messageHandlerFactory,
+	}
+}
+
+type Client struct {
+	MessageHandlerFactory
+	_messageHandler MessageHandler
+}
+
+func (t *Client) getMessageHandler() MessageHandler {
+	if t._messageHandler != nil {
+		return t._messageHandler
+	}
+
+	t._messageHandler = t.NewHandle()
+	return t._messageHandler
+}
+
+// List lists all existing scheduled jobs for this device
+func (t *Client) List(ctx context.Context) (*Jobs, error) {
+
+	method := Component + ".List"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// Create creates a scheduled job. The ID of the job is ignored; the new ID is returned in the report.
+func (t *Client) Create(ctx context.Context, job *Job) (*Report, error) {
+
+	method := Component + ".Create"
+
+	params := job.Clone()
+	params.ID = nil
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: params,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// Update updates an existing scheduled job. The ID of the job is required.
+func (t *Client) Update(ctx context.Context, job *Job) (*Report, error) {
+
+	method := Component + ".Update"
+
+	if job.ID == nil {
+		return nil, fmt.Errorf("ID is required")
+	}
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: job,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// Delete deletes an existing scheduled job
+func (t *Client) Delete(ctx context.Context, id int) (*Report, error) {
+
+	method := Component + ".Delete"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &DeleteParams{
+			ID: id,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// DeleteAll deletes all existing scheduled jobs
+func (t *Client) DeleteAll(ctx context.Context) (*Report, error) {
+
+	method := Component + ".DeleteAll"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+func (t *Client) Close() {
+	if t._messageHandler != nil {
+		t._messageHandler.Close()
+	}
+}
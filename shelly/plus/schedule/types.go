@@ -0,0 +1,16 @@
+package schedule
+
+import (
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+type Job = types.ScheduleJob
+type Jobs = types.ScheduleJobs
+type Call = types.ScheduleCall
+type Report = types.ScheduleReport
+
+type Error = types.Error
+type Request = types.Request
+type Response = types.Response
+type MessageHandlerFactory = types.MessageHandlerFactory
+type MessageHandler = types.MessageHandler
@@ -0,0 +1,13 @@
+package schema
+
+const (
+	// Draft is the JSON Schema version of the generated schemas. Draft 7 is the version supported by
+	// most editors including VS Code.
+	Draft = "http://json-schema.org/draft-07/schema#"
+
+	// DefinitionsRef is the prefix of references to definitions
+	DefinitionsRef = "#/definitions/"
+
+	// ComponentKeyPattern is the pattern of keyed component properties, %s is the component type
+	ComponentKeyPattern = "^%s:[0-9]+$"
+)
@@ -0,0 +1,477 @@
+// Code generated by gen from the doc comments of the types package; DO NOT EDIT.
+
+package schema
+
+// descriptions keyed by <type name> and <type name>.<field name>
+var descriptions = map[string]string{
+	"AuthConfig":                            "Holds the user and clear text password. It is not part of the official Shelly Config API but it should be convenient",
+	"AuthResponse":                          "Auth RFC7616 HTTP Digest Access Authentication",
+	"AuthResponse.Algorithm":                "Algorithm: string, SHA-256. Required",
+	"AuthResponse.Cnonce":                   "Cnonce: number, client nonce, random number generated by the client. Required",
+	"AuthResponse.Nonce":                    "Nonce: number, random or pseudo-random number to prevent replay attacks, taken from the error message. Required",
+	"AuthResponse.Realm":                    "Realm: string, device_id of the Shelly device. Required",
+	"AuthResponse.Response":                 "Response: string, encoding of the string <ha1> + \":\" + <nonce> + \":\" + <nc> + \":\" + <cnonce> + \":\" + \"auth\" + \":\" + <ha2> in SHA256. Required ha1: string, <user>:<realm>:<password> encoded in SHA256 ha2: string, \"dummy_method:dummy_uri\" encoded in SHA256",
+	"AuthResponse.Username":                 "Username: string, must be set to admin. Required",
+	"BluetoothConfig":                       "Configuration of the Bluetooth Low Energy component shows whether the bluetooth connection is enabled.",
+	"BluetoothConfig.Enable":                "True if bluetooth is enabled, false otherwise",
+	"BluetoothConfig.Observer":              "Configuration of the BT LE observer",
+	"BluetoothConfig.RPC":                   "Configuration of the rpc service",
+	"BluetoothObserver":                     "Configuration of the BT LE observer",
+	"BluetoothObserver.Enable":              "True if BT LE observer is enabled, false otherwise",
+	"BluetoothRPC":                          "Configuration of the rpc service",
+	"BluetoothRPC.Enable":                   "True if rpc service is enabled, false otherwise",
+	"BluetoothStatus":                       "Status of the BLE component contains information about the bluetooth on/off state and does not own any status properties.",
+	"CloudConfig":                           "Configuration of the Cloud component shows information about the connection to the cloud",
+	"CloudConfig.Enable":                    "True if cloud connection is enabled, false otherwise",
+	"CloudConfig.Server":                    "Name of the server to which the device is connected",
+	"CloudStatus":                           "Status of the Cloud component it can be checked whether the device is connected to the cloud.",
+	"CloudStatus.Connected":                 "True if the device is connected to the Shelly cloud, false otherwise",
+	"ComponentKey":                          "Is the key of a component in the form <type>:<id>, or <type> for components that only have a single instance such as sys or wifi",
+	"ComponentKey.HasID":                    "True if the key has an id",
+	"ConflictError":                         "Is returned when a revision of the device has moved since a plan was made",
+	"ConflictError.Name":                    "Of the revision, for example cfg_rev",
+	"DeviceInfo":                            "Shelly component top level device info",
+	"DeviceInfo.App":                        "Name",
+	"DeviceInfo.AuthDomain":                 "Name of the domain (null if authentication is not enabled)",
+	"DeviceInfo.AuthEnabled":                "True if authentication is enabled, false otherwise",
+	"DeviceInfo.Batch":                      "Used to provision the device, present only when the ident parameter is set to true",
+	"DeviceInfo.Discoverable":               "Present only when false. If true, device is shown in 'Discovered devices'. If false, the device is hidden.",
+	"DeviceInfo.FirmwareID":                 "Id of the firmware of the device",
+	"DeviceInfo.FwSbits":                    "Shelly internal flags, present only when the ident parameter is set to true",
+	"DeviceInfo.Generation":                 "Of the device",
+	"DeviceInfo.ID":                         "Id of the device",
+	"DeviceInfo.Key":                        "Cloud key of the device (see note below), present only when the ident parameter is set to true",
+	"DeviceInfo.MAC":                        "Address of the device",
+	"DeviceInfo.Model":                      "Of the device",
+	"DeviceInfo.Profile":                    "Name of the device profile (only applicable for multi-profile devices)",
+	"DeviceInfo.Version":                    "Of the firmware of the device",
+	"EthernetConfig":                        "Ethernet component top level config",
+	"EthernetConfig.Enable":                 "True if the configuration is enabled, false otherwise",
+	"EthernetConfig.Gateway":                "To use when ipv4mode is static",
+	"EthernetConfig.IP":                     "Ip to use when ipv4mode is static",
+	"EthernetConfig.Ipv4Mode":               "IPv4 mode. Range of values: dhcp, static",
+	"EthernetConfig.Nameserver":             "To use when ipv4mode is static",
+	"EthernetConfig.Netmask":                "To use when ipv4mode is static",
+	"EthernetStatus":                        "Ethernet component top level status",
+	"EthernetStatus.IP":                     "Of the device in the network",
+	"Extra":                                 "Is kept by the config types so that a get-config, edit, set-config cycle is lossless. Fields holds the fields returned by the device that are not modeled, for example fields added by newer firmware; they are sent back unchanged. Keys holds the keys of the decoded object in their order. The output keeps that order and fields of the struct that were absent from the input are only written if they were set. Values holds the JSON of the modeled fields as they were decoded; a field that was not changed since is written as it was decoded so that null of a field without a null value and number formats such as 60.00 are kept. A config that was not decoded has no Keys and is written with all the fields of the struct followed by Fields ordered by name. YAML is encoded through JSON. The JSON and YAML methods of the types with an Extra field are generated, see gen.",
+	"FieldError":                            "Is a validation error of a single config field",
+	"FieldError.Path":                       "Of the field, for example switch:0.in_mode or wifi.sta.ip",
+	"FieldRule":                             "Describes the values accepted for a field. The rules are used to generate JSON schemas and follow the checks done by Validate.",
+	"FieldRule.Enum":                        "Values accepted for the field",
+	"FieldRule.Format":                      "Of a string field, for example ipv4",
+	"FieldRule.Items":                       "Rule for the elements of an array field",
+	"FieldRule.MaxLength":                   "Maximum length of a string field",
+	"FieldRule.MinItems":                    "And MaxItems number of elements of an array field",
+	"FieldRule.Minimum":                     "And Maximum inclusive range of a numeric field",
+	"FieldRule.Pattern":                     "Regular expression a string field must match",
+	"FirmwareStatus":                        "Is common for components Sys and Shelly",
+	"FirmwareStatus.BuildID":                "Id of the new build",
+	"FirmwareStatus.Version":                "Of the new firmware",
+	"InputConfig":                           "Configuration of the Input component contains information about the type, invert and factory reset settings of the chosen input instance. To Get/Set the configuration of the Input component its id must be specified.",
+	"InputConfig.FactoryReset":              "(only for type switch, button) True if input-triggered factory reset option is enabled, false otherwise (shown if applicable)",
+	"InputConfig.ID":                        "Of the Input component instance",
+	"InputConfig.Invert":                    "(only for type switch, button) True if the logical state of the associated input is inverted, false otherwise. For the change to be applied, the physical switch has to be toggled once after invert is set.",
+	"InputConfig.Name":                      "Of the input instance",
+	"InputConfig.ReportThreshold":           "(only for type analog) Analog input report threshold in percent. Accepted range is device-specific, default [1.0..50.0]% unless specified otherwise",
+	"InputConfig.Type":                      "Of associated input. Range of values switch, button, analog (only if applicable).",
+	"InputStatus":                           "Status of the Input component contains information about the state of the chosen input instance.",
+	"InputStatus.Errors":                    "Shown only if at least one error is present. May contain out_of_range, read",
+	"InputStatus.ID":                        "Id of the Input component instance",
+	"InputStatus.Percent":                   "(only for type analog) Analog value in percent (null if valid value could not be obtained)",
+	"InputStatus.State":                     "(only for type switch, button) State of the input (null if the input instance is stateless, i.e. for type button)",
+	"KVSItem":                               "Value of a key in the Key-Value Store",
+	"KVSItem.Etag":                          "Of the value; changes whenever the value changes",
+	"KVSItem.Value":                         "Of the key. Any JSON value is accepted.",
+	"KVSItems":                              "Result of KVS.GetMany",
+	"KVSItems.Items":                        "Keyed by key",
+	"KVSItems.Offset":                       "Of the first item; present on firmware that pages the result",
+	"KVSItems.Total":                        "Number of matching items; present on firmware that pages the result",
+	"KVSParams":                             "Parameters for the KVS methods",
+	"KVSParams.Key":                         "Used by Set, Get and Delete",
+	"KVSParams.Match":                       "Pattern used by GetMany and List, for example *",
+	"KVSParams.Offset":                      "Used by GetMany",
+	"KVSParams.Value":                       "Used by Set",
+	"KVSReport":                             "Result of KVS.Set and KVS.Delete",
+	"LightConfig.AutoOff":                   "True if the \"Automatic OFF\" function is enabled, false otherwise",
+	"LightConfig.AutoOffDelay":              "Seconds to pass until the component is switched back off",
+	"LightConfig.AutoOn":                    "True if the \"Automatic ON\" function is enabled, false otherwise",
+	"LightConfig.AutoOnDelay":               "Seconds to pass until the component is switched back on",
+	"LightConfig.DefaultBrightness":         "Brightness level (in percent) after power on",
+	"LightConfig.ID":                        "Id of the Switch component instance",
+	"LightConfig.InitialState":              "Range of values: off, on, restore_last, match_input",
+	"LightConfig.Name":                      "Of the switch instance",
+	"LightConfig.NightModeActiveBetween":    "Containing 2 elements of type string, the first element indicates the start of the period during which the night mode will be active, the second indicates the end of that period. Both start and end are strings in the format HH:MM, where HH and MM are hours and minutes with optinal leading zeros",
+	"LightConfig.NightModeBrightness":       "Brightness level limit when night mode is active",
+	"LightConfig.NightModeEnable":           "Enable or disable night mode",
+	"LightStatus":                           "Status of the Light component contains information about the brightness level and output state of the light instance. To obtain the status of the Light component its id must be specified.",
+	"LightStatus.Brightness":                "Current brightness level (in percent)",
+	"LightStatus.ID":                        "Id of the Switch component instance",
+	"LightStatus.Output":                    "True if the output channel is currently on, false otherwise",
+	"LightStatus.Source":                    "Of the last command, for example: init, WS_in, http, ...",
+	"LightStatus.TimerDuration":             "Duration of the timer in seconds (shown if the timer is triggered)",
+	"LightStatus.TimerStartedAt":            "Unix timestamp, start time of the timer (in UTC) (shown if the timer is triggered)",
+	"MqttConfig":                            "Configuration of the MQTT component contains information about the credentials and prefix used and the protection and notifications settings of the MQTT connection.",
+	"MqttConfig.ClientID":                   "Identifies each MQTT client that connects to an MQTT brokers",
+	"MqttConfig.Enable":                     "True if MQTT connection is enabled, false otherwise",
+	"MqttConfig.EnableControl":              "Enable the MQTT control feature. Defalut value: true",
+	"MqttConfig.EnableRPC":                  "Enable RPC",
+	"MqttConfig.Pass":                       "Password, writeonly. Not returned by the device; omitted when not set so the password on the device is kept.",
+	"MqttConfig.RPCNtf":                     "Enables RPC notifications (NotifyStatus and NotifyEvent) to be published on <device_id|topic_prefix>/events/rpc (<topic_prefix> when a custom prefix is set, <device_id> otherwise). Default value: true.",
+	"MqttConfig.Server":                     "Host name of the MQTT server. Can be followed by port number - host:port",
+	"MqttConfig.SslCa":                      "Type of the TCP sockets: null : Plain TCP connection user_ca.pem : TLS connection verified by the user-provided CA ca.pem : TLS connection verified by the built-in CA bundle",
+	"MqttConfig.StatusNtf":                  "Enables publishing the complete component status on <device_id|topic_prefix>/status/<component>:<id> (<topic_prefix> when a custom prefix is set, <device_id> otherwise). The complete status will be published if a signifficant change occurred. Default value: false",
+	"MqttConfig.TopicPrefix":                "Prefix of the topics on which device publish/subscribe. Limited to 300 characters. Could not start with $ and #, +, %, ? are not allowed. Values null : Device id is used as topic prefix",
+	"MqttConfig.UseClientCert":              "Enable or diable usage of client certifactes to use MQTT with encription, default: false",
+	"MqttConfig.User":                       "Username",
+	"MqttStatus":                            "MQTT component top level status",
+	"Notification":                          "Is sent by the device without a request. The device only sends notifications to a channel after a request with a src has been received on it.",
+	"NotificationEvent":                     "Single event of a NotifyEvent notification",
+	"NotificationEvent.Component":           "Key of the component that emitted the event, for example sys or switch:0",
+	"NotificationEvent.Event":               "Name, for example ota_progress",
+	"NotificationEvent.ID":                  "Of the component instance, if applicable",
+	"NotificationEvent.Msg":                 "Optional message of the event",
+	"NotificationEvent.ProgressPercent":     "Progress of an ota_progress event",
+	"NotificationEvent.Ts":                  "Time of the event",
+	"NotificationEvents":                    "Params of a NotifyEvent notification",
+	"NotificationHandler":                   "Is called for every notification received from the device",
+	"RawObject":                             "Is a JSON object that keeps the order of its keys when written as YAML",
+	"Request":                               "Generic request",
+	"Response":                              "Generic response",
+	"ScheduleCall":                          "RPC method and arguments invoked by a job",
+	"ScheduleCall.Method":                   "Name of the RPC method",
+	"ScheduleCall.Params":                   "Of the method. Optional",
+	"ScheduleJob":                           "A schedule job. Jobs call one or more RPC methods at the times given by the timespec.",
+	"ScheduleJob.Calls":                     "RPC methods and arguments to be invoked when the job gets executed",
+	"ScheduleJob.Enable":                    "True to enable the execution of this job, false otherwise",
+	"ScheduleJob.ID":                        "Of the job. Assigned by the device; ignored by Schedule.Create",
+	"ScheduleJob.Timespec":                  "As defined by cron. Note that leading 0s are not supported (e.g.: for 8 AM you should set 8 instead of 08).",
+	"ScheduleJobs":                          "Result of Schedule.List",
+	"ScheduleJobs.Jobs":                     "List of jobs",
+	"ScheduleJobs.Rev":                      "Current revision number of the schedule instances",
+	"ScheduleReport":                        "Result of Schedule.Create, Schedule.Delete and Schedule.DeleteAll",
+	"ScheduleReport.ID":                     "Of the created job",
+	"ScheduleReport.Rev":                    "Revision number of the schedule instances after the change",
+	"ScriptCode":                            "Result of Script.GetCode",
+	"ScriptCode.Data":                       "The requested code",
+	"ScriptCode.Left":                       "Number of bytes remaining after the returned data",
+	"ScriptConfig":                          "Configuration of a Script component",
+	"ScriptConfig.Enable":                   "True if the script runs by default on boot, false otherwise",
+	"ScriptConfig.ID":                       "Of the script",
+	"ScriptConfig.Name":                     "Of the script",
+	"ScriptInfo":                            "Item of the result of Script.List",
+	"ScriptInfo.Enable":                     "True if the script runs by default on boot, false otherwise",
+	"ScriptInfo.ID":                         "Of the script",
+	"ScriptInfo.Name":                       "Of the script",
+	"ScriptInfo.Running":                    "True if the script is currently running, false otherwise",
+	"ScriptList":                            "Result of Script.List",
+	"ScriptParams":                          "Parameters for the Script methods",
+	"ScriptParams.Append":                   "Used by PutCode; true to append the code to the existing code",
+	"ScriptParams.Code":                     "Used by PutCode",
+	"ScriptParams.Config":                   "Used by SetConfig",
+	"ScriptParams.ID":                       "Of the script. Required by all methods but Create",
+	"ScriptParams.Len":                      "Used by GetCode; number of bytes to return",
+	"ScriptParams.Name":                     "Of the script. Used by Create",
+	"ScriptParams.Offset":                   "Used by GetCode; byte offset of the first byte to return",
+	"ScriptReport":                          "Result of Script.Create, Script.PutCode, Script.Start and Script.Stop",
+	"ScriptReport.ID":                       "Of the created script",
+	"ScriptReport.Len":                      "Total length of the code after PutCode",
+	"ScriptReport.RestartRequired":          "True if a restart is required after SetConfig",
+	"ScriptReport.WasRunning":               "True if the script was running before Start or Stop",
+	"SecretMasker":                          "Is implemented by objects that hold secrets in fields that are not named like SecretFields, for example the changes of a plan",
+	"SecretResolver":                        "Returns the value of a secret reference such as env:WIFI_PASS. Values that are not references are returned unchanged.",
+	"ShellyApplyComponent":                  "Is the result of writing the changes of a single component",
+	"ShellyApplyComponent.Error":            "Of a failed component",
+	"ShellyApplyComponent.Fields":           "Paths of the fields that were sent",
+	"ShellyApplyComponent.Key":              "Of the component, for example sys or switch:0",
+	"ShellyApplyComponent.Unconfirmed":      "True if the connection was lost while writing a network component. The change is confirmed by reaching the device again.",
+	"ShellyApplyReport":                     "Is the report returned by Shelly.Apply. Components are either unchanged, changed or failed.",
+	"ShellyApplyReport.Changed":             "Components in the order they were written",
+	"ShellyApplyReport.Failed":              "Components",
+	"ShellyApplyReport.Network":             "Is set if the plan changed the network config of the device",
+	"ShellyApplyReport.RestartRequired":     "True if any component reported that a restart is required",
+	"ShellyApplyReport.RevertFailed":        "Components that were changed and could not be restored",
+	"ShellyApplyReport.Reverted":            "Components that were changed and then restored, in the order they were restored",
+	"ShellyApplyReport.RolledBack":          "True if a transactional apply failed and the changed components were restored",
+	"ShellyApplyReport.Transactional":       "True if the apply was transactional",
+	"ShellyApplyReport.Unchanged":           "Keys of the components that already had the desired config",
+	"ShellyApplyReport.Unsupported":         "Keys of the components that the device does not have; these are not written",
+	"ShellyCertReport":                      "Is the report of a certificate or key upload. Large uploads are split into chunks.",
+	"ShellyCertReport.Bytes":                "Number of bytes sent",
+	"ShellyCertReport.Chunks":               "Number of requests used to send the data",
+	"ShellyCertReport.Deleted":              "True if the existing data was deleted",
+	"ShellyCertReport.NotAfter":             "Earliest expiry of the certificates that were sent",
+	"ShellyCertReport.RestartRequired":      "True if the device must be restarted for the change to apply",
+	"ShellyCertReport.Subjects":             "Of the certificates that were sent",
+	"ShellyCertReport.Warnings":             "About the data, for example certificates that expire soon",
+	"ShellyComponent":                       "Single component returned by Shelly.GetComponents. Status and Config are only present when requested with the include parameter.",
+	"ShellyComponent.Config":                "Of the component",
+	"ShellyComponent.Key":                   "Of the component in the form <type>:<id> or <type>",
+	"ShellyComponent.Status":                "Of the component",
+	"ShellyComponents":                      "Result of Shelly.GetComponents. The result is paginated; Offset and Total can be used to request the remaining components.",
+	"ShellyComponents.CfgRev":               "Configuration revision of the device",
+	"ShellyComponents.Components":           "List of components",
+	"ShellyComponents.Offset":               "Index of the first component in the result",
+	"ShellyComponents.Total":                "Number of components matching the request",
+	"ShellyComponentsParams":                "Parameters for Shelly.GetComponents",
+	"ShellyComponentsParams.DynamicOnly":    "If true only dynamic components will be included. Optional",
+	"ShellyComponentsParams.Include":        "Properties of the component to include in the result. Range of values: status, config. Optional",
+	"ShellyComponentsParams.Keys":           "Components to include in the result in the form <type>:<id>. Optional",
+	"ShellyComponentsParams.Offset":         "Index of the component from which to start generating the result. Optional",
+	"ShellyConfig":                          "Shelly component config. The config is composed of each components config. Shelly devices can have zero or more 'Light', 'Input' and 'Switch' types. These are keyed <type>:<id> in JSON and YAML and kept in maps by id. Components of other types are kept in Unknown as raw JSON.",
+	"ShellyConfig.Input":                    "Components keyed by id",
+	"ShellyConfig.Keys":                     "Of the decoded object in their order; see Extra",
+	"ShellyConfig.Light":                    "Components keyed by id",
+	"ShellyConfig.Switch":                   "Components keyed by id",
+	"ShellyConfig.Unknown":                  "Components keyed by <type>:<id>. The config is kept as raw JSON so that nothing is lost.",
+	"ShellyNetworkReport":                   "Describes how the device was reached after its network config was changed",
+	"ShellyNetworkReport.Address":           "The device was reached at after the change",
+	"ShellyNetworkReport.Method":            "Used to find the address; one of previous, static, mdns or arp",
+	"ShellyNetworkReport.Predicted":         "Static addresses taken from the plan",
+	"ShellyNetworkReport.Previous":          "Address used to reach the device",
+	"ShellyNetworkReport.Verified":          "True if the device was reached at Address and reported the same device id",
+	"ShellyParams":                          "Shelly config parameters",
+	"ShellyParams.Append":                   "Is used by the following methods: PutUserCA : true if more data will be appended afterwards, default false. PutTLSClientCert : true if more data will be appended afterwards, default false PutTLSClientKey : true if more data will be appended afterwards, default false",
+	"ShellyParams.Data":                     "Is used by the following methods: PutUserCA : Contents of the PEM file (null if you want to delete the existing data). Required PutTLSClientCert : Contents of the client.crt file (null if you want to delete the existing data). Required PutTLSClientKey : Contents of the client.key file (null if you want to delete the existing data). Required",
+	"ShellyParams.Ha1":                      "Is used by the following methods: SetAuth : \"user:realm:password\" encoded in SHA256 (null to disable authentication). Required",
+	"ShellyParams.Realm":                    "Is used by the following methods: SetAuth : Must be the id of the device. Only one realm is supported. Required",
+	"ShellyParams.Stage":                    "Is used by the following methods: Update : The type of the new version - either stable or beta. By default updates to stable version. Optional",
+	"ShellyParams.Url":                      "Is used by the following methods: Update : Url address of the update. Optional",
+	"ShellyParams.User":                     "Is used by the following methods: SetAuth: Must be set to admin. Only one user is supported. Required",
+	"ShellyPlan":                            "Is the difference between the config of the device and a desired config. Only the fields that SetConfig would change are listed.",
+	"ShellyPlan.Auth":                       "True if the desired config sets auth. The current auth can not be read so it is always set.",
+	"ShellyPlan.Changes":                    "Total number of changed fields",
+	"ShellyPlan.Components":                 "That have at least one change, ordered by key",
+	"ShellyPlan.RestartRequired":            "True if any change is known to require a restart",
+	"ShellyPlan.Revisions":                  "Of the device when the plan was made. Applying the plan fails if they have moved.",
+	"ShellyPlan.Unchanged":                  "Keys of the components in the desired config that have no change",
+	"ShellyPlan.Unsupported":                "Keys of the components in the desired config that the device does not have",
+	"ShellyPlanChange":                      "Is a change of a single field",
+	"ShellyPlanChange.Path":                 "Of the field within the component, for example device.name",
+	"ShellyPlanChange.RestartRequired":      "True if the change is known to require a restart",
+	"ShellyPlanComponent":                   "Is the list of changes of a single component",
+	"ShellyPlanComponent.Key":               "Of the component, for example sys or switch:0",
+	"ShellyPlanComponent.RestartRequired":   "True if any change of the component is known to require a restart",
+	"ShellyProfile":                         "Components that are available when the profile is active",
+	"ShellyProfile.Components":              "List of component types and count",
+	"ShellyProfileComponent":                "Component type and the number of instances of the type",
+	"ShellyProfileComponent.Count":          "Number of instances of the component type",
+	"ShellyProfileComponent.Type":           "Of component",
+	"ShellyProfileReport":                   "Is the report returned by Shelly.SetProfile. Setting a profile causes the device to reboot.",
+	"ShellyProfileReport.ProfileWas":        "Name of the profile that was active before the change",
+	"ShellyProfileReport.Rebooted":          "True if the device was observed coming back after the reboot",
+	"ShellyProfiles":                        "Lists the device profiles supported by multi-profile devices and the components each profile provides.",
+	"ShellyProfiles.Profiles":               "Keyed by profile name",
+	"ShellyRPCMethods":                      "Lists of all available RPC methods. It takes into account both ACL and authentication restrictions and only lists the methods allowed for the particular user/channel that's making the request.",
+	"ShellyRPCMethods.Methods":              "Names of the methods allowed",
+	"ShellyReport":                          "Is the report returned by Shelly.SetConfig",
+	"ShellyReport.Input":                    "Components keyed by id",
+	"ShellyReport.Light":                    "Components keyed by id",
+	"ShellyReport.Skipped":                  "Keys of the unknown components that were not sent because their SetConfig method is not known",
+	"ShellyReport.Switch":                   "Components keyed by id",
+	"ShellyReport.Unknown":                  "Components keyed by <type>:<id>",
+	"ShellyRevisions":                       "Are revision numbers of the device that are incremented on every change. A nil revision is not checked.",
+	"ShellyRevisions.CfgRev":                "Revision of the config of the components",
+	"ShellyRevisions.ScheduleRev":           "Revision of the schedules",
+	"ShellyRevisions.WebhookRev":            "Revision of the webhooks",
+	"ShellyStatus":                          "Status of all the components of the device.",
+	"ShellyStatus.Input":                    "Components keyed by id",
+	"ShellyStatus.Keys":                     "Of the decoded object in their order; see Extra",
+	"ShellyStatus.Light":                    "Components keyed by id",
+	"ShellyStatus.Switch":                   "Components keyed by id",
+	"ShellyStatus.Unknown":                  "Components keyed by <type>:<id>. The status is kept as raw JSON so that nothing is lost.",
+	"ShellyTimezones":                       "List of all timezones known to the device",
+	"ShellyUpgradeReport":                   "Is the report of an orchestrated firmware upgrade",
+	"ShellyUpgradeReport.DownloadSeconds":   "Time from the start until the device reported ota_success; zero if not observed",
+	"ShellyUpgradeReport.FromFirmwareID":    "Firmware ID before the upgrade",
+	"ShellyUpgradeReport.FromVersion":       "Version before the upgrade",
+	"ShellyUpgradeReport.Message":           "Describes the failure, if any",
+	"ShellyUpgradeReport.RebootSeconds":     "Time from the start until the device was back after the reboot",
+	"ShellyUpgradeReport.Stage":             "Of the update; stable or beta",
+	"ShellyUpgradeReport.StartedAt":         "Time the upgrade was started",
+	"ShellyUpgradeReport.Success":           "True if the device came back with the new firmware",
+	"ShellyUpgradeReport.TargetVersion":     "Version advertised by CheckForUpdate, if known",
+	"ShellyUpgradeReport.ToFirmwareID":      "Firmware ID after the upgrade",
+	"ShellyUpgradeReport.ToVersion":         "Version after the upgrade",
+	"ShellyUpgradeReport.TotalSeconds":      "Total duration of the upgrade",
+	"ShellyUpgradeReport.UpToDate":          "True if no update was available and nothing was done",
+	"SwitchAenergy":                         "Information about the active energy counter (shown if applicable)",
+	"SwitchAenergy.ByMinute":                "Energy consumption by minute (in Milliwatt-hours) for the last three minutes (the lower the index of the element in the array, the closer to the current moment the minute)",
+	"SwitchAenergy.MinuteTs":                "Unix timestamp of the first second of the last minute (in UTC)",
+	"SwitchAenergy.Total":                   "Energy consumed in Watt-hours",
+	"SwitchConfig":                          "Configuration of the Switch component contains information about the input mode, the timers and the protection settings of the chosen switch instance. To Get/Set the configuration of the Switch component its id must be specified.",
+	"SwitchConfig.AutoOff":                  "True if the \"Automatic OFF\" function is enabled, false otherwise",
+	"SwitchConfig.AutoOffDelay":             "Seconds to pass until the component is switched back off",
+	"SwitchConfig.AutoOn":                   "True if the \"Automatic ON\" function is enabled, false otherwise",
+	"SwitchConfig.AutoOnDelay":              "Seconds to pass until the component is switched back on",
+	"SwitchConfig.AutorecoverVoltageErrors": "True if switch output state should be restored after over/undervoltage error is cleared, false otherwise (shown if applicable)",
+	"SwitchConfig.CurrentLimit":             "Number, limit (in Amperes) over which overcurrent condition occurs (shown if applicable)",
+	"SwitchConfig.ID":                       "Id of the Switch component instance",
+	"SwitchConfig.InMode":                   "Range of values: momentary, follow, flip, detached",
+	"SwitchConfig.InitialState":             "Range of values: off, on, restore_last, match_input",
+	"SwitchConfig.InputID":                  "Id of the Input component which controls the Switch. Applicable only to Pro1 and Pro1PM devices. Valid values: 0, 1",
+	"SwitchConfig.Name":                     "Of the switch instance",
+	"SwitchConfig.PowerLimit":               "Limit (in Watts) over which overpower condition occurs (shown if applicable)",
+	"SwitchConfig.UndervoltageLimit":        "Limit (in Volts) under which undervoltage condition occurs (shown if applicable)",
+	"SwitchConfig.VoltageLimit":             "Limit (in Volts) over which overvoltage condition occurs (shown if applicable)",
+	"SwitchStatus":                          "Status of the Switch component contains information about the temperature, voltage, energy level and other physical characteristics of the switch instance. To obtain the status of the Switch component its id must be specified. For switches with power metering capabilities the status payload contains an additional set of properties with information about instantaneous power, supply voltage parameters and energy counters.",
+	"SwitchStatus.Aenergy":                  "Information about the active energy counter (shown if applicable)",
+	"SwitchStatus.Apower":                   "Last measured instantaneous active power (in Watts) delivered to the attached load (shown if applicable)",
+	"SwitchStatus.Current":                  "Last measured current in Amperes (shown if applicable)",
+	"SwitchStatus.Errors":                   "Error conditions occurred. May contain overtemp, overpower, overvoltage, undervoltage, (shown if at least one error is present)",
+	"SwitchStatus.ID":                       "Id of the Switch component instance",
+	"SwitchStatus.Output":                   "True if the output channel is currently on, false otherwise",
+	"SwitchStatus.PowerFactor":              "Last measured power factor (shown if applicable)",
+	"SwitchStatus.Source":                   "Of the last command, for example: init, WS_in, http, ...",
+	"SwitchStatus.Temperature":              "Information about the temperature",
+	"SwitchStatus.TimerDuration":            "Duration of the timer in seconds (shown if the timer is triggered)",
+	"SwitchStatus.TimerStartedAt":           "Unix timestamp, start time of the timer (in UTC) (shown if the timer is triggered)",
+	"SwitchStatus.Voltage":                  "Last measured voltage in Volts (shown if applicable)",
+	"SwitchTemperature":                     "System component object",
+	"SwitchTemperature.TC":                  "Temperature in Celsius (null if temperature is out of the measurement range)",
+	"SwitchTemperature.TF":                  "Temperature in Fahrenheit (null if temperature is out of the measurement",
+	"SystemAvailableUpdates":                "Information about available updates, similar to the one returned by Shelly.CheckForUpdate (empty object: {}, if no updates available). This information is automatically updated every 24 hours. Note that build_id and url for an update are not displayed here",
+	"SystemAvailableUpdates.Beta":           "Shown only if beta update is available",
+	"SystemAvailableUpdates.Stable":         "Version of the new firmware. Shown only if stable update is available",
+	"SystemConfig":                          "System component config",
+	"SystemConfig.CfgRev":                   "Configuration revision. This number will be incremented for every configuration change of a device component. If the new config value is the same as the old one there will be no change of this property. Can not be modified explicitly by a call to Sys.SetConfig",
+	"SystemConfig.Debug":                    "Configuration of the device's debug logs.",
+	"SystemConfig.Device":                   "Information about the device",
+	"SystemConfig.Location":                 "Information about the current location of the device",
+	"SystemConfig.RPCUDP":                   "Configuration for the RPC over UDP",
+	"SystemConfig.Sntp":                     "Configuration for the sntp server",
+	"SystemConfig.UIData":                   "User interface data",
+	"SystemDebug":                           "DebugConfig Configuration of the device's debug logs",
+	"SystemDebug.Mqtt":                      "Configuration of logs streamed over MQTT",
+	"SystemDebug.UDP":                       "Configuration of logs streamed over UDP",
+	"SystemDebug.Websocket":                 "Configuration of logs streamed over websocket. Attention: Access to log streams over websocket is not restricted, even when authentication is enabled!",
+	"SystemDevice":                          "Information about the device",
+	"SystemDevice.AddonType":                "Enable/disable addon board (if supported). Range of values: sensor; null to disable.",
+	"SystemDevice.Discoverable":             "If true, device is shown in 'Discovered devices'. If false, the device is hidden.",
+	"SystemDevice.EcoMode":                  "Experimental Decreases power consumption when set to true, at the cost of reduced execution speed and increased network latency",
+	"SystemDevice.FwID":                     "Read-only build identifier of the current firmware image",
+	"SystemDevice.MAC":                      "Read-only base MAC address of the device",
+	"SystemDevice.Name":                     "Of the device",
+	"SystemDevice.Profile":                  "Name of the device profile (only applicable for multi-profile devices)",
+	"SystemLocation":                        "SystemLocationConfig Information about the current location of the device",
+	"SystemLocation.Lat":                    "Latitude in degrees (null if unavailable)",
+	"SystemLocation.Lon":                    "Longitude in degrees (null if unavailable)",
+	"SystemLocation.Tz":                     "Timezone (null if unavailable)",
+	"SystemMqtt":                            "Configuration of logs streamed over MQTT",
+	"SystemRPCUDP":                          "Configuration for the RPC over UDP",
+	"SystemRPCUDP.DstAddr":                  "Destination IP address",
+	"SystemRPCUDP.ListenPort":               "Port number for inbound UDP RPC channel, null disables. Restart is required for changes to apply",
+	"SystemSntp":                            "SntpConfig configuration for the sntp server",
+	"SystemSntp.Server":                     "Name of the sntp server",
+	"SystemStatus":                          "Status contains information about network state, system time and other common attributes of the Shelly device. Presence of some keys is optional, depending on the underlying hardware components.",
+	"SystemStatus.AvailableUpdates":         "Information about available updates, similar to the one returned by Shelly.CheckForUpdate (empty object: {}, if no updates available). This information is automatically updated every 24 hours. Note that build_id and url for an update are not displayed here",
+	"SystemStatus.CfgRev":                   "Configuration revision number",
+	"SystemStatus.FsFree":                   "Size of the free file system in Bytes",
+	"SystemStatus.FsSize":                   "Total size of the file system in Bytes",
+	"SystemStatus.KvsRev":                   "KVS (Key-Value Store) revision number",
+	"SystemStatus.MAC":                      "Address of the device",
+	"SystemStatus.RAMFree":                  "Size of the free RAM in the system in Bytes",
+	"SystemStatus.RAMSize":                  "Total size of the RAM in the system in Bytes",
+	"SystemStatus.RestartRequired":          "True if restart is required, false otherwise",
+	"SystemStatus.ScheduleRev":              "Schedules revision number, present if schedules are enabled",
+	"SystemStatus.Time":                     "Current time in the format HH:MM (24-hour time format in the current timezone with leading zero). null when time is not synced from NTP server.",
+	"SystemStatus.Unixtime":                 "Unix timestamp (in UTC), null when time is not synced from NTP server.",
+	"SystemStatus.Uptime":                   "Time in seconds since last reboot",
+	"SystemStatus.WakeupPeriod":             "Period (in seconds) at which device wakes up and sends \"keep-alive\" packet to cloud, readonly. Count starts from last full wakeup",
+	"SystemStatus.WakeupReason":             "Information about boot type and cause (only for battery-operated devices)",
+	"SystemStatus.WebhookRev":               "Webhooks revision number, present if webhooks are enabled",
+	"SystemTimeParams":                      "Parameters for Sys.SetTime",
+	"SystemTimeParams.Unixtime":             "Unix timestamp (in UTC) to set the device time to",
+	"SystemUDP":                             "Configuration of logs streamed over UDP. Used by component System.",
+	"SystemUIData":                          "User interface data. Used by component System.",
+	"SystemWakeupReason":                    "Information about boot type and cause (only for battery-operated devices)",
+	"SystemWakeupReason.Boot":               "Type, one of: poweron, software_restart, deepsleep_wake, internal (e.g. brownout detection, watchdog timeout, etc.), unknown",
+	"SystemWakeupReason.Cause":              "One of: button, usb, periodic, status_update, alarm, alarm_test, undefined (in case of deep sleep, reset was not caused by exit from deep sleep)",
+	"SystemWebsocket":                       "Configuration of logs streamed over websocket. Attention: Access to log streams over websocket is not restricted, even when authentication is enabled!",
+	"SystemWebsocket.Enable":                "True if enabled, false otherwise",
+	"TemplateData":                          "Is the data available to config templates, for example {{ .Device.Name }}, {{ .Vars.room }} and {{ .DeviceInfo.MAC }}",
+	"TemplateData.Device":                   "Identity of the device the config is rendered for",
+	"TemplateData.DeviceInfo":               "As returned by the device. Nil when rendering without a device.",
+	"TemplateData.Vars":                     "User defined variables",
+	"TemplateDevice":                        "Identity of the device a config is rendered for. Values from a vars file take precedence over the values read from the device.",
+	"TemplateDevice.Hostname":               "Used to reach the device",
+	"TemplateDevice.ID":                     "Of the device, for example shellyplus1-a8032ab12345",
+	"TemplateDevice.Name":                   "Of the device",
+	"UpdatesReport":                         "Checks for new firmware version for the device and returns information about it. If no update is available returns empty JSON object as result.",
+	"WebhookAttrs":                          "Since version 0.11.0 Contains all events that can be used to trigger a webhook, extended with a declaration of supported event attributes. Events are listed as JSON objects with keys in format component type,event type. Supported event attributes, if such exist, are represented in attrs array",
+	"WebhookAttrs.Name":                     "Attribute name",
+	"WebhookDeleteParams":                   "Parameters for Webhook.Delete",
+	"WebhookDeleteParams.ID":                "Of the webhook to delete. Required",
+	"WebhookHook":                           "WebHook",
+	"WebhookHook.ActiveBetween":             "Period during which the webhook will be active. Not present if the webhook is always active.",
+	"WebhookHook.Cid":                       "Id of the component",
+	"WebhookHook.Condition":                 "Hook trigger condition associated with event.",
+	"WebhookHook.Enable":                    "True to be enabled, false otherwise",
+	"WebhookHook.Event":                     "Which will trigger the execution of the webhook. Valid events are listed by Webhook.ListSupported. Example values: switch.on, input.toggle_off.",
+	"WebhookHook.ID":                        "Of the webhook",
+	"WebhookHook.Name":                      "Sser-defined name for the webhook instance",
+	"WebhookHook.RepeatPeriod":              "Minimum interval for invocations of the hook.",
+	"WebhookHook.SslCa":                     "Type of the TCP sockets: null : Plain TCP connection user_ca.pem : TLS connection verified by the user-provided CA ca.pem : TLS connection verified by the built-in CA bundle",
+	"WebhookHook.URLs":                      "Containing url addresses that will be called when the webhook event occurs",
+	"WebhookParams.ActiveBetween":           "The first element indicates the start of the period during which the webhook will be active, the second indicates the end of that period. Both start and end are strings in the format HH:MM, where HH and MM are hours and minutes with optional leading zeros. To clear active_between its value should be set to empty array or null. When active_between is empty, this attribute is not visible in Webhook.List and the webhook is active all the time. Optional",
+	"WebhookParams.Cid":                     "Id of the component Required",
+	"WebhookParams.Condition":               "Hook trigger condition associated with event. Optional",
+	"WebhookParams.Enable":                  "True to be enabled, false otherwise. It is false by default. Optional",
+	"WebhookParams.Event":                   "Which will trigger the execution of the webhook. Valid events are listed by Webhook.ListSupported. Example values: switch.on, input.toggle_off. Required",
+	"WebhookParams.ID":                      "Of the webhook to update. Required for Update, ignored by Create",
+	"WebhookParams.Name":                    "User-defined name for the webhook instance. Optional",
+	"WebhookParams.RepeatPeriod":            "Minimum interval for invocations of the hook. If set to negative the hook will be invoked only once when the condition changes from false to true. If set to 0 the hook will be invoked every time the triggering event occurs. Default is 0.Optional",
+	"WebhookParams.SslCa":                   "Type of the TCP sockets: null : Plain TCP connection user_ca.pem : TLS connection verified by the user-provided CA ca.pem : TLS connection verified by the built-in CA bundle. Optional",
+	"WebhookParams.URLs":                    "Containing url addresses that will be called when the webhook event occurs. Each url address is limited to 300 characters and the total number of url addresses associate with one webhook is 5. At least one url address is Required",
+	"WebhookSyncReport":                     "Is the report returned by a declarative sync of webhooks. Hooks are identified by their name.",
+	"WebhookSyncReport.Created":             "Names of the hooks that were created",
+	"WebhookSyncReport.Deleted":             "Names (or IDs of unnamed hooks) of the hooks that were deleted",
+	"WebhookSyncReport.DryRun":              "True if no changes were sent to the device",
+	"WebhookSyncReport.Rev":                 "Revision of the webhooks after the sync",
+	"WebhookSyncReport.Unchanged":           "Names of the hooks that already matched",
+	"WebhookSyncReport.Updated":             "Names of the hooks that were updated",
+	"Webhooks.Hooks":                        "List of hooks",
+	"Webhooks.Rev":                          "Current revision number of the webhook instances",
+	"Webhooks.Types":                        "Of events",
+	"WebsocketConfig":                       "Configuration",
+	"WebsocketConfig.Enable":                "True if websocket outbound connection is enabled, false otherwise",
+	"WebsocketConfig.Server":                "Name of the server to which the device is connected. When prefixed with wss:// a TLS socket will be used",
+	"WebsocketConfig.SslCa":                 "Type of the TCP sockets",
+	"WebsocketStatus":                       "Status",
+	"WebsocketStatus.Connected":             "True if device is connected to a websocket outbound connection or false otherwise.",
+	"WifiAP":                                "WiFi component object",
+	"WifiAP.Enable":                         "True if the access point is enabled, false otherwise",
+	"WifiAP.IsOpen":                         "True if the access point is open, false otherwise",
+	"WifiAP.Pass":                           "Password for the ssid, writeonly. Must be provided if you provide ssid",
+	"WifiAP.RangeExtender":                  "Range extender configuration object, available only when range extender functionality is present.",
+	"WifiAP.SSID":                           "Readonly SSID of the access point",
+	"WifiAPClient":                          "WiFi component object",
+	"WifiAPClients":                         "Internal use only",
+	"WifiConfig":                            "Configuration of the WiFi component contains information about the access point of the device, the network stations and the roaming settings.",
+	"WifiConfig.Ap":                         "Information about the access point",
+	"WifiConfig.Roam":                       "WiFi roaming configuration",
+	"WifiConfig.Sta":                        "Information about the sta configuration",
+	"WifiConfig.Sta1":                       "Information about the sta configuration",
+	"WifiNet":                               "Scan WiFi component object",
+	"WifiRangeExtender":                     "Range extender configuration object, available only when range extender functionality is present.",
+	"WifiRoam":                              "WiFi roaming configuration",
+	"WifiRoam.Interval":                     "At which to scan for better access points. Enabled if set to positive number, disabled if set to 0. Default value: 60",
+	"WifiRoam.RSSIThreshold":                "- when reached will trigger the access point roaming. Default value: -80",
+	"WifiSTA":                               "WiFi component object",
+	"WifiSTA.Enable":                        "True if the configuration is enabled, false otherwise",
+	"WifiSTA.Gateway":                       "To use when ipv4mode is static",
+	"WifiSTA.IP":                            "Ip to use when ipv4mode is static",
+	"WifiSTA.Ipv4Mode":                      "IPv4 mode. Range of values: dhcp, static",
+	"WifiSTA.IsOpen":                        "True if the network is open, i.e. no password is set, false otherwise, readonly",
+	"WifiSTA.Nameserver":                    "To use when ipv4mode is static",
+	"WifiSTA.Netmask":                       "To use when ipv4mode is static",
+	"WifiSTA.Pass":                          "Password for the ssid, writeonly. Must be provided if you provide ssid",
+	"WifiSTA.SSID":                          "Of the network",
+	"WifiScanResults":                       "Internal use only",
+	"WifiStatus":                            "Status of the WiFi component contains information about the state of the WiFi connection of the device.",
+	"WifiStatus.ApClientCount":              "Number of clients connected to the access point. Present only when AP is enabled and range extender functionality is present and enabled.",
+	"WifiStatus.RSSI":                       "Rssi Strength of the signal in dBms",
+	"WifiStatus.SSID":                       "Ssid of the network (null if disconnected)",
+	"WifiStatus.StaIP":                      "Ip of the device in the network (null if disconnected)",
+	"WifiStatus.Status":                     "Of the connection. Range of values: disconnected, connecting, connected, got ip",
+}
@@ -0,0 +1,135 @@
+// Command gen writes the doc comments of the types package as a Go map so that the JSON schemas
+// carry the same descriptions as the library. Run with go generate from the schema package.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+)
+
+func main() {
+
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: gen <types dir> <output file>")
+		os.Exit(1)
+	}
+
+	err := run(os.Args[1], os.Args[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string) error {
+
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	descriptions := make(map[string]string)
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range gen.Specs {
+
+					typeSpec := spec.(*ast.TypeSpec)
+					if !typeSpec.Name.IsExported() {
+						continue
+					}
+
+					doc := typeSpec.Doc
+					if doc == nil {
+						doc = gen.Doc
+					}
+
+					add(descriptions, typeSpec.Name.Name, typeSpec.Name.Name, doc)
+
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+
+					for _, field := range structType.Fields.List {
+						for _, name := range field.Names {
+							add(descriptions, typeSpec.Name.Name+"."+name.Name, name.Name, field.Doc)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(descriptions))
+	for key := range descriptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+
+	fmt.Fprintln(&b, "// Code generated by gen from the doc comments of the types package; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package schema")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "// descriptions keyed by <type name> and <type name>.<field name>")
+	fmt.Fprintln(&b, "var descriptions = map[string]string{")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\t%q: %q,\n", key, descriptions[key])
+	}
+	fmt.Fprintln(&b, "}")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, src, 0644)
+}
+
+// add stores the comment without links and without the leading name that Go doc comments start with
+func add(descriptions map[string]string, key, name string, doc *ast.CommentGroup) {
+
+	if doc == nil {
+		return
+	}
+
+	var lines []string
+
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	text := strings.Join(lines, " ")
+	text = strings.TrimPrefix(text, name+" ")
+	text = strings.TrimSpace(text)
+
+	if text == "" || text == name || text == "..." {
+		return
+	}
+
+	descriptions[key] = strings.ToUpper(text[:1]) + text[1:]
+}
@@ -0,0 +1,250 @@
+package schema
+
+//go:generate go run ./gen ../types descriptions.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+// Types are the types a schema can be generated for keyed by the name used on the command line
+var Types = map[string]reflect.Type{
+	"config":                reflect.TypeOf(types.ShellyConfig{}),
+	"auth-config":           reflect.TypeOf(types.AuthConfig{}),
+	"ble-config":            reflect.TypeOf(types.BluetoothConfig{}),
+	"cloud-config":          reflect.TypeOf(types.CloudConfig{}),
+	"eth-config":            reflect.TypeOf(types.EthernetConfig{}),
+	"input-config":          reflect.TypeOf(types.InputConfig{}),
+	"light-config":          reflect.TypeOf(types.LightConfig{}),
+	"mqtt-config":           reflect.TypeOf(types.MqttConfig{}),
+	"switch-config":         reflect.TypeOf(types.SwitchConfig{}),
+	"sys-config":            reflect.TypeOf(types.SystemConfig{}),
+	"wifi-config":           reflect.TypeOf(types.WifiConfig{}),
+	"ws-config":             reflect.TypeOf(types.WebsocketConfig{}),
+	"light-params":          reflect.TypeOf(types.LightParams{}),
+	"switch-params":         reflect.TypeOf(types.SwitchParams{}),
+	"shelly-params":         reflect.TypeOf(types.ShellyParams{}),
+	"components-params":     reflect.TypeOf(types.ShellyComponentsParams{}),
+	"time-params":           reflect.TypeOf(types.SystemTimeParams{}),
+	"webhook-params":        reflect.TypeOf(types.WebhookParams{}),
+	"webhook-delete-params": reflect.TypeOf(types.WebhookDeleteParams{}),
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Names returns the sorted names of Types
+func Names() []string {
+
+	var names []string
+	for name := range Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Generate returns the schema of the type with the name. See Types.
+func Generate(name string) (*Schema, error) {
+
+	typ, ok := Types[name]
+	if !ok {
+		return nil, fmt.Errorf("type %s is not known; expect one of %s", name, strings.Join(Names(), ", "))
+	}
+
+	return ForType(typ), nil
+}
+
+// ForType returns the schema of the struct type. Nested structs are placed in the definitions. Descriptions
+// are taken from the doc comments of the types package, enums and ranges from types.GetFieldRule.
+func ForType(typ reflect.Type) *Schema {
+
+	g := &generator{
+		definitions: make(map[string]*Schema),
+	}
+
+	root := g.schemaOf(typ)
+
+	return &Schema{
+		Schema:      Draft,
+		Ref:         root.Ref,
+		Definitions: g.definitions,
+	}
+}
+
+type generator struct {
+	definitions map[string]*Schema
+}
+
+func (t *generator) schemaOf(typ reflect.Type) *Schema {
+
+	switch typ {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawJSONType:
+		return &Schema{}
+	}
+
+	switch typ.Kind() {
+
+	case reflect.Pointer:
+		return nullable(t.schemaOf(typ.Elem()))
+
+	case reflect.Struct:
+		t.define(typ)
+		return &Schema{Ref: DefinitionsRef + typ.Name()}
+
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: t.schemaOf(typ.Elem())}
+
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: t.schemaOf(typ.Elem())}
+
+	case reflect.String:
+		return &Schema{Type: "string"}
+
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+
+	// Interfaces and anything else accept any value
+	return &Schema{}
+}
+
+// define adds the definition of the struct type
+func (t *generator) define(typ reflect.Type) {
+
+	name := typ.Name()
+
+	if _, ok := t.definitions[name]; ok {
+		return
+	}
+
+	definition := &Schema{
+		Title:                name,
+		Description:          descriptions[name],
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	// Added before the fields so that recursive types terminate
+	t.definitions[name] = definition
+
+	for i := 0; i < typ.NumField(); i++ {
+
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		if jsonName == "-" {
+			switch {
+
+			// Keyed component maps such as Switch map[int]*SwitchConfig
+			case field.Type.Kind() == reflect.Map && field.Type.Key().Kind() == reflect.Int:
+				if definition.PatternProperties == nil {
+					definition.PatternProperties = make(map[string]*Schema)
+				}
+				pattern := fmt.Sprintf(ComponentKeyPattern, strings.ToLower(field.Name))
+				definition.PatternProperties[pattern] = t.schemaOf(deref(field.Type.Elem()))
+
+			// Extra fields and unknown components are kept by the types
+			case field.Type.Kind() == reflect.Map:
+				definition.AdditionalProperties = true
+			}
+			continue
+		}
+
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+
+		property := t.schemaOf(field.Type)
+
+		if property.Ref != "" {
+			// Siblings of $ref are ignored so the reference is wrapped to carry the description
+			property = &Schema{AnyOf: []*Schema{property}}
+		}
+
+		property.Description = descriptions[name+"."+field.Name]
+		applyRule(property, types.GetFieldRule(name, jsonName))
+
+		definition.Properties[jsonName] = property
+	}
+}
+
+func deref(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// nullable allows null in addition to the schema
+func nullable(schema *Schema) *Schema {
+
+	switch typ := schema.Type.(type) {
+
+	case string:
+		schema.Type = []string{typ, "null"}
+		return schema
+
+	case nil:
+		if schema.Ref != "" {
+			return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+		}
+	}
+
+	return schema
+}
+
+func isNullable(schema *Schema) bool {
+	typ, ok := schema.Type.([]string)
+	return ok && len(typ) > 1 && typ[len(typ)-1] == "null"
+}
+
+func applyRule(schema *Schema, rule *types.FieldRule) {
+
+	if rule == nil {
+		return
+	}
+
+	for _, value := range rule.Enum {
+		schema.Enum = append(schema.Enum, value)
+	}
+
+	if len(schema.Enum) > 0 && isNullable(schema) {
+		schema.Enum = append(schema.Enum, nil)
+	}
+
+	schema.Minimum = rule.Minimum
+	schema.Maximum = rule.Maximum
+	schema.MaxLength = rule.MaxLength
+	schema.Pattern = rule.Pattern
+	schema.Format = rule.Format
+	schema.MinItems = rule.MinItems
+	schema.MaxItems = rule.MaxItems
+
+	if rule.Items != nil && schema.Items != nil {
+		applyRule(schema.Items, rule.Items)
+	}
+}
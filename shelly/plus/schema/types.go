@@ -0,0 +1,26 @@
+package schema
+
+// Schema is a JSON Schema document or sub schema
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Ref         string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        any                `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Enum        []any              `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Pattern     string             `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Items       *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	// PatternProperties is used for keyed components such as switch:0
+	PatternProperties map[string]*Schema `json:"patternProperties,omitempty" yaml:"patternProperties,omitempty"`
+	// AdditionalProperties is either a bool or a *Schema
+	AdditionalProperties any                `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+}
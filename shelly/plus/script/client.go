@@ -0,0 +1,403 @@
+package script
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ListResponse internal use only
+type ListResponse struct {
+	Response
+	Result *List `json:"result,omitempty"`
+}
+
+// ConfigResponse internal use only
+type ConfigResponse struct {
+	Response
+	Result *Config `json:"result,omitempty"`
+}
+
+// CodeResponse internal use only
+type CodeResponse struct {
+	Response
+	Result *Code `json:"result,omitempty"`
+}
+
+// ReportResponse internal use only
+type ReportResponse struct {
+	Response
+	Result *Report `json:"result,omitempty"`
+}
+
+func New(messageHandlerFactory MessageHandlerFactory) *Client {
+	return &Client{
+		MessageHandlerFactory: messageHandlerFactory,
+	}
+}
+
+type Client struct {
+	MessageHandlerFactory
+	_messageHandler MessageHandler
+}
+
+func (t *Client) getMessageHandler() MessageHandler {
+	if t._messageHandler != nil {
+		return t._messageHandler
+	}
+
+	t._messageHandler = t.NewHandle()
+	return t._messageHandler
+}
+
+// List lists all scripts of the device
+func (t *Client) List(ctx context.Context) (*List, error) {
+
+	method := Component + ".List"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// GetConfig returns the config of the script
+func (t *Client) GetConfig(ctx context.Context, id int) (*Config, error) {
+
+	method := Component + ".GetConfig"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			ID: &id,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ConfigResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// SetConfig sets the config of the script. The ID of the config is required.
+func (t *Client) SetConfig(ctx context.Context, config *Config) (*Report, error) {
+
+	method := Component + ".SetConfig"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			ID:     &config.ID,
+			Config: config,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// Create creates a new empty script. The ID of the script is returned in the report.
+func (t *Client) Create(ctx context.Context, name string) (*Report, error) {
+
+	method := Component + ".Create"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			Name: &name,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// Delete deletes the script
+func (t *Client) Delete(ctx context.Context, id int) (*Report, error) {
+
+	method := Component + ".Delete"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			ID: &id,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return response.Result, nil
+}
+
+// Start starts the script
+func (t *Client) Start(ctx context.Context, id int) (*Report, error) {
+
+	method := Component + ".Start"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			ID: &id,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// Stop stops the script
+func (t *Client) Stop(ctx context.Context, id int) (*Report, error) {
+
+	method := Component + ".Stop"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			ID: &id,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// getCode returns the code of the script starting at offset
+func (t *Client) getCode(ctx context.Context, id, offset int) (*Code, error) {
+
+	method := Component + ".GetCode"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			ID:     &id,
+			Offset: &offset,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CodeResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// putCode sets or appends a chunk of the code of the script
+func (t *Client) putCode(ctx context.Context, id int, code string, appendCode bool) (*Report, error) {
+
+	method := Component + ".PutCode"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &Params{
+			ID:     &id,
+			Code:   &code,
+			Append: appendCode,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// GetCode returns the complete code of the script. The device returns the code in chunks.
+func (t *Client) GetCode(ctx context.Context, id int) (string, error) {
+
+	var code []byte
+
+	for {
+
+		chunk, err := t.getCode(ctx, id, len(code))
+		if err != nil {
+			return "", err
+		}
+
+		code = append(code, chunk.Data...)
+
+		if chunk.Left <= 0 || chunk.Data == "" {
+			return string(code), nil
+		}
+	}
+}
+
+// PutCode replaces the code of the script. The code is sent in chunks of CodeChunkSize bytes.
+func (t *Client) PutCode(ctx context.Context, id int, code string) (*Report, error) {
+
+	report, err := t.putCode(ctx, id, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(code) > 0 {
+
+		size := CodeChunkSize
+		if size > len(code) {
+			size = len(code)
+		}
+
+		// Chunks must be valid UTF-8 to be sent as a JSON string
+		for size < len(code) && !utf8.RuneStart(code[size]) {
+			size--
+		}
+
+		report, err = t.putCode(ctx, id, code[:size], true)
+		if err != nil {
+			return nil, err
+		}
+
+		code = code[size:]
+	}
+
+	return report, nil
+}
+
+func (t *Client) Close() {
+	if t._messageHandler != nil {
+		t._messageHandler.Close()
+	}
+}
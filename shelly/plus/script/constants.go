@@ -0,0 +1,7 @@
+package script
+
+const (
+	Component = "Script"
+	// CodeChunkSize is the number of bytes sent with each Script.PutCode call
+	CodeChunkSize = 1024
+)
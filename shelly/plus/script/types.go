@@ -0,0 +1,18 @@
+package script
+
+import (
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+type Config = types.ScriptConfig
+type Info = types.ScriptInfo
+type List = types.ScriptList
+type Params = types.ScriptParams
+type Code = types.ScriptCode
+type Report = types.ScriptReport
+
+type Error = types.Error
+type Request = types.Request
+type Response = types.Response
+type MessageHandlerFactory = types.MessageHandlerFactory
+type MessageHandler = types.MessageHandler
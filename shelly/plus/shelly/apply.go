@@ -0,0 +1,441 @@
+package shelly
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+// applyOrder is the order in which component types are written. It follows SetConfig: network
+// components come last because connectivity may be lost after the change. Types that are not listed
+// are written after switch.
+var applyOrder = map[string]int{
+	"ble":    1,
+	"cloud":  2,
+	"mqtt":   3,
+	"light":  4,
+	"input":  5,
+	"switch": 6,
+	"sys":    8,
+	"ws":     9,
+	"eth":    10,
+	"wifi":   11,
+}
+
+const applyOrderUnknown = 7
+
+// ApplyOptions changes how a plan is applied
+type ApplyOptions struct {
+	// Force applies the plan even if the revisions of the device moved since the plan was made
+	Force bool
+	// Transactional stops at the first failed component and restores the components that were
+	// already written from a snapshot taken before the first write
+	Transactional bool
+	// Hostname used to reach the device. It is the first address tried after a network change.
+	Hostname string
+	// Dial is used to reach the device after a network change. If nil the device can not be located
+	// and a lost connection while writing a network component is reported as an error.
+	Dial Dialer
+	// ReconnectTimeout is the maximum time to locate the device after a network change. Default is
+	// DefaultReconnectTimeout.
+	ReconnectTimeout time.Duration
+}
+
+// Apply fetches the config of the device and only writes the components and fields that differ from
+// the desired config. Components without changes are not written so cfg_rev is not bumped and no
+// needless restart is triggered. The error lists the failed components; the report is returned in
+// either case.
+func (t *Client) Apply(ctx context.Context, desired *ShellyConfig) (*ShellyApplyReport, error) {
+
+	plan, err := t.Plan(ctx, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ApplyPlan(ctx, plan, desired, &ApplyOptions{})
+}
+
+// ApplyPlan writes the changes of the plan in apply order. Desired is only used for auth which can not
+// be planned and may be nil. Unless options.Force is set a *ConflictError is returned without writing
+// anything if the revisions of the device have moved since the plan was made.
+func (t *Client) ApplyPlan(ctx context.Context, plan *ShellyPlan, desired *ShellyConfig, options *ApplyOptions) (*ShellyApplyReport, error) {
+
+	if options == nil {
+		options = &ApplyOptions{}
+	}
+
+	if !options.Force {
+		err := t.CheckRevisions(ctx, plan.Revisions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if plan.Auth && (desired == nil || desired.Auth == nil) {
+		if options.Transactional {
+			return nil, fmt.Errorf("Auth :: desired config is required")
+		}
+	}
+
+	components := sortPlanComponents(plan.Components)
+
+	var snapshot map[string]any
+
+	if options.Transactional && len(components) > 0 {
+		current, err := t.GetConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot :: %w", err)
+		}
+		snapshot, err = toGeneric(current)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot :: %w", err)
+		}
+	}
+
+	report := &ShellyApplyReport{
+		Unchanged:     plan.Unchanged,
+		Unsupported:   plan.Unsupported,
+		Transactional: options.Transactional,
+	}
+
+	local, network := splitNetworkComponents(components)
+
+	if len(network) > 0 {
+		info, err := t.GetDeviceInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		report.Network = &ShellyNetworkReport{
+			DeviceID:  info.ID,
+			MAC:       info.MAC,
+			Previous:  options.Hostname,
+			Predicted: predictAddresses(network),
+		}
+	}
+
+	var errors *multierror.Error
+
+	stopped := func() bool {
+		return options.Transactional && len(report.Failed) > 0
+	}
+
+	// apply writes the component through the client and returns true if the connection was lost
+	apply := func(client *Client, component *ShellyPlanComponent, tolerateDisconnect bool) bool {
+
+		result, err := client.applyComponent(ctx, component)
+		if err != nil {
+			if tolerateDisconnect && isConnectionError(err) {
+				zap.L().Debug(fmt.Sprintf("connection lost while applying %s: %v", component.Key, err))
+				result.Unconfirmed = true
+				report.Changed = append(report.Changed, result)
+				return true
+			}
+			result.Error = err.Error()
+			report.Failed = append(report.Failed, result)
+			errors = multierror.Append(errors, fmt.Errorf("%s :: %v", component.Key, err))
+			return false
+		}
+
+		report.RestartRequired = report.RestartRequired || result.RestartRequired
+		report.Changed = append(report.Changed, result)
+		return false
+	}
+
+	for _, component := range local {
+		if stopped() {
+			break
+		}
+		apply(t, component, false)
+	}
+
+	// Auth is written after the other components because the previous value can not be read and
+	// restored, and before the network components because the device may not be reachable after them
+
+	if plan.Auth && (desired == nil || desired.Auth == nil) {
+		report.Failed = append(report.Failed, &ShellyApplyComponent{
+			Key:   authKey,
+			Error: "auth is not part of the plan and needs the desired config",
+		})
+		errors = multierror.Append(errors, fmt.Errorf("Auth :: desired config is required"))
+	}
+
+	if plan.Auth && desired != nil && desired.Auth != nil && !stopped() {
+
+		result := &ShellyApplyComponent{
+			Key: authKey,
+		}
+
+		resp, err := t.SetAuth(ctx, &ShellyParams{
+			Ha1:  desired.Auth.Pass,
+			User: &desired.Auth.User,
+		})
+		if err != nil {
+			result.Error = err.Error()
+			report.Failed = append(report.Failed, result)
+			errors = multierror.Append(errors, fmt.Errorf("Auth :: %v", err))
+		} else {
+			result.RestartRequired = resp.RestartRequired
+			report.RestartRequired = report.RestartRequired || resp.RestartRequired
+			report.Changed = append(report.Changed, result)
+		}
+	}
+
+	// Network components are written last. The connection may be lost while writing them. The device is
+	// then located and the remaining components are written through a client for its new address; if it
+	// can not be reached they are reported as not applied. The device is verified before the apply is
+	// reported as successful.
+
+	client := t
+
+	for i, component := range network {
+
+		if stopped() {
+			break
+		}
+
+		if !apply(client, component, true) {
+			continue
+		}
+
+		remaining := network[i+1:]
+		if len(remaining) == 0 {
+			break
+		}
+
+		relocated, closer, err := t.relocate(ctx, report, options)
+		if err != nil {
+			var keys []string
+			for _, notApplied := range remaining {
+				result := &ShellyApplyComponent{
+					Key:   notApplied.Key,
+					Error: fmt.Sprintf("not applied; connection lost after writing %s", component.Key),
+				}
+				for _, change := range notApplied.Changes {
+					result.Fields = append(result.Fields, change.Path)
+				}
+				report.Failed = append(report.Failed, result)
+				keys = append(keys, notApplied.Key)
+			}
+			errors = multierror.Append(errors, fmt.Errorf("%s :: not applied: %w", strings.Join(keys, ","), err))
+			break
+		}
+		defer closer()
+
+		client = relocated
+	}
+
+	// The device is also located if the apply stopped after a network change so that the rollback
+	// reaches it at its new address
+
+	networkChanged := false
+	for _, changed := range report.Changed {
+		networkChanged = networkChanged || IsNetworkComponent(changed.Key)
+	}
+
+	if report.Network != nil && networkChanged {
+		err := t.verifyNetwork(ctx, report, options)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	if options.Transactional && len(report.Failed) > 0 {
+		t.rollback(ctx, report, snapshot, options)
+	}
+
+	return report, errors.ErrorOrNil()
+}
+
+// relocate locates the device after the connection was lost while writing a network component and
+// returns a client for its address
+func (t *Client) relocate(ctx context.Context, report *ShellyApplyReport, options *ApplyOptions) (*Client, func(), error) {
+
+	if options.Dial == nil {
+		return nil, nil, fmt.Errorf("connection lost after network change and the device can not be located")
+	}
+
+	err := t.verifyNetwork(ctx, report, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return options.Dial(report.Network.Address)
+}
+
+// applyComponent writes the changed fields of the component
+func (t *Client) applyComponent(ctx context.Context, component *ShellyPlanComponent) (*ShellyApplyComponent, error) {
+
+	result := &ShellyApplyComponent{
+		Key: component.Key,
+	}
+
+	componentType := component.Key
+	if componentKey, err := types.ParseComponentKey(component.Key); err == nil {
+		componentType = componentKey.Type
+	}
+
+	partial := any(nil)
+	for _, change := range component.Changes {
+
+		result.Fields = append(result.Fields, change.Path)
+
+		// A plan keeps secret references; they are resolved when the plan is applied
+		value := change.To
+		if s, ok := value.(string); ok && types.SecretReferenceFields[joinPath(componentType, change.Path)] {
+			secret, err := util.ResolveSecret(s)
+			if err != nil {
+				return result, fmt.Errorf("%s :: %w", change.Path, err)
+			}
+			value = secret
+		}
+
+		partial = setPath(partial, change.Path, value)
+	}
+
+	zap.L().Debug(fmt.Sprintf("applying %s fields %s", component.Key, strings.Join(result.Fields, ",")))
+
+	resp, err := t.SetComponentConfig(ctx, component.Key, partial)
+	if err != nil {
+		return result, err
+	}
+
+	result.RestartRequired = resp.RestartRequired
+
+	return result, nil
+}
+
+// rollback restores the changed components from the snapshot in reverse order. Fields that were absent
+// from the snapshot are not sent. If the device moved to a new address it is restored through a client
+// for that address.
+func (t *Client) rollback(ctx context.Context, report *ShellyApplyReport, snapshot map[string]any, options *ApplyOptions) {
+
+	report.RolledBack = true
+
+	client := t
+
+	network := report.Network
+	if network != nil && network.Verified && network.Address != "" && network.Address != network.Previous && options.Dial != nil {
+		relocated, closer, err := options.Dial(network.Address)
+		if err != nil {
+			zap.L().Debug(fmt.Sprintf("reaching %s to roll back failed: %v", network.Address, err))
+		} else {
+			defer closer()
+			client = relocated
+		}
+	}
+
+	for i := len(report.Changed) - 1; i >= 0; i-- {
+
+		changed := report.Changed[i]
+
+		result := &ShellyApplyComponent{
+			Key: changed.Key,
+		}
+
+		if changed.Key == authKey {
+			result.Fields = changed.Fields
+			result.Error = "auth can not be restored because the previous value can not be read"
+			report.RevertFailed = append(report.RevertFailed, result)
+			continue
+		}
+
+		partial := any(nil)
+		for _, path := range changed.Fields {
+			if !hasPath(snapshot[changed.Key], path) {
+				zap.L().Debug(fmt.Sprintf("not reverting %s field %s; it was absent", changed.Key, path))
+				continue
+			}
+			result.Fields = append(result.Fields, path)
+			partial = setPath(partial, path, getPath(snapshot[changed.Key], path))
+		}
+
+		if partial == nil {
+			continue
+		}
+
+		zap.L().Debug(fmt.Sprintf("reverting %s fields %s", changed.Key, strings.Join(result.Fields, ",")))
+
+		resp, err := client.SetComponentConfig(ctx, changed.Key, partial)
+		if err != nil {
+			result.Error = err.Error()
+			report.RevertFailed = append(report.RevertFailed, result)
+			continue
+		}
+
+		result.RestartRequired = resp.RestartRequired
+		report.RestartRequired = report.RestartRequired || resp.RestartRequired
+		report.Reverted = append(report.Reverted, result)
+	}
+}
+
+// sortPlanComponents returns the components in apply order
+func sortPlanComponents(components []*ShellyPlanComponent) []*ShellyPlanComponent {
+
+	sorted := append([]*ShellyPlanComponent{}, components...)
+
+	order := func(key string) int {
+		componentKey, err := types.ParseComponentKey(key)
+		if err != nil {
+			return applyOrderUnknown
+		}
+		if o, ok := applyOrder[componentKey.Type]; ok {
+			return o
+		}
+		return applyOrderUnknown
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		oi, oj := order(sorted[i].Key), order(sorted[j].Key)
+		if oi != oj {
+			return oi < oj
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	return sorted
+}
+
+// setPath sets the value at the dot separated path within root and returns root. An empty path
+// replaces root.
+func setPath(root any, path string, value any) any {
+
+	if path == "" {
+		return value
+	}
+
+	first, rest, _ := strings.Cut(path, ".")
+
+	m, ok := root.(map[string]any)
+	if !ok {
+		m = make(map[string]any)
+	}
+
+	m[first] = setPath(m[first], rest, value)
+
+	return m
+}
+
+// getPath returns the value at the dot separated path within root or nil if it does not exist
+func getPath(root any, path string) any {
+
+	if path == "" {
+		return root
+	}
+
+	first, rest, _ := strings.Cut(path, ".")
+
+	m, ok := root.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	return getPath(m[first], rest)
+}
@@ -0,0 +1,164 @@
+package shelly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+// recordingHandler answers every request with an empty SetConfig result and records the params
+type recordingHandler struct {
+	params map[string]json.RawMessage
+}
+
+func (t *recordingHandler) Send(ctx context.Context, request *Request) ([]byte, error) {
+
+	b, err := json.Marshal(request.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	t.params[request.Method] = b
+
+	return []byte(`{"id":1,"result":{"restart_required":false}}`), nil
+}
+
+func (t *recordingHandler) Close() {}
+
+// recordingContract only provides the message handler
+type recordingContract struct {
+	clientContract
+	handler *recordingHandler
+}
+
+func (t *recordingContract) NewHandle() MessageHandler {
+	return t.handler
+}
+
+func TestApplyPlanPartialSetConfig(t *testing.T) {
+
+	current, desired := decodeTestConfigs(t)
+
+	plan, err := NewPlan(current, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &recordingHandler{
+		params: make(map[string]json.RawMessage),
+	}
+
+	client := New(&recordingContract{handler: handler})
+
+	_, err = client.ApplyPlan(context.Background(), plan, desired, &ApplyOptions{Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Switch.SetConfig": `{"id":0,"config":{"auto_off":true}}`,
+		"Sys.SetConfig":    `{"config":{"device":{"name":"kitchen"}}}`,
+	}
+
+	if len(handler.params) != len(expected) {
+		t.Fatalf("expected %d requests, got %v", len(expected), handler.params)
+	}
+
+	for method, params := range expected {
+		if string(handler.params[method]) != params {
+			t.Errorf("%s: expected params %s, got %s", method, params, string(handler.params[method]))
+		}
+	}
+}
+
+// failingHandler answers GetDeviceInfo and fails every other request with err
+type failingHandler struct {
+	err error
+}
+
+func (t *failingHandler) Send(ctx context.Context, request *Request) ([]byte, error) {
+
+	if request.Method == "Shelly.GetDeviceInfo" {
+		return []byte(`{"id":1,"result":{"id":"shellyplus1-a8032ab636ec"}}`), nil
+	}
+
+	return nil, t.err
+}
+
+func (t *failingHandler) Close() {}
+
+type failingContract struct {
+	clientContract
+	handler *failingHandler
+}
+
+func (t *failingContract) NewHandle() MessageHandler {
+	return t.handler
+}
+
+func TestApplyPlanNetworkErrors(t *testing.T) {
+
+	plan := &ShellyPlan{
+		Components: []*ShellyPlanComponent{
+			{
+				Key:     "wifi",
+				Changes: []*ShellyPlanChange{{Path: "sta.ssid", From: "home", To: "office"}},
+			},
+		},
+		Changes: 1,
+	}
+
+	// An error that is not a transport error is a failure of the component
+	client := New(&failingContract{handler: &failingHandler{err: errors.New("invalid argument")}})
+
+	report, err := client.ApplyPlan(context.Background(), plan, nil, &ApplyOptions{Force: true})
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+
+	if report == nil || len(report.Failed) != 1 || len(report.Changed) != 0 {
+		t.Fatalf("expected wifi to be failed, got %+v", report)
+	}
+
+	// A lost connection leaves the component changed but unconfirmed
+	client = New(&failingContract{handler: &failingHandler{err: io.ErrUnexpectedEOF}})
+
+	report, _ = client.ApplyPlan(context.Background(), plan, nil, &ApplyOptions{Force: true})
+
+	if report == nil || len(report.Changed) != 1 || !report.Changed[0].Unconfirmed {
+		t.Fatalf("expected wifi to be changed and unconfirmed, got %+v", report)
+	}
+}
+
+func TestSetConfigUnknownComponents(t *testing.T) {
+
+	handler := &recordingHandler{
+		params: make(map[string]json.RawMessage),
+	}
+
+	client := New(&recordingContract{handler: handler})
+
+	report, err := client.SetConfig(context.Background(), &ShellyConfig{
+		Unknown: map[string]json.RawMessage{
+			"em:0":     json.RawMessage(`{"name":"grid"}`),
+			"widget:0": json.RawMessage(`{"name":"x"}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(handler.params["EM.SetConfig"]) != `{"id":0,"config":{"name":"grid"}}` {
+		t.Errorf("em:0 is not set with EM.SetConfig: %v", handler.params)
+	}
+
+	if len(handler.params) != 1 {
+		t.Errorf("a component without a known method is sent: %v", handler.params)
+	}
+
+	if len(report.Skipped) != 1 || report.Skipped[0] != "widget:0" {
+		t.Errorf("expected widget:0 to be skipped, got %v", report.Skipped)
+	}
+}
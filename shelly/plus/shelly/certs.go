@@ -0,0 +1,191 @@
+package shelly
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+type putFunc func(ctx context.Context, params *ShellyParams) (*SetReport, error)
+
+// UploadUserCA validates the PEM data and uploads it as the user CA. The data must contain one or
+// more certificates. Data larger than CertChunkSize is sent in chunks.
+func (t *Client) UploadUserCA(ctx context.Context, data []byte) (*ShellyCertReport, error) {
+
+	certs, err := util.ParseCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("user CA :: %w", err)
+	}
+
+	report := newCertReport(certs)
+
+	err = t.putChunked(ctx, t.PutUserCA, data, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// UploadTLSClientCert validates the PEM data and uploads it as the TLS client certificate. If key is
+// not nil the certificate must match the key. Data larger than CertChunkSize is sent in chunks.
+func (t *Client) UploadTLSClientCert(ctx context.Context, data []byte, key []byte) (*ShellyCertReport, error) {
+
+	certs, err := util.ParseCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("TLS client cert :: %w", err)
+	}
+
+	if key != nil {
+		err = checkKeyMatch(certs[0], key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := newCertReport(certs)
+
+	err = t.putChunked(ctx, t.PutTLSClientCert, data, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// UploadTLSClientKey validates the PEM data and uploads it as the TLS client key. If cert is not nil
+// the key must match the certificate. Data larger than CertChunkSize is sent in chunks.
+func (t *Client) UploadTLSClientKey(ctx context.Context, data []byte, cert []byte) (*ShellyCertReport, error) {
+
+	_, err := util.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("TLS client key :: %w", err)
+	}
+
+	report := &ShellyCertReport{}
+
+	if cert != nil {
+
+		certs, err := util.ParseCertificates(cert)
+		if err != nil {
+			return nil, fmt.Errorf("TLS client cert :: %w", err)
+		}
+
+		err = checkKeyMatch(certs[0], data)
+		if err != nil {
+			return nil, err
+		}
+
+		report = newCertReport(certs)
+	}
+
+	err = t.putChunked(ctx, t.PutTLSClientKey, data, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// DeleteUserCA deletes the user CA
+func (t *Client) DeleteUserCA(ctx context.Context) (*ShellyCertReport, error) {
+	return t.putDelete(ctx, t.PutUserCA)
+}
+
+// DeleteTLSClientCert deletes the TLS client certificate
+func (t *Client) DeleteTLSClientCert(ctx context.Context) (*ShellyCertReport, error) {
+	return t.putDelete(ctx, t.PutTLSClientCert)
+}
+
+// DeleteTLSClientKey deletes the TLS client key
+func (t *Client) DeleteTLSClientKey(ctx context.Context) (*ShellyCertReport, error) {
+	return t.putDelete(ctx, t.PutTLSClientKey)
+}
+
+func (t *Client) putDelete(ctx context.Context, put putFunc) (*ShellyCertReport, error) {
+
+	result, err := put(ctx, &ShellyParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShellyCertReport{
+		Src:             result.Src,
+		Deleted:         true,
+		Chunks:          1,
+		RestartRequired: result.RestartRequired,
+	}, nil
+}
+
+// putChunked sends the data in chunks of CertChunkSize. Append is true for every chunk but the last.
+func (t *Client) putChunked(ctx context.Context, put putFunc, data []byte, report *ShellyCertReport) error {
+
+	chunkSize := CertChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(data)
+	}
+
+	for offset := 0; offset < len(data); offset += chunkSize {
+
+		end := offset + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunk := string(data[offset:end])
+		appendMore := end < len(data)
+
+		zap.L().Debug(fmt.Sprintf("sending chunk %d bytes %d-%d append %t", report.Chunks+1, offset, end, appendMore))
+
+		result, err := put(ctx, &ShellyParams{
+			Data:   &chunk,
+			Append: &appendMore,
+		})
+		if err != nil {
+			return fmt.Errorf("chunk %d :: %w", report.Chunks+1, err)
+		}
+
+		report.Src = result.Src
+		report.Chunks++
+		report.Bytes = end
+		report.RestartRequired = report.RestartRequired || result.RestartRequired
+	}
+
+	return nil
+}
+
+func newCertReport(certs []*x509.Certificate) *ShellyCertReport {
+
+	report := &ShellyCertReport{
+		Warnings: util.CertificateWarnings(certs, time.Now(), CertExpiryWarning),
+	}
+
+	for _, cert := range certs {
+		report.Subjects = append(report.Subjects, cert.Subject.String())
+		if report.NotAfter == nil || cert.NotAfter.Before(*report.NotAfter) {
+			notAfter := cert.NotAfter
+			report.NotAfter = &notAfter
+		}
+	}
+
+	return report
+}
+
+func checkKeyMatch(cert *x509.Certificate, keyData []byte) error {
+
+	key, err := util.ParsePrivateKey(keyData)
+	if err != nil {
+		return fmt.Errorf("TLS client key :: %w", err)
+	}
+
+	if !util.KeyMatchesCertificate(cert, key) {
+		return fmt.Errorf("TLS client key does not match certificate %s", cert.Subject.String())
+	}
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/jodydadescott/shelly-manager/shelly/plus/bluetooth"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/cloud"
@@ -21,6 +23,7 @@ import (
 	"github.com/jodydadescott/shelly-manager/shelly/util"
 
 	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
 )
 
 // Result internal use only
@@ -35,6 +38,18 @@ type SetConfigResponse struct {
 	Result *Result `json:"result,omitempty"`
 }
 
+// RawResponse internal use only
+type RawResponse struct {
+	Response
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// ComponentConfigParams internal use only
+type ComponentConfigParams struct {
+	ID     *int `json:"id,omitempty"`
+	Config any  `json:"config"`
+}
+
 // GetConfigResponse internal use only
 type GetConfigResponse struct {
 	Response
@@ -65,6 +80,47 @@ type ListMethodsResponse struct {
 	Result *ShellyRPCMethods `json:"result,omitempty"`
 }
 
+// ListProfilesResponse internal use only
+type ListProfilesResponse struct {
+	Response
+	Result *ShellyProfiles `json:"result,omitempty"`
+}
+
+// SetProfileResponse internal use only
+type SetProfileResponse struct {
+	Response
+	Result *ShellyProfileReport `json:"result,omitempty"`
+}
+
+// ListTimezonesResponse internal use only
+type ListTimezonesResponse struct {
+	Response
+	Result *ShellyTimezones `json:"result,omitempty"`
+}
+
+// DetectLocationResponse internal use only
+type DetectLocationResponse struct {
+	Response
+	Result *SystemLocation `json:"result,omitempty"`
+}
+
+// GetComponentsResponse internal use only
+type GetComponentsResponse struct {
+	Response
+	Result *ShellyComponents `json:"result,omitempty"`
+}
+
+// UpdateParams internal use only
+type UpdateParams struct {
+	Stage *string `json:"stage,omitempty"`
+	Url   *string `json:"url,omitempty"`
+}
+
+// ProfileParams internal use only
+type ProfileParams struct {
+	Name string `json:"name"`
+}
+
 type clientContract interface {
 	System() *system.Client
 	Bluetooth() *bluetooth.Client
@@ -77,6 +133,7 @@ type clientContract interface {
 	Websocket() *websocket.Client
 	Ethernet() *ethernet.Client
 	NewHandle() MessageHandler
+	Subscribe(handler NotificationHandler) func()
 }
 
 func New(clientContract clientContract) *Client {
@@ -190,434 +247,261 @@ func (t *Client) GetConfig(ctx context.Context) (*ShellyConfig, error) {
 }
 
 // SetConfig sets the configuration for each component with non nil config. Note that this function
-// calls into each componenet as necessary.
+// calls into each componenet as necessary. Unknown components are set with the generic
+// <Type>.SetConfig method if the RPC name of their type is known; the others are not sent and are
+// reported as skipped.
 func (t *Client) SetConfig(ctx context.Context, config *ShellyConfig) (*ShellyReport, error) {
 
 	mresp := &ShellyReport{}
 
 	var errors *multierror.Error
 
+	record := func(name string, resp *SetReport, err error) {
+		if err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("%s :: %v", name, err))
+			return
+		}
+		if resp.RestartRequired {
+			mresp.RestartRequired = true
+		}
+	}
+
 	if config.Bluetooth != nil {
 		resp, err := t.Bluetooth().SetConfig(ctx, config.Bluetooth)
 		mresp.Bluetooth = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Bluetooth :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+		record("Bluetooth", resp, err)
 	}
 
 	if config.Cloud != nil {
 		resp, err := t.Cloud().SetConfig(ctx, config.Cloud)
 		mresp.Cloud = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Cloud :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+		record("Cloud", resp, err)
 	}
 
 	if config.Mqtt != nil {
 		resp, err := t.Mqtt().SetConfig(ctx, config.Mqtt)
 		mresp.Mqtt = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Mqtt :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+		record("Mqtt", resp, err)
 	}
 
-	if config.Light0 != nil {
-		resp, err := t.Light().SetConfig(ctx, 0, config.Light0)
-		mresp.Light0 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Light0 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
+	for _, id := range sortedIDs(config.Light) {
+		resp, err := t.Light().SetConfig(ctx, id, config.Light[id])
+		if mresp.Light == nil {
+			mresp.Light = make(map[int]*SetReport)
 		}
+		mresp.Light[id] = resp
+		record(fmt.Sprintf("Light%d", id), resp, err)
 	}
 
-	if config.Light1 != nil {
-		resp, err := t.Light().SetConfig(ctx, 1, config.Light1)
-		mresp.Light1 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Light1 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
+	for _, id := range sortedIDs(config.Input) {
+		resp, err := t.Input().SetConfig(ctx, id, config.Input[id])
+		if mresp.Input == nil {
+			mresp.Input = make(map[int]*SetReport)
 		}
+		mresp.Input[id] = resp
+		record(fmt.Sprintf("Input%d", id), resp, err)
 	}
 
-	if config.Light2 != nil {
-		resp, err := t.Light().SetConfig(ctx, 2, config.Light2)
-		mresp.Light2 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Light2 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
+	for _, id := range sortedIDs(config.Switch) {
+		resp, err := t.Switch().SetConfig(ctx, id, config.Switch[id])
+		if mresp.Switch == nil {
+			mresp.Switch = make(map[int]*SetReport)
 		}
+		mresp.Switch[id] = resp
+		record(fmt.Sprintf("Switch%d", id), resp, err)
 	}
 
-	if config.Light3 != nil {
-		resp, err := t.Light().SetConfig(ctx, 3, config.Light3)
-		mresp.Light3 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Light3 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	var unknownKeys []string
+	for key := range config.Unknown {
+		unknownKeys = append(unknownKeys, key)
 	}
+	sort.Strings(unknownKeys)
 
-	if config.Light4 != nil {
-		resp, err := t.Light().SetConfig(ctx, 4, config.Light4)
-		mresp.Light4 = resp
+	for _, key := range unknownKeys {
 
+		componentKey, err := types.ParseComponentKey(key)
 		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Light4 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
+			record(key, nil, err)
+			continue
 		}
-	}
-
-	if config.Light5 != nil {
-		resp, err := t.Light().SetConfig(ctx, 5, config.Light5)
-		mresp.Light5 = resp
 
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Light5 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
+		if _, ok := ComponentMethod(componentKey.Type, "SetConfig"); !ok {
+			zap.L().Debug(fmt.Sprintf("%s is skipped; the SetConfig method of %s is not known", key, componentKey.Type))
+			mresp.Skipped = append(mresp.Skipped, key)
+			continue
 		}
-	}
-
-	if config.Light6 != nil {
-		resp, err := t.Light().SetConfig(ctx, 6, config.Light6)
-		mresp.Light6 = resp
 
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Light6 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
+		resp, err := t.SetComponentConfig(ctx, key, config.Unknown[key])
+		if mresp.Unknown == nil {
+			mresp.Unknown = make(map[string]*SetReport)
 		}
+		mresp.Unknown[key] = resp
+		record(key, resp, err)
 	}
 
-	if config.Light7 != nil {
-		resp, err := t.Light().SetConfig(ctx, 7, config.Light7)
-		mresp.Light7 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Light7 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if config.System != nil {
+		resp, err := t.System().SetConfig(ctx, config.System)
+		mresp.System = resp
+		record("System", resp, err)
 	}
 
-	if config.Input0 != nil {
-		resp, err := t.Input().SetConfig(ctx, 0, config.Input0)
-		mresp.Input0 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Input0 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if config.Websocket != nil {
+		resp, err := t.Websocket().SetConfig(ctx, config.Websocket)
+		mresp.Websocket = resp
+		record("Websocket", resp, err)
 	}
 
-	if config.Input1 != nil {
-		resp, err := t.Input().SetConfig(ctx, 1, config.Input1)
-		mresp.Input1 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Input1 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if config.Ethernet != nil {
+		resp, err := t.Ethernet().SetConfig(ctx, config.Ethernet)
+		mresp.Ethernet = resp
+		record("Ethernet", resp, err)
 	}
 
-	if config.Input2 != nil {
-		resp, err := t.Input().SetConfig(ctx, 2, config.Input2)
-		mresp.Input2 = resp
+	// We set WiFi last because we may lose connectivity after the change
 
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Input2 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if config.Wifi != nil {
+		resp, err := t.WiFi().SetConfig(ctx, config.Wifi)
+		mresp.Wifi = resp
+		record("WiFi", resp, err)
 	}
 
-	if config.Input3 != nil {
-		resp, err := t.Input().SetConfig(ctx, 3, config.Input3)
-		mresp.Input3 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Input3 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if config.Auth != nil {
+		resp, err := t.SetAuth(ctx, &ShellyParams{
+			Ha1:  config.Auth.Pass,
+			User: &config.Auth.User,
+		})
+		record("Auth", resp, err)
 	}
 
-	if config.Input4 != nil {
-		resp, err := t.Input().SetConfig(ctx, 4, config.Input4)
-		mresp.Input4 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Input4 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
-	}
+	return mresp, errors.ErrorOrNil()
+}
 
-	if config.Input5 != nil {
-		resp, err := t.Input().SetConfig(ctx, 5, config.Input5)
-		mresp.Input5 = resp
+// SetComponentConfig sets the config of the component with the specified key (<type>:<id> or <type>)
+// using the generic <Type>.SetConfig method. This is used for components that have no client of their own.
+// An error is returned if the RPC name of the type is not known.
+func (t *Client) SetComponentConfig(ctx context.Context, key string, config any) (*SetReport, error) {
 
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Input5 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	componentKey, err := types.ParseComponentKey(key)
+	if err != nil {
+		return nil, err
 	}
 
-	if config.Input6 != nil {
-		resp, err := t.Input().SetConfig(ctx, 6, config.Input6)
-		mresp.Input6 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Input6 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	params := &ComponentConfigParams{
+		Config: config,
 	}
 
-	if config.Input7 != nil {
-		resp, err := t.Input().SetConfig(ctx, 7, config.Input7)
-		mresp.Input7 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Input7 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if componentKey.HasID {
+		params.ID = &componentKey.ID
 	}
 
-	if config.Switch0 != nil {
-		resp, err := t.Switch().SetConfig(ctx, 0, config.Switch0)
-		mresp.Switch0 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Switch0 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	method, ok := ComponentMethod(componentKey.Type, "SetConfig")
+	if !ok {
+		return nil, fmt.Errorf("the SetConfig method of %s is not known", componentKey.Type)
 	}
 
-	if config.Switch1 != nil {
-		resp, err := t.Switch().SetConfig(ctx, 1, config.Switch1)
-		mresp.Switch1 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Switch1 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: params,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if config.Switch2 != nil {
-		resp, err := t.Switch().SetConfig(ctx, 2, config.Switch2)
-		mresp.Switch2 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Switch2 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	response := &SetConfigResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
 	}
 
-	if config.Switch3 != nil {
-		resp, err := t.Switch().SetConfig(ctx, 3, config.Switch3)
-		mresp.Switch3 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Switch3 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if response.Error != nil {
+		return nil, response.Error
 	}
 
-	if config.Switch4 != nil {
-		resp, err := t.Switch().SetConfig(ctx, 4, config.Switch4)
-		mresp.Switch4 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Switch4 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
 	}
 
-	if config.Switch5 != nil {
-		resp, err := t.Switch().SetConfig(ctx, 5, config.Switch5)
-		mresp.Switch5 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Switch5 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
-	}
+	return &SetReport{
+		Src:             response.Src,
+		RestartRequired: response.Result.RestartRequired,
+	}, nil
+}
 
-	if config.Switch6 != nil {
-		resp, err := t.Switch().SetConfig(ctx, 6, config.Switch6)
-		mresp.Switch6 = resp
+// Call sends the method with the params and returns the raw result. It is meant for methods that
+// have no typed wrapper and for callers that need the exact output of the device.
+func (t *Client) Call(ctx context.Context, method string, params any) (json.RawMessage, error) {
 
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Switch6 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: params,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if config.Switch7 != nil {
-		resp, err := t.Switch().SetConfig(ctx, 7, config.Switch7)
-		mresp.Switch7 = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Switch7 :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	response := &RawResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
 	}
 
-	if config.System != nil {
-		resp, err := t.System().SetConfig(ctx, config.System)
-		mresp.System = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("System :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if response.Error != nil {
+		return nil, response.Error
 	}
 
-	if config.Websocket != nil {
-		resp, err := t.Websocket().SetConfig(ctx, config.Websocket)
-		mresp.Websocket = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Websocket :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
 	}
 
-	if config.Ethernet != nil {
-		resp, err := t.Ethernet().SetConfig(ctx, config.Ethernet)
-		mresp.Ethernet = resp
+	return response.Result, nil
+}
 
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Ethernet :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+// ComponentMethod returns the RPC method of a component type, for example cover and GetConfig
+// returns Cover.GetConfig. False is returned if the RPC name of the type is not known.
+func ComponentMethod(componentType string, method string) (string, bool) {
+	if componentType == "" {
+		return method, true
 	}
-
-	// We set WiFi last because we may lose connectivity after the change
-
-	if config.Wifi != nil {
-		resp, err := t.WiFi().SetConfig(ctx, config.Wifi)
-		mresp.Wifi = resp
-
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("WiFi :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+	name, ok := componentNames[componentType]
+	if !ok {
+		return "", false
 	}
+	return name + "." + method, true
+}
 
-	if config.Auth != nil {
-		resp, err := t.SetAuth(ctx, &ShellyParams{
-			Ha1:  config.Auth.Pass,
-			User: &config.Auth.User,
-		})
+// componentNames maps the component types to the name of their RPC methods. The names can not be
+// derived from the type, for example em is EM and pm1 is PM1.
+var componentNames = map[string]string{
+	"ble":         bluetooth.Component,
+	"cloud":       cloud.Component,
+	"eth":         ethernet.Component,
+	"input":       input.Component,
+	"light":       light.Component,
+	"mqtt":        mqtt.Component,
+	"switch":      switchx.Component,
+	"sys":         system.Component,
+	"ws":          websocket.Component,
+	"wifi":        wifi.Component,
+	"cover":       "Cover",
+	"em":          "EM",
+	"em1":         "EM1",
+	"humidity":    "Humidity",
+	"illuminance": "Illuminance",
+	"pm1":         "PM1",
+	"script":      "Script",
+	"smoke":       "Smoke",
+	"temperature": "Temperature",
+	"ui":          "UI",
+	"voltmeter":   "Voltmeter",
+}
 
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Auth :: %v", err))
-		} else {
-			if resp.RestartRequired {
-				mresp.RestartRequired = true
-			}
-		}
+func sortedIDs[T any](components map[int]T) []int {
+	var ids []int
+	for id := range components {
+		ids = append(ids, id)
 	}
-
-	return mresp, errors.ErrorOrNil()
+	sort.Ints(ids)
+	return ids
 }
 
 // GetDeviceInfo returns information about the device.
@@ -682,13 +566,26 @@ func (t *Client) CheckForUpdate(ctx context.Context) (*UpdatesReport, error) {
 	}, nil
 }
 
-// Update updates the firmware version of the device.
+// Update updates the firmware version of the device. The function returns as soon as the device accepts
+// the request; use Upgrade to follow the update until the device is running the new firmware.
 func (t *Client) Update(ctx context.Context, params *ShellyParams) (*SetReport, error) {
 
 	method := Component + ".Update"
 
+	updateParams := &UpdateParams{}
+
+	if params != nil {
+		if params.Stage != nil && *params.Stage != "" {
+			updateParams.Stage = params.Stage
+		}
+		if params.Url != nil && *params.Url != "" {
+			updateParams.Url = params.Url
+		}
+	}
+
 	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
 		Method: method,
+		Params: updateParams,
 	})
 	if err != nil {
 		return nil, err
@@ -704,10 +601,15 @@ func (t *Client) Update(ctx context.Context, params *ShellyParams) (*SetReport,
 		return nil, response.Error
 	}
 
-	return &SetReport{
-		Src:             response.Src,
-		RestartRequired: response.Result.RestartRequired,
-	}, nil
+	report := &SetReport{
+		Src: response.Src,
+	}
+
+	if response.Result != nil {
+		report.RestartRequired = response.Result.RestartRequired
+	}
+
+	return report, nil
 }
 
 // FactoryReset resets the configuration to its default state
@@ -791,6 +693,301 @@ func (t *Client) Reboot(ctx context.Context) error {
 	return nil
 }
 
+// ListProfiles returns the profiles supported by the device. Only multi-profile devices support this method.
+func (t *Client) ListProfiles(ctx context.Context) (*ShellyProfiles, error) {
+
+	method := Component + ".ListProfiles"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListProfilesResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// SetProfile sets the device profile. The device reboots after the profile is changed. If wait is
+// true the function blocks until the device is back online or the context is done.
+func (t *Client) SetProfile(ctx context.Context, name string, wait bool) (*ShellyProfileReport, error) {
+
+	method := Component + ".SetProfile"
+
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	var before *SystemStatus
+
+	if wait {
+		status, err := t.System().GetStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		before = status
+	}
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: &ProfileParams{
+			Name: name,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SetProfileResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	report := response.Result
+	report.Src = response.Src
+
+	if !wait || report.ProfileWas == name {
+		return report, nil
+	}
+
+	err = t.WaitReboot(ctx, before)
+	if err != nil {
+		return report, err
+	}
+
+	report.Rebooted = true
+	return report, nil
+}
+
+// WaitReboot blocks until the device has rebooted and is responding again or the context is done. The
+// status should be obtained before the action that triggers the reboot; the device is considered rebooted
+// once the uptime it reports is lower than the uptime in the status.
+func (t *Client) WaitReboot(ctx context.Context, before *SystemStatus) error {
+
+	if before == nil {
+		return fmt.Errorf("status is required")
+	}
+
+	for {
+
+		select {
+
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for device to reboot")
+
+		case <-time.After(RebootPollInterval):
+
+		}
+
+		status, err := t.System().GetStatus(ctx)
+		if err != nil {
+			zap.L().Debug(fmt.Sprintf("device not responding yet: %v", err))
+			continue
+		}
+
+		if status.Uptime < before.Uptime {
+			return nil
+		}
+	}
+}
+
+// ListTimezones returns a list of all timezones known to the device
+func (t *Client) ListTimezones(ctx context.Context) (*ShellyTimezones, error) {
+
+	method := Component + ".ListTimezones"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListTimezonesResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// DetectLocation detects the timezone and the coordinates of the device based on its IP address. The
+// result is not applied to the config; use Sys.SetConfig to store it.
+func (t *Client) DetectLocation(ctx context.Context) (*SystemLocation, error) {
+
+	method := Component + ".DetectLocation"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DetectLocationResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// GetComponents returns a single page of the components of the device. Use GetAllComponents
+// to fetch every page.
+func (t *Client) GetComponents(ctx context.Context, params *ShellyComponentsParams) (*ShellyComponents, error) {
+
+	method := Component + ".GetComponents"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComponentsResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("Result is missing from response")
+	}
+
+	return response.Result, nil
+}
+
+// GetAllComponents returns the components of the device following the pagination of GetComponents
+func (t *Client) GetAllComponents(ctx context.Context, params *ShellyComponentsParams) (*ShellyComponents, error) {
+
+	if params == nil {
+		params = &ShellyComponentsParams{}
+	}
+
+	params = params.Clone()
+
+	offset := 0
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+
+	result := &ShellyComponents{
+		Offset: offset,
+	}
+
+	for {
+
+		params.Offset = &offset
+
+		page, err := t.GetComponents(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Components = append(result.Components, page.Components...)
+		result.CfgRev = page.CfgRev
+		result.Total = page.Total
+
+		offset = offset + len(page.Components)
+
+		if len(page.Components) == 0 || offset >= page.Total {
+			return result, nil
+		}
+	}
+}
+
+// GetComponentConfig returns the configuration of the component with the specified key (<type>:<id> or <type>)
+func (t *Client) GetComponentConfig(ctx context.Context, key string) (any, error) {
+
+	component, err := t.getComponent(ctx, key, "config")
+	if err != nil {
+		return nil, err
+	}
+
+	return component.Config, nil
+}
+
+// GetComponentStatus returns the status of the component with the specified key (<type>:<id> or <type>)
+func (t *Client) GetComponentStatus(ctx context.Context, key string) (any, error) {
+
+	component, err := t.getComponent(ctx, key, "status")
+	if err != nil {
+		return nil, err
+	}
+
+	return component.Status, nil
+}
+
+func (t *Client) getComponent(ctx context.Context, key string, include string) (*ShellyComponent, error) {
+
+	if key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	result, err := t.GetAllComponents(ctx, &ShellyComponentsParams{
+		Include: []string{include},
+		Keys:    []string{key},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range result.Components {
+		if component.Key == key {
+			return &component, nil
+		}
+	}
+
+	return nil, fmt.Errorf("component %s not found", key)
+}
+
 // HashAuth checks to see if the Ha1 attribute is hashed. If so no action is taken and a nil error is returned.
 // Otherwise the hash is created and the Ha1 attribute is updated. This function is exposed so that the caller
 // may create configuration.
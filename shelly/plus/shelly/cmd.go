@@ -4,19 +4,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/fwserver"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/schema"
 	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+	"github.com/jodydadescott/shelly-manager/shelly/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
 
 type callback interface {
+	GetHostname() string
 	WriteObject(any) error
 	WriteStderr(s string)
 	ReadInput() ([]byte, error)
 	Shelly() (*Client, error)
+	ShellyFor(hostname string) (*Client, func(), error)
 	RebootDevice(ctx context.Context) error
 }
 
@@ -24,10 +31,33 @@ func NewCmd(callback callback) *cobra.Command {
 
 	var stageArg string
 	var urlArg string
+	var fileArg string
+	var portArg int
 	var newUserArg string
 	var newPasswordArg string
-	var appendArg string
+	var deleteCertArg bool
+	var deleteKeyArg bool
+	var deleteCAArg bool
+	var keyFileArg string
+	var certFileArg string
 	var autorebootArg bool
+	var componentArg string
+	var keysArg []string
+	var includeArg []string
+	var dynamicOnlyArg bool
+	var waitArg bool
+	var jsonArg bool
+	var planFileArg string
+	var forceArg bool
+	var transactionalArg bool
+	var noValidateArg bool
+	var cfgRevArg int
+	var varsFileArg string
+	var varArgs []string
+	var offlineArg bool
+	var rebootTimeoutArg time.Duration
+	var downloadTimeoutArg time.Duration
+	var upgradeTimeoutArg time.Duration
 
 	rootCmd := &cobra.Command{
 		Use:   "shelly",
@@ -44,6 +74,14 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
+			if componentArg != "" {
+				result, err := client.GetComponentConfig(cmd.Context(), componentArg)
+				if err != nil {
+					return err
+				}
+				return callback.WriteObject(result)
+			}
+
 			result, err := client.GetConfig(cmd.Context())
 			if err != nil {
 				return err
@@ -53,6 +91,8 @@ func NewCmd(callback callback) *cobra.Command {
 		},
 	}
 
+	getConfigCmd.PersistentFlags().StringVar(&componentArg, "component", "", "only return the config of the component with the key <type>:<id>")
+
 	getStatusCmd := &cobra.Command{
 		Use:   "get-status",
 		Short: "Returns status",
@@ -63,6 +103,14 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
+			if componentArg != "" {
+				result, err := client.GetComponentStatus(cmd.Context(), componentArg)
+				if err != nil {
+					return err
+				}
+				return callback.WriteObject(result)
+			}
+
 			result, err := client.GetStatus(cmd.Context())
 			if err != nil {
 				return err
@@ -72,6 +120,130 @@ func NewCmd(callback callback) *cobra.Command {
 		},
 	}
 
+	getStatusCmd.PersistentFlags().StringVar(&componentArg, "component", "", "only return the status of the component with the key <type>:<id>")
+
+	getComponentsCmd := &cobra.Command{
+		Use:   "get-components",
+		Short: "Returns the components of the device",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			params := &ShellyComponentsParams{
+				Include: includeArg,
+				Keys:    keysArg,
+			}
+
+			if dynamicOnlyArg {
+				params.DynamicOnly = &dynamicOnlyArg
+			}
+
+			result, err := client.GetAllComponents(cmd.Context(), params)
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(result)
+		},
+	}
+
+	getComponentsCmd.PersistentFlags().StringSliceVar(&keysArg, "key", nil, "component key <type>:<id>; may be repeated")
+	getComponentsCmd.PersistentFlags().StringSliceVar(&includeArg, "include", nil, "properties to include; status, config")
+	getComponentsCmd.PersistentFlags().BoolVar(&dynamicOnlyArg, "dynamic-only", false, "only return dynamic components")
+
+	listProfilesCmd := &cobra.Command{
+		Use:   "list-profiles",
+		Short: "Returns the profiles supported by the device",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			result, err := client.ListProfiles(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(result)
+		},
+	}
+
+	setProfileCmd := &cobra.Command{
+		Use:   "set-profile [name]",
+		Short: "Sets the device profile; the device will reboot",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if len(args) <= 0 {
+				return fmt.Errorf("profile name is required")
+			}
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), rebootTimeoutArg)
+			defer cancel()
+
+			if waitArg {
+				callback.WriteStderr("device will reboot; waiting ...")
+			}
+
+			report, err := client.SetProfile(ctx, args[0], waitArg)
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(report)
+		},
+	}
+
+	setProfileCmd.PersistentFlags().BoolVar(&waitArg, "wait", true, "wait for the device to come back after the reboot")
+	setProfileCmd.PersistentFlags().DurationVar(&rebootTimeoutArg, "timeout", DefaultRebootTimeout, "maximum time to wait for the device")
+
+	listTimezonesCmd := &cobra.Command{
+		Use:   "list-timezones",
+		Short: "Returns the timezones known to the device",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			result, err := client.ListTimezones(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(result)
+		},
+	}
+
+	detectLocationCmd := &cobra.Command{
+		Use:   "detect-location",
+		Short: "Returns the timezone and location detected by the device",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			result, err := client.DetectLocation(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(result)
+		},
+	}
+
 	getInfoCmd := &cobra.Command{
 		Use:   "get-info",
 		Short: "Returns device info",
@@ -158,7 +330,7 @@ func NewCmd(callback callback) *cobra.Command {
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
-		Short: "Returns available update info",
+		Short: "Starts a firmware update; returns as soon as the device accepts it",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			client, err := callback.Shelly()
@@ -166,14 +338,64 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
+			if fileArg == "" {
+
+				report, err := client.Update(cmd.Context(), &ShellyParams{
+					Stage: &stageArg,
+					Url:   &urlArg,
+				})
+				if err != nil {
+					return err
+				}
+
+				return callback.WriteObject(report)
+			}
+
+			if urlArg != "" {
+				return fmt.Errorf("url and file are mutually exclusive")
+			}
+
+			server, err := fwserver.New(&fwserver.Config{
+				Filename: fileArg,
+				Hostname: callback.GetHostname(),
+				Port:     portArg,
+			})
+			if err != nil {
+				return err
+			}
+
+			url, err := server.Start()
+			if err != nil {
+				return err
+			}
+
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), fwserver.ShutdownTimeout)
+				defer cancel()
+				server.Shutdown(ctx)
+			}()
+
+			callback.WriteStderr(fmt.Sprintf("serving firmware at %s", url))
+
 			report, err := client.Update(cmd.Context(), &ShellyParams{
-				Stage: &stageArg,
-				Url:   &urlArg,
+				Url: &url,
 			})
 			if err != nil {
 				return err
 			}
 
+			select {
+
+			case <-server.Done():
+				callback.WriteStderr("firmware downloaded by device; device will install it and reboot")
+
+			case <-time.After(downloadTimeoutArg):
+				return fmt.Errorf("timeout waiting for the device to download the firmware")
+
+			case <-cmd.Context().Done():
+				return fmt.Errorf("cancelled waiting for the device to download the firmware")
+			}
+
 			return callback.WriteObject(report)
 
 		},
@@ -181,6 +403,55 @@ func NewCmd(callback callback) *cobra.Command {
 
 	updateCmd.PersistentFlags().StringVar(&stageArg, "stage", "", "The type of the new version - either stable or beta. By default updates to stable version. Optional")
 	updateCmd.PersistentFlags().StringVar(&urlArg, "url", "", "Url address of the update. Optional")
+	updateCmd.PersistentFlags().StringVar(&fileArg, "file", "", "local firmware zip file; served to the device by a built-in HTTP server. Optional")
+	updateCmd.PersistentFlags().IntVar(&portArg, "port", 0, "local port for the firmware server; default selects a free port")
+	updateCmd.PersistentFlags().DurationVar(&downloadTimeoutArg, "timeout", DefaultDownloadTimeout, "maximum time to wait for the device to download the firmware file")
+
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Updates the firmware, waits for the reboot and verifies the new version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), upgradeTimeoutArg)
+			defer cancel()
+
+			progress := func(event *NotificationEvent) {
+				if event.ProgressPercent != nil {
+					callback.WriteStderr(fmt.Sprintf("%s %d%% %s", event.Event, *event.ProgressPercent, event.Msg))
+					return
+				}
+				callback.WriteStderr(fmt.Sprintf("%s %s", event.Event, event.Msg))
+			}
+
+			report, err := client.Upgrade(ctx, &ShellyParams{
+				Stage: &stageArg,
+				Url:   &urlArg,
+			}, progress)
+			if err != nil {
+				return err
+			}
+
+			err = callback.WriteObject(report)
+			if err != nil {
+				return err
+			}
+
+			if !report.Success {
+				return fmt.Errorf("upgrade failed: %s", report.Message)
+			}
+
+			return nil
+		},
+	}
+
+	upgradeCmd.PersistentFlags().StringVar(&stageArg, "stage", "", "The type of the new version - either stable or beta. By default updates to stable version. Optional")
+	upgradeCmd.PersistentFlags().StringVar(&urlArg, "url", "", "Url address of the update. Optional")
+	upgradeCmd.PersistentFlags().DurationVar(&upgradeTimeoutArg, "timeout", DefaultUpgradeTimeout, "maximum time to wait for the upgrade to complete")
 
 	factoryResetCmd := &cobra.Command{
 		Use:   "factory-reset",
@@ -220,9 +491,67 @@ func NewCmd(callback callback) *cobra.Command {
 		},
 	}
 
+	// readConfig reads the desired config from the input. Secret references are resolved if resolve is
+	// set; a plan keeps them so that it does not hold the secrets.
+	readConfig := func(resolve bool) (*ShellyConfig, error) {
+
+		b, err := callback.ReadInput()
+		if err != nil {
+			return nil, err
+		}
+
+		config, err := decodeConfig(b)
+		if err != nil {
+			return nil, err
+		}
+
+		if !resolve {
+			return config, nil
+		}
+
+		err = config.ResolveSecrets(util.ResolveSecret)
+		if err != nil {
+			return nil, err
+		}
+
+		return config, nil
+	}
+
+	readPlan := func(filename string) (*ShellyPlan, error) {
+
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		var plan *ShellyPlan
+
+		var errors *multierror.Error
+
+		err = json.Unmarshal(b, &plan)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			err = yaml.Unmarshal(b, &plan)
+
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				errors = multierror.Append(errors, fmt.Errorf("Invalid format. Expect JSON or YAML"))
+				return nil, errors.ErrorOrNil()
+			}
+		}
+
+		if plan == nil {
+			return nil, fmt.Errorf("plan is required")
+		}
+
+		return plan, nil
+	}
+
 	setConfigCmd := &cobra.Command{
 		Use:   "set-config",
 		Short: "Sets config",
+		Long: "Sets the config from the input. If the config was read from this device the set is refused when the " +
+			"config revision sys.cfg_rev of the device moved since unless --force is set.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			client, err := callback.Shelly()
@@ -230,24 +559,22 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
-			b, err := callback.ReadInput()
+			config, err := readConfig(true)
 			if err != nil {
 				return err
 			}
 
-			var config *ShellyConfig
-
-			var errors *multierror.Error
-
-			err = json.Unmarshal(b, &config)
-			if err != nil {
-				errors = multierror.Append(errors, err)
-				err = yaml.Unmarshal(b, &config)
+			if !noValidateArg {
+				err = config.Validate()
+				if err != nil {
+					return err
+				}
+			}
 
+			if !forceArg {
+				err = client.CheckConfigRevision(cmd.Context(), config)
 				if err != nil {
-					errors = multierror.Append(errors, err)
-					errors = multierror.Append(errors, fmt.Errorf("Invalid format. Expect JSON or YAML"))
-					return errors.ErrorOrNil()
+					return err
 				}
 			}
 
@@ -272,6 +599,375 @@ func NewCmd(callback callback) *cobra.Command {
 	}
 
 	setConfigCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+	setConfigCmd.PersistentFlags().BoolVar(&noValidateArg, "no-validate", false, "send the config without validating it first")
+	setConfigCmd.PersistentFlags().BoolVar(&forceArg, "force", false, "set even if the device was changed since the config was read")
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Gets or sets individual config fields",
+		Long: "Gets or sets individual config fields by path. Paths are dotted such as sys.device.name and " +
+			"switch:0.auto_off_delay or JSON pointers such as /sys/device/name",
+	}
+
+	configGetCmd := &cobra.Command{
+		Use:   "get <path> ...",
+		Short: "Gets config fields",
+		Long:  "Writes the value of the field. If more than one path is given the values are written keyed by path.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 {
+				value, err := client.GetConfigValue(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				return callback.WriteObject(value)
+			}
+
+			values := make(map[string]any)
+
+			for _, path := range args {
+				value, err := client.GetConfigValue(cmd.Context(), path)
+				if err != nil {
+					return err
+				}
+				values[path] = value
+			}
+
+			return callback.WriteObject(values)
+		},
+	}
+
+	configSetCmd := &cobra.Command{
+		Use:   "set <path>=<value> ...",
+		Short: "Sets config fields",
+		Long: "Sets the fields and only sends the changed fields to the affected components. Values are converted to " +
+			"the type of the field; arrays and objects are given as JSON and null clears optional fields. The changed " +
+			"components are validated before anything is written. The set is refused if the config revision of the " +
+			"device moved since it was read, either from --cfg-rev or while the fields are set.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			var assignments []*ConfigAssignment
+
+			for _, arg := range args {
+				assignment, err := ParseConfigAssignment(arg)
+				if err != nil {
+					return err
+				}
+				assignments = append(assignments, assignment)
+			}
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("cfg-rev") {
+				err = client.CheckRevisions(cmd.Context(), &ShellyRevisions{
+					CfgRev: &cfgRevArg,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			report, setErr := client.SetConfigValues(cmd.Context(), assignments, &ApplyOptions{
+				Hostname: callback.GetHostname(),
+				Dial:     callback.ShellyFor,
+			})
+			if report == nil {
+				return setErr
+			}
+
+			moved := report.Network != nil && report.Network.Verified && report.Network.Address != report.Network.Previous
+			if moved {
+				callback.WriteStderr(fmt.Sprintf("device moved to %s (found with %s)", report.Network.Address, report.Network.Method))
+			}
+
+			if report.RestartRequired {
+				if autorebootArg && setErr == nil {
+					callback.WriteStderr("reboot is required; rebooting ...")
+					if moved {
+						err = rebootAt(cmd.Context(), callback, report.Network.Address)
+					} else {
+						err = callback.RebootDevice(cmd.Context())
+					}
+					if err != nil {
+						return err
+					}
+				} else {
+					callback.WriteStderr("reboot is required!")
+				}
+			}
+
+			err = callback.WriteObject(report)
+			if err != nil {
+				return err
+			}
+
+			return setErr
+		},
+	}
+
+	configSetCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+	configSetCmd.PersistentFlags().IntVar(&cfgRevArg, "cfg-rev", 0, "refuse unless the config revision of the device is this value, for example sys.cfg_rev read with config get")
+
+	configCmd.AddCommand(configGetCmd, configSetCmd)
+
+	renderCmd := &cobra.Command{
+		Use:   "render <base> [overlay] ...",
+		Short: "Renders a config from templates",
+		Long: "Renders the base config and the overlays as Go templates and merges the overlays onto the base in order. " +
+			"Templates can use {{ .Device.Name }}, {{ .Device.ID }}, {{ .Device.Hostname }}, {{ .DeviceInfo.MAC }} and " +
+			"{{ .Vars.<name> }}. Values are read from the device unless --offline is set; the vars file and --var " +
+			"take precedence. The output can be used as input for plan, apply and set-config.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			var sources []*TemplateSource
+
+			for _, name := range args {
+				b, err := os.ReadFile(name)
+				if err != nil {
+					return err
+				}
+				sources = append(sources, &TemplateSource{Name: name, Text: b})
+			}
+
+			var data *TemplateData
+
+			if !offlineArg {
+
+				client, err := callback.Shelly()
+				if err != nil {
+					return err
+				}
+
+				data, err = client.GetTemplateData(cmd.Context(), callback.GetHostname())
+				if err != nil {
+					return err
+				}
+			}
+
+			if varsFileArg != "" {
+
+				vars, err := readTemplateData(varsFileArg)
+				if err != nil {
+					return err
+				}
+
+				data = MergeTemplateData(data, vars)
+			}
+
+			if len(varArgs) > 0 {
+
+				vars := &TemplateData{
+					Vars: make(map[string]any),
+				}
+
+				for _, arg := range varArgs {
+					key, value, ok := strings.Cut(arg, "=")
+					if !ok {
+						return fmt.Errorf("var %s is not valid; expect <name>=<value>", arg)
+					}
+					vars.Vars[key] = value
+				}
+
+				data = MergeTemplateData(data, vars)
+			}
+
+			config, err := RenderConfig(data, sources[0], sources[1:]...)
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(config)
+		},
+	}
+
+	renderCmd.PersistentFlags().StringVar(&varsFileArg, "vars", "", "file with device and vars, in JSON or YAML")
+	renderCmd.PersistentFlags().StringArrayVar(&varArgs, "var", nil, "variable as <name>=<value>; can be repeated")
+	renderCmd.PersistentFlags().BoolVar(&offlineArg, "offline", false, "do not read values from the device")
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema <type>",
+		Short: "Writes the JSON schema of a config or params type",
+		Long: "Writes the JSON schema of the type as generated from this version of the library. Use the JSON output " +
+			"format to use the schema in an editor. Types: " + strings.Join(schema.Names(), ", "),
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: schema.Names(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			result, err := schema.Generate(args[0])
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(result)
+		},
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validates config",
+		Long: "Validates the config from the input without sending it to the device. Each invalid field is reported " +
+			"with its path, for example switch:0.in_mode",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			config, err := readConfig(true)
+			if err != nil {
+				return err
+			}
+
+			err = config.Validate()
+			if err != nil {
+				return err
+			}
+
+			callback.WriteStderr("config is valid")
+			return nil
+		},
+	}
+
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Shows what set-config would change",
+		Long: "Compares the config of the device with the desired config from the input and shows the changes per " +
+			"component and field, the changes known to require a restart and the components the device does not support. " +
+			"Secret references such as env:WIFI_PASS are kept in the plan and resolved when it is applied; other " +
+			"secrets are masked unless --show-secrets is set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			config, err := readConfig(false)
+			if err != nil {
+				return err
+			}
+
+			plan, err := client.Plan(cmd.Context(), config)
+			if err != nil {
+				return err
+			}
+
+			if jsonArg {
+				return callback.WriteObject(plan)
+			}
+
+			fmt.Println(FormatPlan(plan))
+			return nil
+		},
+	}
+
+	planCmd.PersistentFlags().BoolVar(&jsonArg, "json", false, "write the plan as an object in the selected format instead of a diff")
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Sets only the config that differs",
+		Long: "Compares the config of the device with the desired config from the input and only sets the components " +
+			"and fields that differ. Components without changes are not written. The apply is refused if the config " +
+			"revision of the device moved since the plan was made unless --force is set. Wi-Fi and Ethernet changes " +
+			"are written last and the device is located again at its previous address, a new static address, with " +
+			"mDNS or with the ARP table before the apply is reported as successful. The desired config is validated " +
+			"first unless --no-validate is set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.Shelly()
+			if err != nil {
+				return err
+			}
+
+			var plan *ShellyPlan
+			var config *ShellyConfig
+
+			if planFileArg != "" {
+
+				plan, err = readPlan(planFileArg)
+				if err != nil {
+					return err
+				}
+
+			} else {
+
+				config, err = readConfig(true)
+				if err != nil {
+					return err
+				}
+
+				if !noValidateArg {
+					err = config.Validate()
+					if err != nil {
+						return err
+					}
+				}
+
+				if !forceArg {
+					err = client.CheckConfigRevision(cmd.Context(), config)
+					if err != nil {
+						return err
+					}
+				}
+
+				plan, err = client.Plan(cmd.Context(), config)
+				if err != nil {
+					return err
+				}
+			}
+
+			report, applyErr := client.ApplyPlan(cmd.Context(), plan, config, &ApplyOptions{
+				Force:         forceArg,
+				Transactional: transactionalArg,
+				Hostname:      callback.GetHostname(),
+				Dial:          callback.ShellyFor,
+			})
+			if report == nil {
+				return applyErr
+			}
+
+			moved := report.Network != nil && report.Network.Verified && report.Network.Address != report.Network.Previous
+			if moved {
+				callback.WriteStderr(fmt.Sprintf("device moved to %s (found with %s)", report.Network.Address, report.Network.Method))
+			}
+
+			if report.RestartRequired {
+				if autorebootArg && applyErr == nil {
+					callback.WriteStderr("reboot is required; rebooting ...")
+					if moved {
+						err = rebootAt(cmd.Context(), callback, report.Network.Address)
+					} else {
+						err = callback.RebootDevice(cmd.Context())
+					}
+					if err != nil {
+						return err
+					}
+				} else {
+					callback.WriteStderr("reboot is required!")
+				}
+			}
+
+			err = callback.WriteObject(report)
+			if err != nil {
+				return err
+			}
+
+			return applyErr
+		},
+	}
+
+	applyCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
+	applyCmd.PersistentFlags().StringVar(&planFileArg, "plan", "", "apply a plan saved with 'plan --json' instead of the input config")
+	applyCmd.PersistentFlags().BoolVar(&noValidateArg, "no-validate", false, "apply the config without validating it first")
+	applyCmd.PersistentFlags().BoolVar(&forceArg, "force", false, "apply even if the device was changed since the plan was made")
+	applyCmd.PersistentFlags().BoolVar(&transactionalArg, "transactional", false, "stop at the first failure and restore the components that were already changed")
 
 	//TODO
 
@@ -289,8 +985,13 @@ func NewCmd(callback callback) *cobra.Command {
 				return fmt.Errorf("newpassword is required")
 			}
 
+			password, err := util.ResolveSecret(newPasswordArg)
+			if err != nil {
+				return err
+			}
+
 			params := &ShellyParams{
-				Ha1: &newPasswordArg,
+				Ha1: &password,
 			}
 
 			if newUserArg != "" {
@@ -307,7 +1008,8 @@ func NewCmd(callback callback) *cobra.Command {
 	}
 
 	setAuthCmd.PersistentFlags().StringVar(&newUserArg, "newuser", "", "New user. Default is "+types.ShellyUser)
-	setAuthCmd.PersistentFlags().StringVar(&newPasswordArg, "newpassword", "", "new cleartext password or SHA256(user:realm:password). If cleartext hash will be done automatically.")
+	setAuthCmd.PersistentFlags().StringVar(&newPasswordArg, "newpassword", "", "new cleartext password or SHA256(user:realm:password). If cleartext hash will be done automatically. "+
+		"Can be a reference such as env:NAME, file:path or cmd:command")
 
 	resetAuthCmd := &cobra.Command{
 		Use:   "reset-auth",
@@ -330,26 +1032,24 @@ func NewCmd(callback callback) *cobra.Command {
 		},
 	}
 
-	getAppend := func() (bool, error) {
-
-		switch strings.ToLower(appendArg) {
-
-		case "true":
-			return true, nil
-
-		case "false":
-			return false, nil
-
-		default:
-			return false, fmt.Errorf("append must be set to true or false")
-
+	readFile := func(name string) ([]byte, error) {
+		if name == "" {
+			return nil, nil
 		}
+		return os.ReadFile(name)
+	}
 
+	writeCertReport := func(report *ShellyCertReport) error {
+		for _, warning := range report.Warnings {
+			callback.WriteStderr("Warning: " + warning)
+		}
+		return callback.WriteObject(report)
 	}
 
 	putTlsClientCertCmd := &cobra.Command{
 		Use:   "put-tls-client-cert",
 		Short: "Sets TLS Client Cert",
+		Long:  "Validates the PEM input and uploads it as the TLS client cert in chunks. Use --delete to delete the existing cert.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			client, err := callback.Shelly()
@@ -357,7 +1057,15 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
-			append, err := getAppend()
+			if deleteCertArg {
+				report, err := client.DeleteTLSClientCert(cmd.Context())
+				if err != nil {
+					return err
+				}
+				return writeCertReport(report)
+			}
+
+			key, err := readFile(keyFileArg)
 			if err != nil {
 				return err
 			}
@@ -367,27 +1075,24 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
-			data := string(b)
-
-			report, err := client.PutTLSClientCert(cmd.Context(), &ShellyParams{
-				Data:   &data,
-				Append: &append,
-			})
+			report, err := client.UploadTLSClientCert(cmd.Context(), b, key)
 			if err != nil {
 				return err
 			}
 
-			return callback.WriteObject(report)
+			return writeCertReport(report)
 		},
 	}
 
-	appendMsg := "true if more data will be appended afterwards, default false"
+	deleteMsg := "delete the existing data instead of uploading"
 
-	putTlsClientCertCmd.PersistentFlags().StringVar(&appendArg, "append", "", appendMsg)
+	putTlsClientCertCmd.PersistentFlags().BoolVar(&deleteCertArg, "delete", false, deleteMsg)
+	putTlsClientCertCmd.PersistentFlags().StringVar(&keyFileArg, "key", "", "PEM key file; if set the cert must match the key")
 
 	putTlsClientKeyCmd := &cobra.Command{
 		Use:   "put-tls-client-key",
 		Short: "Sets TLS Client Key",
+		Long:  "Validates the PEM input and uploads it as the TLS client key in chunks. Use --delete to delete the existing key.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			client, err := callback.Shelly()
@@ -395,7 +1100,15 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
-			append, err := getAppend()
+			if deleteKeyArg {
+				report, err := client.DeleteTLSClientKey(cmd.Context())
+				if err != nil {
+					return err
+				}
+				return writeCertReport(report)
+			}
+
+			cert, err := readFile(certFileArg)
 			if err != nil {
 				return err
 			}
@@ -405,25 +1118,22 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
-			data := string(b)
-
-			report, err := client.PutTLSClientKey(cmd.Context(), &ShellyParams{
-				Data:   &data,
-				Append: &append,
-			})
+			report, err := client.UploadTLSClientKey(cmd.Context(), b, cert)
 			if err != nil {
 				return err
 			}
 
-			return callback.WriteObject(report)
+			return writeCertReport(report)
 		},
 	}
 
-	putTlsClientKeyCmd.PersistentFlags().StringVar(&appendArg, "append", "", appendMsg)
+	putTlsClientKeyCmd.PersistentFlags().BoolVar(&deleteKeyArg, "delete", false, deleteMsg)
+	putTlsClientKeyCmd.PersistentFlags().StringVar(&certFileArg, "cert", "", "PEM cert file; if set the key must match the cert")
 
 	putUserCACmd := &cobra.Command{
 		Use:   "put-user-ca",
 		Short: "Sets Users CA",
+		Long:  "Validates the PEM input and uploads it as the user CA in chunks. Use --delete to delete the existing CA.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			client, err := callback.Shelly()
@@ -431,9 +1141,12 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
-			append, err := getAppend()
-			if err != nil {
-				return err
+			if deleteCAArg {
+				report, err := client.DeleteUserCA(cmd.Context())
+				if err != nil {
+					return err
+				}
+				return writeCertReport(report)
 			}
 
 			b, err := callback.ReadInput()
@@ -441,25 +1154,98 @@ func NewCmd(callback callback) *cobra.Command {
 				return err
 			}
 
-			data := string(b)
-
-			report, err := client.PutUserCA(cmd.Context(), &ShellyParams{
-				Data:   &data,
-				Append: &append,
-			})
+			report, err := client.UploadUserCA(cmd.Context(), b)
 			if err != nil {
 				return err
 			}
 
-			return callback.WriteObject(report)
+			return writeCertReport(report)
 		},
 	}
 
-	putUserCACmd.PersistentFlags().StringVar(&appendArg, "append", "", appendMsg)
+	putUserCACmd.PersistentFlags().BoolVar(&deleteCAArg, "delete", false, deleteMsg)
 
+	rootCmd.AddCommand(getComponentsCmd, listProfilesCmd, setProfileCmd, listTimezonesCmd, detectLocationCmd)
 	rootCmd.AddCommand(getConfigCmd, getStatusCmd, getInfoCmd, getMethodsCmd,
-		getUpdatesCmd, getExampleConfigCmd, rebootCmd, updateCmd,
-		factoryResetCmd, resetWifiConfigCmd, setConfigCmd, setAuthCmd, resetAuthCmd,
+		getUpdatesCmd, getExampleConfigCmd, rebootCmd, updateCmd, upgradeCmd,
+		factoryResetCmd, resetWifiConfigCmd, setConfigCmd, configCmd, validateCmd, renderCmd, schemaCmd, planCmd, applyCmd, setAuthCmd, resetAuthCmd,
 		putTlsClientCertCmd, putTlsClientKeyCmd, putUserCACmd)
 	return rootCmd
 }
+
+// rebootAt reboots the device at the address, used when the device moved during an apply
+func rebootAt(ctx context.Context, callback callback, address string) error {
+
+	client, closer, err := callback.ShellyFor(address)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	return client.Reboot(ctx)
+}
+
+// readTemplateData reads a vars file with the device and vars keys in JSON or YAML
+func readTemplateData(filename string) (*TemplateData, error) {
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var data *TemplateData
+
+	var errors *multierror.Error
+
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+		err = yaml.Unmarshal(b, &data)
+
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			errors = multierror.Append(errors, fmt.Errorf("Invalid format. Expect JSON or YAML"))
+			return nil, errors.ErrorOrNil()
+		}
+	}
+
+	if data == nil {
+		return &TemplateData{}, nil
+	}
+
+	// Nested vars decoded from YAML have interface keys that templates can not access
+	for key, value := range data.Vars {
+		data.Vars[key], err = types.YAMLToJSON(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// decodeConfig decodes a config from JSON or YAML
+func decodeConfig(b []byte) (*ShellyConfig, error) {
+
+	var config *ShellyConfig
+
+	var errors *multierror.Error
+
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+		err = yaml.Unmarshal(b, &config)
+
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			errors = multierror.Append(errors, fmt.Errorf("Invalid format. Expect JSON or YAML"))
+			return nil, errors.ErrorOrNil()
+		}
+	}
+
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+
+	return config, nil
+}
@@ -1,6 +1,39 @@
 package shelly
 
+import "time"
+
 const (
 	Component = "Shelly"
 	User      = "admin"
 )
+
+var (
+	// DefaultRebootTimeout is the default maximum time to wait for a device to come back after a reboot
+	DefaultRebootTimeout = time.Duration(2) * time.Minute
+
+	// DefaultUpgradeTimeout is the default maximum time allowed for a firmware upgrade
+	DefaultUpgradeTimeout = time.Duration(10) * time.Minute
+
+	// DefaultDownloadTimeout is the default maximum time allowed for a device to download a firmware file
+	DefaultDownloadTimeout = time.Duration(5) * time.Minute
+
+	// RebootPollInterval is the interval used to poll the device while waiting for it to reboot
+	RebootPollInterval = time.Duration(2) * time.Second
+
+	// CertChunkSize is the maximum number of bytes sent in a single PutUserCA, PutTLSClientCert or
+	// PutTLSClientKey request. Larger data is sent in chunks with append set on all but the last chunk.
+	CertChunkSize = 1024
+
+	// CertExpiryWarning is the duration before expiry at which a warning is added to the upload report
+	CertExpiryWarning = time.Duration(30*24) * time.Hour
+
+	// DefaultReconnectTimeout is the default maximum time to locate a device after its network config
+	// was changed
+	DefaultReconnectTimeout = time.Duration(2) * time.Minute
+
+	// MDNSTimeout is the time spent on a single mDNS query while locating a device
+	MDNSTimeout = time.Duration(3) * time.Second
+
+	// VerifyTimeout is the maximum time to wait for a device to answer at a candidate address
+	VerifyTimeout = time.Duration(5) * time.Second
+)
@@ -0,0 +1,263 @@
+package shelly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-manager/mdns"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+// networkTypes are the component types that may change the address of the device
+var networkTypes = map[string]bool{
+	"wifi": true,
+	"eth":  true,
+}
+
+// Dialer returns a client for the device at hostname and a function that closes it
+type Dialer func(hostname string) (*Client, func(), error)
+
+// Methods used to find the device after a network change
+const (
+	LocateMethodPrevious = "previous"
+	LocateMethodStatic   = "static"
+	LocateMethodMDNS     = "mdns"
+	LocateMethodARP      = "arp"
+)
+
+// splitNetworkComponents returns the local and the network components keeping their order
+func splitNetworkComponents(components []*ShellyPlanComponent) ([]*ShellyPlanComponent, []*ShellyPlanComponent) {
+
+	var local, network []*ShellyPlanComponent
+
+	for _, component := range components {
+		if IsNetworkComponent(component.Key) {
+			network = append(network, component)
+			continue
+		}
+		local = append(local, component)
+	}
+
+	return local, network
+}
+
+// IsNetworkComponent returns true if writing the component may change the address of the device
+func IsNetworkComponent(key string) bool {
+	componentKey, err := types.ParseComponentKey(key)
+	if err != nil {
+		return false
+	}
+	return networkTypes[componentKey.Type]
+}
+
+// predictAddresses returns the static addresses set by the network components
+func predictAddresses(components []*ShellyPlanComponent) []string {
+
+	var addresses []string
+
+	for _, component := range components {
+		for _, change := range component.Changes {
+
+			path := strings.Split(change.Path, ".")
+			if path[len(path)-1] != "ip" {
+				continue
+			}
+
+			if ip, ok := change.To.(string); ok && ip != "" {
+				addresses = append(addresses, ip)
+			}
+		}
+	}
+
+	return addresses
+}
+
+// isConnectionError returns true if the error is a transport error. This is the case when the
+// connection was lost or timed out while the device applied a network change.
+func isConnectionError(err error) bool {
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var closeErr *websocket.CloseError
+	return errors.As(err, &closeErr)
+}
+
+// verifyNetwork confirms the device is reachable after its network config was written
+func (t *Client) verifyNetwork(ctx context.Context, report *ShellyApplyReport, options *ApplyOptions) error {
+
+	unconfirmed := false
+	for _, changed := range report.Changed {
+		unconfirmed = unconfirmed || changed.Unconfirmed
+	}
+
+	if options.Dial == nil {
+
+		if unconfirmed {
+			return fmt.Errorf("connection lost after network change; reachability of the device can not be verified")
+		}
+
+		verifyCtx, cancel := context.WithTimeout(ctx, VerifyTimeout)
+		defer cancel()
+
+		info, err := t.GetDeviceInfo(verifyCtx)
+		if err != nil {
+			return fmt.Errorf("device is not reachable after network change: %w", err)
+		}
+
+		if info.ID != report.Network.DeviceID {
+			return fmt.Errorf("device id changed from %s to %s", report.Network.DeviceID, info.ID)
+		}
+
+		report.Network.Address = report.Network.Previous
+		report.Network.Method = LocateMethodPrevious
+		report.Network.Verified = true
+		return nil
+	}
+
+	timeout := options.ReconnectTimeout
+	if timeout <= 0 {
+		timeout = DefaultReconnectTimeout
+	}
+
+	locateCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := Locate(locateCtx, report.Network, options.Dial)
+	if err != nil {
+		return err
+	}
+
+	for _, changed := range report.Changed {
+		changed.Unconfirmed = false
+	}
+
+	return nil
+}
+
+// Locate finds the device after a network change. The previous address is tried first, then the
+// predicted static addresses, then mDNS and finally the ARP table of the host. An address is only
+// accepted if the device reports the same id. Address, Method and Verified of the report are set on
+// success. Locate polls until the context is done.
+func Locate(ctx context.Context, network *ShellyNetworkReport, dial Dialer) error {
+
+	if network == nil {
+		return fmt.Errorf("network report is required")
+	}
+
+	if dial == nil {
+		return fmt.Errorf("dialer is required")
+	}
+
+	for {
+
+		for _, candidate := range locateCandidates(network) {
+
+			if verifyAddress(ctx, network.DeviceID, candidate.address, dial) {
+				network.Address = candidate.address
+				network.Method = candidate.method
+				network.Verified = true
+				return nil
+			}
+		}
+
+		select {
+
+		case <-ctx.Done():
+			return fmt.Errorf("timeout locating device %s after network change", network.DeviceID)
+
+		case <-time.After(RebootPollInterval):
+
+		}
+	}
+}
+
+type locateCandidate struct {
+	address string
+	method  string
+}
+
+func locateCandidates(network *ShellyNetworkReport) []*locateCandidate {
+
+	var candidates []*locateCandidate
+	seen := make(map[string]bool)
+
+	add := func(address, method string) {
+		if address == "" || seen[address] {
+			return
+		}
+		seen[address] = true
+		candidates = append(candidates, &locateCandidate{
+			address: address,
+			method:  method,
+		})
+	}
+
+	add(network.Previous, LocateMethodPrevious)
+
+	for _, address := range network.Predicted {
+		add(address, LocateMethodStatic)
+	}
+
+	if network.DeviceID != "" {
+		entry, err := mdns.LookupDevice(network.DeviceID, MDNSTimeout)
+		if err != nil {
+			zap.L().Debug(fmt.Sprintf("mdns lookup of %s failed: %v", network.DeviceID, err))
+		} else {
+			add(entry.IP.String(), LocateMethodMDNS)
+		}
+	}
+
+	if network.MAC != "" {
+		ip, err := util.LookupARP(network.MAC)
+		if err != nil {
+			zap.L().Debug(fmt.Sprintf("arp lookup of %s failed: %v", network.MAC, err))
+		} else {
+			add(ip.String(), LocateMethodARP)
+		}
+	}
+
+	return candidates
+}
+
+// verifyAddress returns true if the device at address reports the device id
+func verifyAddress(ctx context.Context, deviceID, address string, dial Dialer) bool {
+
+	client, closer, err := dial(address)
+	if err != nil {
+		zap.L().Debug(fmt.Sprintf("unable to dial %s: %v", address, err))
+		return false
+	}
+	defer closer()
+
+	verifyCtx, cancel := context.WithTimeout(ctx, VerifyTimeout)
+	defer cancel()
+
+	info, err := client.GetDeviceInfo(verifyCtx)
+	if err != nil {
+		zap.L().Debug(fmt.Sprintf("device not responding at %s: %v", address, err))
+		return false
+	}
+
+	if info.ID != deviceID {
+		zap.L().Debug(fmt.Sprintf("device at %s is %s, expected %s", address, info.ID, deviceID))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,477 @@
+package shelly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+// ConfigAssignment is a single field assignment such as switch:0.auto_off_delay=30
+type ConfigAssignment struct {
+	// Key of the component, for example sys or switch:0
+	Key string
+	// Path of the field within the component, for example device.name
+	Path string
+	// Value converted to the type of the field
+	Value any
+}
+
+// ParseConfigPath splits a dotted path such as sys.device.name or a JSON pointer such as /sys/device/name
+// into the component key and the dotted path of the field within the component
+func ParseConfigPath(path string) (string, string, error) {
+
+	var segments []string
+
+	if strings.HasPrefix(path, "/") {
+		for _, segment := range strings.Split(path[1:], "/") {
+			segment = strings.ReplaceAll(segment, "~1", "/")
+			segment = strings.ReplaceAll(segment, "~0", "~")
+			segments = append(segments, segment)
+		}
+	} else {
+		segments = strings.Split(path, ".")
+	}
+
+	for _, segment := range segments {
+		if segment == "" {
+			return "", "", fmt.Errorf("path %s is not valid", path)
+		}
+	}
+
+	return segments[0], strings.Join(segments[1:], "."), nil
+}
+
+// ParseConfigAssignment parses <path>=<value>. The value is converted to the type of the field in
+// ShellyConfig. Fields that are not modeled accept JSON or are taken as string. null clears pointer,
+// slice and map fields; arrays and objects are given as JSON. The value of a secret field such as
+// wifi.sta.pass may be a secret reference such as env:WIFI_PASS; a masked secret is refused.
+func ParseConfigAssignment(s string) (*ConfigAssignment, error) {
+
+	path, raw, ok := strings.Cut(s, "=")
+	if !ok {
+		return nil, fmt.Errorf("assignment %s is not valid; expect <path>=<value>", s)
+	}
+
+	key, fieldPath, err := ParseConfigPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fieldPath == "" {
+		return nil, fmt.Errorf("%s :: path of a field within the component is required", path)
+	}
+
+	// A resolved secret is taken as is so that it is not read as JSON
+	if types.IsSecretPath(fieldPath) && (raw == util.SecretMask || util.IsSecretReference(raw)) {
+
+		secret, err := util.ResolveSecret(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s :: %w", path, err)
+		}
+
+		return &ConfigAssignment{
+			Key:   key,
+			Path:  fieldPath,
+			Value: secret,
+		}, nil
+	}
+
+	typ := configFieldType(key, fieldPath)
+
+	value, err := parseConfigValue(typ, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", path, err)
+	}
+
+	// Numbers are compared with the config of the device which is decoded from JSON
+	value, err = jsonValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s :: %w", path, err)
+	}
+
+	return &ConfigAssignment{
+		Key:   key,
+		Path:  fieldPath,
+		Value: value,
+	}, nil
+}
+
+// configFieldType returns the type of the field or nil if the field is not modeled
+func configFieldType(key, path string) reflect.Type {
+
+	typ := componentConfigType(key)
+	if typ == nil {
+		return nil
+	}
+
+	segments := strings.Split(path, ".")
+
+	for len(segments) > 0 {
+
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		if typ.Kind() != reflect.Struct {
+			return nil
+		}
+
+		field, used := matchJSONField(typ, segments)
+		if field == nil {
+			return nil
+		}
+
+		typ = field.Type
+		segments = segments[used:]
+	}
+
+	return typ
+}
+
+// componentConfigType returns the config type of the component or nil if it is not modeled
+func componentConfigType(key string) reflect.Type {
+
+	componentKey, err := types.ParseComponentKey(key)
+	if err != nil {
+		return nil
+	}
+
+	typ := reflect.TypeOf(ShellyConfig{})
+
+	for i := 0; i < typ.NumField(); i++ {
+
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		if componentKey.HasID {
+			// Keyed component maps such as Switch map[int]*SwitchConfig
+			if name == "-" && field.Type.Kind() == reflect.Map && field.Type.Key().Kind() == reflect.Int &&
+				strings.ToLower(field.Name) == componentKey.Type {
+				return field.Type.Elem()
+			}
+			continue
+		}
+
+		if name == componentKey.Type {
+			return field.Type
+		}
+	}
+
+	return nil
+}
+
+// matchJSONField returns the field of the struct named by the leading segments and the number of segments
+// used. Field names that contain dots such as night_mode.brightness are matched over several segments.
+func matchJSONField(typ reflect.Type, segments []string) (*reflect.StructField, int) {
+
+	for used := len(segments); used > 0; used-- {
+
+		name := strings.Join(segments[:used], ".")
+
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if jsonName == name {
+				return &field, used
+			}
+		}
+	}
+
+	return nil, 0
+}
+
+func parseConfigValue(typ reflect.Type, raw string) (any, error) {
+
+	if typ == nil {
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value, nil
+		}
+		return raw, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		if raw == "null" {
+			return nil, nil
+		}
+	}
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+
+	case reflect.String:
+		var value string
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value, nil
+		}
+		return raw, nil
+
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q", raw)
+		}
+		return value, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", raw)
+		}
+		return value, nil
+
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", raw)
+		}
+		return value, nil
+	}
+
+	// Arrays and objects are given as JSON and checked against the type
+	err := json.Unmarshal([]byte(raw), reflect.New(typ).Interface())
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q: %w", raw, err)
+	}
+
+	var value any
+	err = json.Unmarshal([]byte(raw), &value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// jsonValue returns the value as it is decoded from its JSON encoding
+func jsonValue(value any) (any, error) {
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result any
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetConfigValue returns the value at the path of the config, for example sys.device.name or
+// /switch:0/auto_off_delay
+func (t *Client) GetConfigValue(ctx context.Context, path string) (any, error) {
+
+	key, fieldPath, err := ParseConfigPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := t.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := toGeneric(config)
+	if err != nil {
+		return nil, err
+	}
+
+	component, ok := configMap[key]
+	if !ok {
+		return nil, fmt.Errorf("component %s not found", key)
+	}
+
+	if !hasPath(component, fieldPath) {
+		return nil, fmt.Errorf("field %s not found", path)
+	}
+
+	return getPath(component, fieldPath), nil
+}
+
+// SetConfigValues sets the fields of the assignments. Only the components with changed fields are written
+// and only the changed fields are sent. The changed components are validated before anything is written.
+// The plan is applied with ApplyPlan; network components are written last. The config revision is read
+// before the config, so the set is refused if the device was changed meanwhile unless options.Force is set.
+func (t *Client) SetConfigValues(ctx context.Context, assignments []*ConfigAssignment, options *ApplyOptions) (*ShellyApplyReport, error) {
+
+	revisions, err := t.GetRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := t.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := NewAssignmentPlan(current, assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Revisions = &ShellyRevisions{
+		CfgRev: revisions.CfgRev,
+	}
+
+	return t.ApplyPlan(ctx, plan, nil, options)
+}
+
+// NewAssignmentPlan returns the plan that applies the assignments to the current config. An error is
+// returned if a component does not exist, a field is read only or a changed component is not valid.
+func NewAssignmentPlan(current *ShellyConfig, assignments []*ConfigAssignment) (*ShellyPlan, error) {
+
+	currentMap, err := toGeneric(current)
+	if err != nil {
+		return nil, err
+	}
+
+	desiredMap, err := toGeneric(current)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ShellyPlan{}
+	components := make(map[string]*ShellyPlanComponent)
+
+	var errors *multierror.Error
+
+	// The last assignment of a path wins
+	last := make(map[string]int)
+	for i, assignment := range assignments {
+		last[joinPath(assignment.Key, assignment.Path)] = i
+	}
+
+	for i, assignment := range assignments {
+
+		path := joinPath(assignment.Key, assignment.Path)
+
+		if last[path] != i {
+			continue
+		}
+
+		if assignment.Key == authKey {
+			errors = multierror.Append(errors, fmt.Errorf("%s :: auth can not be set by path; use set-auth", path))
+			continue
+		}
+
+		from, ok := currentMap[assignment.Key]
+		if !ok {
+			errors = multierror.Append(errors, fmt.Errorf("%s :: component %s not found", path, assignment.Key))
+			continue
+		}
+
+		componentType := assignment.Key
+		if componentKey, err := types.ParseComponentKey(assignment.Key); err == nil {
+			componentType = componentKey.Type
+		}
+
+		if matchField(readOnlyFields, componentType, assignment.Path) {
+			errors = multierror.Append(errors, fmt.Errorf("%s :: field is read only", path))
+			continue
+		}
+
+		desiredMap[assignment.Key] = setPath(desiredMap[assignment.Key], assignment.Path, assignment.Value)
+
+		fromValue := getPath(from, assignment.Path)
+		if reflect.DeepEqual(fromValue, assignment.Value) {
+			continue
+		}
+
+		component, ok := components[assignment.Key]
+		if !ok {
+			component = &ShellyPlanComponent{Key: assignment.Key}
+			components[assignment.Key] = component
+			plan.Components = append(plan.Components, component)
+		}
+
+		change := &ShellyPlanChange{
+			Path:            assignment.Path,
+			From:            fromValue,
+			To:              assignment.Value,
+			RestartRequired: matchField(restartFields, componentType, assignment.Path),
+		}
+
+		component.Changes = append(component.Changes, change)
+		component.RestartRequired = component.RestartRequired || change.RestartRequired
+		plan.Changes++
+		plan.RestartRequired = plan.RestartRequired || change.RestartRequired
+	}
+
+	if err := errors.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	for key := range currentMap {
+		if _, ok := components[key]; !ok && key != authKey {
+			plan.Unchanged = append(plan.Unchanged, key)
+		}
+	}
+	sort.Strings(plan.Unchanged)
+
+	err = validateComponents(desiredMap, components)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// validateComponents validates the changed components of the desired config
+func validateComponents(desiredMap map[string]any, components map[string]*ShellyPlanComponent) error {
+
+	changed := make(map[string]any)
+	for key := range components {
+		changed[key] = desiredMap[key]
+	}
+
+	b, err := json.Marshal(changed)
+	if err != nil {
+		return err
+	}
+
+	var desired *ShellyConfig
+	err = json.Unmarshal(b, &desired)
+	if err != nil {
+		return err
+	}
+
+	return desired.Validate()
+}
+
+// hasPath returns true if the dot separated path exists within root
+func hasPath(root any, path string) bool {
+
+	if path == "" {
+		return true
+	}
+
+	first, rest, _ := strings.Cut(path, ".")
+
+	m, ok := root.(map[string]any)
+	if !ok {
+		return false
+	}
+
+	value, ok := m[first]
+	if !ok {
+		return false
+	}
+
+	return hasPath(value, rest)
+}
@@ -0,0 +1,35 @@
+package shelly
+
+import (
+	"testing"
+
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+func TestParseConfigAssignmentSecret(t *testing.T) {
+
+	t.Setenv("TEST_WIFI_PASS", "null")
+
+	assignment, err := ParseConfigAssignment("wifi.sta.pass=env:TEST_WIFI_PASS")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assignment.Value != "null" {
+		t.Errorf("expected the resolved secret, got %v", assignment.Value)
+	}
+
+	_, err = ParseConfigAssignment("mqtt.pass=" + util.SecretMask)
+	if err == nil {
+		t.Errorf("a masked secret is accepted")
+	}
+
+	assignment, err = ParseConfigAssignment("switch:0.name=env:NOT_A_SECRET")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assignment.Value != "env:NOT_A_SECRET" {
+		t.Errorf("a field that is not a secret was resolved: %v", assignment.Value)
+	}
+}
@@ -0,0 +1,303 @@
+package shelly
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+const authKey = "auth"
+
+// readOnlyFields are fields returned by GetConfig that SetConfig ignores. The key is the component type
+// followed by the path of the field; an entry matches the field and everything below it.
+var readOnlyFields = map[string]bool{
+	"sys.device.mac":   true,
+	"sys.device.fw_id": true,
+	"sys.cfg_rev":      true,
+	"light.id":         true,
+	"input.id":         true,
+	"switch.id":        true,
+}
+
+// restartFields are fields that are known to require a restart when changed. An entry with only the
+// component type covers the whole component. This is a hint for the plan; the report returned by
+// SetConfig is authoritative.
+var restartFields = map[string]bool{
+	"ble.enable":  true,
+	"mqtt":        true,
+	"eth":         true,
+	"ws":          true,
+	"sys.rpc_udp": true,
+	"wifi.ap":     true,
+}
+
+// Plan fetches the config of the device and returns the changes SetConfig would make to reach the
+// desired config
+func (t *Client) Plan(ctx context.Context, desired *ShellyConfig) (*ShellyPlan, error) {
+
+	revisions, err := t.GetRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := t.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := NewPlan(current, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the config revision is relevant to a config plan
+	plan.Revisions = &ShellyRevisions{
+		CfgRev: revisions.CfgRev,
+	}
+
+	return plan, nil
+}
+
+// GetRevisions returns the current config, webhook and schedule revisions of the device
+func (t *Client) GetRevisions(ctx context.Context) (*ShellyRevisions, error) {
+
+	status, err := t.System().GetStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgRev := int(status.CfgRev)
+	webhookRev := int(status.WebhookRev)
+	scheduleRev := int(status.ScheduleRev)
+
+	return &ShellyRevisions{
+		CfgRev:      &cfgRev,
+		WebhookRev:  &webhookRev,
+		ScheduleRev: &scheduleRev,
+	}, nil
+}
+
+// CheckRevisions returns a *ConflictError if a revision that is set in expected differs from the
+// current revision of the device
+func (t *Client) CheckRevisions(ctx context.Context, expected *ShellyRevisions) error {
+
+	if expected == nil {
+		return nil
+	}
+
+	current, err := t.GetRevisions(ctx)
+	if err != nil {
+		return err
+	}
+
+	checks := []struct {
+		name     string
+		expected *int
+		current  *int
+	}{
+		{name: "cfg_rev", expected: expected.CfgRev, current: current.CfgRev},
+		{name: "webhook_rev", expected: expected.WebhookRev, current: current.WebhookRev},
+		{name: "schedule_rev", expected: expected.ScheduleRev, current: current.ScheduleRev},
+	}
+
+	for _, check := range checks {
+		if check.expected != nil && *check.expected != *check.current {
+			return &ConflictError{
+				Name:    check.name,
+				Planned: *check.expected,
+				Current: *check.current,
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckConfigRevision returns a *ConflictError if the config was read from this device and the config
+// revision of the device moved since. The revision is sys.cfg_rev of the config. Configs without it or
+// read from another device, for example a template or a backup of another device, are not checked.
+func (t *Client) CheckConfigRevision(ctx context.Context, config *ShellyConfig) error {
+
+	if config == nil || config.System == nil || config.System.CfgRev == nil {
+		return nil
+	}
+
+	if config.System.Device == nil || config.System.Device.MAC == "" {
+		return nil
+	}
+
+	info, err := t.GetDeviceInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(info.MAC, config.System.Device.MAC) {
+		return nil
+	}
+
+	return t.CheckRevisions(ctx, &ShellyRevisions{
+		CfgRev: config.System.CfgRev,
+	})
+}
+
+// NewPlan returns the changes needed to go from the current to the desired config. Only the fields of
+// the desired config are compared; for a decoded config these are the keys of the input, so fields that
+// are absent are left as they are while a field that is explicitly null is planned as a change that
+// clears it. A config that was not decoded has all the fields of its types; its null fields are left
+// as they are. Components the device does not have are listed as unsupported.
+func NewPlan(current, desired *ShellyConfig) (*ShellyPlan, error) {
+
+	currentMap, err := toGeneric(current)
+	if err != nil {
+		return nil, err
+	}
+
+	desiredMap, err := toGeneric(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only a decoded config tells an absent field apart from an explicit null
+	clearNull := desired != nil && desired.Keys != nil
+
+	plan := &ShellyPlan{}
+
+	var keys []string
+	for key := range desiredMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+
+		to := desiredMap[key]
+		if to == nil {
+			continue
+		}
+
+		if key == authKey {
+			plan.Auth = true
+			continue
+		}
+
+		from, ok := currentMap[key]
+		if !ok {
+			plan.Unsupported = append(plan.Unsupported, key)
+			continue
+		}
+
+		componentType := key
+		if componentKey, err := types.ParseComponentKey(key); err == nil {
+			componentType = componentKey.Type
+		}
+
+		component := &ShellyPlanComponent{Key: key}
+		diffValues(componentType, "", from, to, clearNull, component)
+
+		if len(component.Changes) == 0 {
+			plan.Unchanged = append(plan.Unchanged, key)
+			continue
+		}
+
+		plan.Components = append(plan.Components, component)
+		plan.Changes = plan.Changes + len(component.Changes)
+		plan.RestartRequired = plan.RestartRequired || component.RestartRequired
+	}
+
+	return plan, nil
+}
+
+// diffValues appends the changes from from to to. A field that is absent from to is not compared; a
+// field that is null in to is a change if clearNull is set and the field is set in from.
+func diffValues(componentType, path string, from, to any, clearNull bool, component *ShellyPlanComponent) {
+
+	if (to == nil && !clearNull) || matchField(readOnlyFields, componentType, path) {
+		return
+	}
+
+	if toMap, ok := to.(map[string]any); ok {
+
+		fromMap, _ := from.(map[string]any)
+
+		var keys []string
+		for key := range toMap {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			diffValues(componentType, joinPath(path, key), fromMap[key], toMap[key], clearNull, component)
+		}
+
+		return
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+
+	change := &ShellyPlanChange{
+		Path:            path,
+		From:            from,
+		To:              to,
+		RestartRequired: matchField(restartFields, componentType, path),
+	}
+
+	component.Changes = append(component.Changes, change)
+	component.RestartRequired = component.RestartRequired || change.RestartRequired
+}
+
+// matchField returns true if the table has the component type or the component type followed by the
+// path or any parent of the path
+func matchField(table map[string]bool, componentType, path string) bool {
+
+	if table[componentType] {
+		return true
+	}
+
+	key := componentType
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+		key = key + "." + part
+		if table[key] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// toGeneric returns the JSON encoding of config decoded as a map
+func toGeneric(config *ShellyConfig) (map[string]any, error) {
+
+	result := make(map[string]any)
+
+	if config == nil {
+		return result, nil
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package shelly
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+var (
+	planChangedColor     = color.New(color.FgYellow, color.Bold)
+	planFromColor        = color.New(color.FgRed)
+	planToColor          = color.New(color.FgGreen)
+	planUnchangedColor   = color.New(color.FgHiBlack)
+	planUnsupportedColor = color.New(color.FgMagenta)
+	planRestartColor     = color.New(color.FgRed, color.Bold)
+)
+
+// FormatPlan returns the plan as a colored human readable diff. Colors are disabled automatically when
+// the output is not a terminal.
+func FormatPlan(plan *ShellyPlan) string {
+
+	var b strings.Builder
+
+	for _, component := range plan.Components {
+
+		line := planChangedColor.Sprintf("~ %s", component.Key)
+		if component.RestartRequired {
+			line = line + " " + planRestartColor.Sprint("(restart required)")
+		}
+		b.WriteString(line + "\n")
+
+		for _, change := range component.Changes {
+
+			path := change.Path
+			if path == "" {
+				path = "(value)"
+			}
+
+			from, to := formatPlanValue(change.From), formatPlanValue(change.To)
+			if types.IsSecretPath(change.Path) {
+				from, to = formatPlanValue(types.MaskSecretValue(change.From)), formatPlanValue(types.MaskSecretValue(change.To))
+			}
+
+			line := fmt.Sprintf("    %s: %s -> %s", path, planFromColor.Sprint(from), planToColor.Sprint(to))
+
+			if change.RestartRequired {
+				line = line + " " + planRestartColor.Sprint("!")
+			}
+
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if plan.Auth {
+		b.WriteString(planChangedColor.Sprint("~ auth") + " (always set; the current value can not be read)\n")
+	}
+
+	for _, key := range plan.Unchanged {
+		b.WriteString(planUnchangedColor.Sprintf("= %s", key) + "\n")
+	}
+
+	for _, key := range plan.Unsupported {
+		b.WriteString(planUnsupportedColor.Sprintf("! %s is not supported by the device", key) + "\n")
+	}
+
+	summary := fmt.Sprintf("Plan: %d changes in %d components", plan.Changes, len(plan.Components))
+	if len(plan.Unsupported) > 0 {
+		summary = summary + fmt.Sprintf(", %d unsupported", len(plan.Unsupported))
+	}
+	if plan.RestartRequired {
+		summary = summary + "; " + planRestartColor.Sprint("restart required")
+	}
+	b.WriteString(summary)
+
+	return b.String()
+}
+
+func formatPlanValue(value any) string {
+
+	if value == nil {
+		return "null"
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,151 @@
+package shelly
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// currentConfigJSON is the config of a device as returned by Shelly.GetConfig
+const currentConfigJSON = `{
+  "switch:0": {"id": 0, "name": null, "in_mode": "follow", "initial_state": "match_input", "auto_on": false, "auto_on_delay": 60, "auto_off": false, "auto_off_delay": 60, "power_limit": 4480},
+  "sys": {"device": {"name": null, "mac": "A8032AB636EC", "fw_id": "20230503-101129/0.14.4-g7b9c8a4", "discoverable": true}, "sntp": {"server": "time.google.com"}, "cfg_rev": 10}
+}`
+
+// partialConfigYAML is a desired config that only sets two fields
+const partialConfigYAML = `
+switch:0:
+  auto_off: true
+sys:
+  device:
+    name: kitchen
+`
+
+func decodeTestConfigs(t *testing.T) (*ShellyConfig, *ShellyConfig) {
+
+	t.Helper()
+
+	current := &ShellyConfig{}
+
+	err := json.Unmarshal([]byte(currentConfigJSON), current)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	desired := &ShellyConfig{}
+
+	err = yaml.Unmarshal([]byte(partialConfigYAML), desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return current, desired
+}
+
+func TestNewPlanPartialConfig(t *testing.T) {
+
+	current, desired := decodeTestConfigs(t)
+
+	plan, err := NewPlan(current, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(map[string]any)
+	for _, component := range plan.Components {
+		for _, change := range component.Changes {
+			changes[component.Key+"."+change.Path] = change.To
+		}
+	}
+
+	expected := map[string]any{
+		"switch:0.auto_off": true,
+		"sys.device.name":   "kitchen",
+	}
+
+	if len(changes) != len(expected) || plan.Changes != len(expected) {
+		t.Fatalf("expected changes %v, got %v", expected, changes)
+	}
+
+	for path, to := range expected {
+		if changes[path] != to {
+			t.Errorf("%s: expected %v, got %v", path, to, changes[path])
+		}
+	}
+}
+
+func TestNewPlanExplicitNull(t *testing.T) {
+
+	current, _ := decodeTestConfigs(t)
+
+	name := "pump"
+	current.Switch[0].Name = &name
+
+	// name is cleared; power_limit is null on neither side and sys.device.name is already null
+	desired := &ShellyConfig{}
+
+	err := yaml.Unmarshal([]byte(`
+switch:0:
+  name: null
+  auto_off: true
+sys:
+  device:
+    name: null
+`), desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := NewPlan(current, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.Changes != 2 || len(plan.Components) != 1 {
+		t.Fatalf("expected two changes of switch:0, got %+v", plan)
+	}
+
+	changes := make(map[string]*ShellyPlanChange)
+	for _, change := range plan.Components[0].Changes {
+		changes[change.Path] = change
+	}
+
+	change := changes["name"]
+	if change == nil || change.From != "pump" || change.To != nil {
+		t.Errorf("expected name to be cleared, got %+v", change)
+	}
+
+	handler := &recordingHandler{
+		params: make(map[string]json.RawMessage),
+	}
+
+	_, err = New(&recordingContract{handler: handler}).ApplyPlan(context.Background(), plan, desired, &ApplyOptions{Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"id":0,"config":{"auto_off":true,"name":null}}`
+	if string(handler.params["Switch.SetConfig"]) != expected {
+		t.Errorf("expected params %s, got %s", expected, string(handler.params["Switch.SetConfig"]))
+	}
+
+	// A config that was not decoded has all its fields; its null fields are not cleared
+	desired = &ShellyConfig{
+		Switch: map[int]*SwitchConfig{0: {AutoOff: true, InMode: "follow", InitialState: "match_input", AutoOnDelay: 60, AutoOffDelay: 60}},
+	}
+
+	plan, err = NewPlan(current, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, component := range plan.Components {
+		for _, change := range component.Changes {
+			if change.To == nil {
+				t.Errorf("%s.%s is cleared by a config that was not decoded", component.Key, change.Path)
+			}
+		}
+	}
+}
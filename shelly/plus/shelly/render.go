@@ -0,0 +1,230 @@
+package shelly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v2"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+// TemplateSource is a config template, either a base config or an overlay
+type TemplateSource struct {
+	// Name used in errors, usually the file name
+	Name string
+	Text []byte
+}
+
+// templateFuncs are available to config templates in addition to the text/template builtins
+var templateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"default": func(def, value any) any {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+	"required": func(name string, value any) (any, error) {
+		if value == nil || value == "" {
+			return nil, fmt.Errorf("%s is required", name)
+		}
+		return value, nil
+	},
+}
+
+// RenderConfig renders the base and the overlays as Go templates with the data and merges the overlays
+// onto the base in order. Objects are merged key by key; any other value of an overlay replaces the value
+// of the base. Missing variables are an error. Secret references such as env:WIFI_PASS are kept.
+func RenderConfig(data *TemplateData, base *TemplateSource, overlays ...*TemplateSource) (*ShellyConfig, error) {
+
+	if data == nil {
+		data = &TemplateData{}
+	}
+
+	if data.Device == nil {
+		data.Device = &TemplateDevice{}
+	}
+
+	if data.Vars == nil {
+		data.Vars = make(map[string]any)
+	}
+
+	var merged any
+
+	for _, source := range append([]*TemplateSource{base}, overlays...) {
+
+		document, err := renderTemplate(data, source)
+		if err != nil {
+			return nil, err
+		}
+
+		merged = mergeValues(merged, document)
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	var config *ShellyConfig
+
+	err = json.Unmarshal(b, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return nil, fmt.Errorf("config is empty")
+	}
+
+	return config, nil
+}
+
+// renderTemplate executes the template and decodes the result as JSON or YAML
+func renderTemplate(data *TemplateData, source *TemplateSource) (any, error) {
+
+	tmpl, err := template.New(source.Name).Option("missingkey=error").Funcs(templateFuncs).Parse(string(source.Text))
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered bytes.Buffer
+
+	err = tmpl.Execute(&rendered, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var document any
+
+	var errors *multierror.Error
+
+	err = json.Unmarshal(rendered.Bytes(), &document)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+		err = yaml.Unmarshal(rendered.Bytes(), &document)
+
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			errors = multierror.Append(errors, fmt.Errorf("%s :: Invalid format. Expect JSON or YAML", source.Name))
+			return nil, errors.ErrorOrNil()
+		}
+	}
+
+	document, err = types.YAMLToJSON(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if document == nil {
+		return nil, nil
+	}
+
+	if _, ok := document.(map[string]any); !ok {
+		return nil, fmt.Errorf("%s :: expect an object", source.Name)
+	}
+
+	return document, nil
+}
+
+// mergeValues merges the overlay onto the base
+func mergeValues(base, overlay any) any {
+
+	if overlay == nil {
+		return base
+	}
+
+	baseMap, ok := base.(map[string]any)
+	if !ok {
+		return overlay
+	}
+
+	overlayMap, ok := overlay.(map[string]any)
+	if !ok {
+		return overlay
+	}
+
+	result := make(map[string]any, len(baseMap))
+	for key, value := range baseMap {
+		result[key] = value
+	}
+
+	for key, value := range overlayMap {
+		if value == nil {
+			result[key] = nil
+			continue
+		}
+		result[key] = mergeValues(result[key], value)
+	}
+
+	return result
+}
+
+// GetTemplateData returns the template data read from the device
+func (t *Client) GetTemplateData(ctx context.Context, hostname string) (*TemplateData, error) {
+
+	info, err := t.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	device := &TemplateDevice{
+		ID:       info.ID,
+		Hostname: hostname,
+	}
+
+	if info.Name != nil {
+		device.Name = *info.Name
+	}
+
+	return &TemplateData{
+		Device:     device,
+		DeviceInfo: info,
+		Vars:       make(map[string]any),
+	}, nil
+}
+
+// MergeTemplateData returns the data with the device fields and vars that are set in override replaced
+func MergeTemplateData(data, override *TemplateData) *TemplateData {
+
+	result := &TemplateData{
+		Device: &TemplateDevice{},
+		Vars:   make(map[string]any),
+	}
+
+	for _, source := range []*TemplateData{data, override} {
+
+		if source == nil {
+			continue
+		}
+
+		if source.DeviceInfo != nil {
+			result.DeviceInfo = source.DeviceInfo
+		}
+
+		if source.Device != nil {
+			if source.Device.Name != "" {
+				result.Device.Name = source.Device.Name
+			}
+			if source.Device.ID != "" {
+				result.Device.ID = source.Device.ID
+			}
+			if source.Device.Hostname != "" {
+				result.Device.Hostname = source.Device.Hostname
+			}
+		}
+
+		for key, value := range source.Vars {
+			result.Vars[key] = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,76 @@
+package shelly
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// secretConfigJSON is the Wi-Fi and MQTT config of a device; the device does not return passwords
+const secretConfigJSON = `{
+  "mqtt": {"enable": true, "server": "broker:1883", "user": "shelly"},
+  "wifi": {"sta": {"ssid": "home", "is_open": false, "enable": true}}
+}`
+
+// secretDesiredYAML sets the station password with a reference and the MQTT password literally
+const secretDesiredYAML = `
+mqtt:
+  pass: mqtt-secret
+wifi:
+  sta:
+    pass: env:TEST_WIFI_PASS
+`
+
+func TestPlanDoesNotHoldResolvedSecrets(t *testing.T) {
+
+	t.Setenv("TEST_WIFI_PASS", "wifi-secret")
+
+	current, err := decodeConfig([]byte(secretConfigJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	desired, err := decodeConfig([]byte(secretDesiredYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := NewPlan(current, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(plan.MaskSecrets())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"wifi-secret", "mqtt-secret"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("plan holds the secret %s: %s", secret, string(b))
+		}
+	}
+
+	if !strings.Contains(string(b), "env:TEST_WIFI_PASS") {
+		t.Errorf("plan does not hold the secret reference: %s", string(b))
+	}
+
+	// The reference is resolved when the plan is applied
+
+	handler := &recordingHandler{
+		params: make(map[string]json.RawMessage),
+	}
+
+	client := New(&recordingContract{handler: handler})
+
+	_, err = client.ApplyPlan(context.Background(), plan, desired, &ApplyOptions{Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"config":{"sta":{"pass":"wifi-secret"}}}`
+	if string(handler.params["Wifi.SetConfig"]) != expected {
+		t.Errorf("expected params %s, got %s", expected, string(handler.params["Wifi.SetConfig"]))
+	}
+}
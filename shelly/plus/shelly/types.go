@@ -37,6 +37,30 @@ type AuthConfig = types.AuthConfig
 type ShellyParams = types.ShellyParams
 type ShellyReport = types.ShellyReport
 type UpdatesReport = types.UpdatesReport
+type ShellyProfiles = types.ShellyProfiles
+type ShellyProfile = types.ShellyProfile
+type ShellyProfileComponent = types.ShellyProfileComponent
+type ShellyProfileReport = types.ShellyProfileReport
+type ShellyTimezones = types.ShellyTimezones
+type ShellyComponentsParams = types.ShellyComponentsParams
+type ShellyComponents = types.ShellyComponents
+type ShellyComponent = types.ShellyComponent
+type ShellyUpgradeReport = types.ShellyUpgradeReport
+type ShellyCertReport = types.ShellyCertReport
+type ShellyPlan = types.ShellyPlan
+type ShellyPlanComponent = types.ShellyPlanComponent
+type ShellyPlanChange = types.ShellyPlanChange
+type ShellyRevisions = types.ShellyRevisions
+type ConflictError = types.ConflictError
+type ShellyApplyReport = types.ShellyApplyReport
+type ShellyApplyComponent = types.ShellyApplyComponent
+type ShellyNetworkReport = types.ShellyNetworkReport
+type TemplateData = types.TemplateData
+type TemplateDevice = types.TemplateDevice
+type Notification = types.Notification
+type NotificationHandler = types.NotificationHandler
+type NotificationEvents = types.NotificationEvents
+type NotificationEvent = types.NotificationEvent
 type SwitchStatus = types.SwitchStatus
 type SwitchAenergy = types.SwitchAenergy
 type SwitchTemperature = types.SwitchTemperature
@@ -56,6 +80,7 @@ type SystemUDP = types.SystemUDP
 type SystemUIData = types.SystemUIData
 type SystemRPCUDP = types.SystemRPCUDP
 type SystemSntp = types.SystemSntp
+type SystemTimeParams = types.SystemTimeParams
 type WebhookHook = types.WebhookHook
 type WebhookParams = types.WebhookParams
 type WebhookEventInputToggleOn = types.WebhookEventInputToggleOn
@@ -0,0 +1,192 @@
+package shelly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	otaEventPrefix  = "ota_"
+	otaEventSuccess = "ota_success"
+	otaEventError   = "ota_error"
+	stageStable     = "stable"
+	stageBeta       = "beta"
+)
+
+// Upgrade runs a complete firmware upgrade. It checks for an available update, starts the update and
+// follows the ota_progress, ota_success and ota_error events while polling the device until it has rebooted.
+// Once the device is back the new version and firmware ID are confirmed. The progress function, if not nil,
+// is called for every OTA event. If params has a Url the update is taken from the Url and the availability
+// check is skipped. An error is only returned if the device could not be queried; a failed upgrade is
+// reported in the report.
+func (t *Client) Upgrade(ctx context.Context, params *ShellyParams, progress func(*NotificationEvent)) (*ShellyUpgradeReport, error) {
+
+	report := &ShellyUpgradeReport{
+		StartedAt: time.Now(),
+		Stage:     stageStable,
+	}
+
+	if params == nil {
+		params = &ShellyParams{}
+	}
+
+	if params.Stage != nil && *params.Stage != "" {
+		report.Stage = strings.ToLower(*params.Stage)
+	}
+
+	finish := func(success bool, message string) (*ShellyUpgradeReport, error) {
+		report.Success = success
+		report.Message = message
+		report.TotalSeconds = time.Since(report.StartedAt).Seconds()
+		return report, nil
+	}
+
+	before, err := t.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report.FromVersion = before.Version
+	report.FromFirmwareID = before.FirmwareID
+
+	fromUrl := params.Url != nil && *params.Url != ""
+
+	if !fromUrl {
+
+		updates, err := t.CheckForUpdate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var target *FirmwareStatus
+
+		switch report.Stage {
+
+		case stageStable:
+			target = updates.AvailableUpdates.Stable
+
+		case stageBeta:
+			target = updates.AvailableUpdates.Beta
+
+		default:
+			return nil, fmt.Errorf("stage %s is unknown; expect %s or %s", report.Stage, stageStable, stageBeta)
+		}
+
+		if target == nil || target.Version == "" {
+			report.UpToDate = true
+			return finish(true, "no update available")
+		}
+
+		report.TargetVersion = target.Version
+	}
+
+	status, err := t.System().GetStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *NotificationEvent, 100)
+
+	unsubscribe := t.Subscribe(func(notification *Notification) {
+
+		if notification.Method != "NotifyEvent" {
+			return
+		}
+
+		notificationEvents := &NotificationEvents{}
+		err := json.Unmarshal(notification.Params, notificationEvents)
+		if err != nil {
+			return
+		}
+
+		for _, event := range notificationEvents.Events {
+			event := event
+			if strings.HasPrefix(event.Event, otaEventPrefix) {
+				select {
+				case events <- &event:
+				default:
+				}
+			}
+		}
+	})
+	defer unsubscribe()
+
+	// The device accepts either a stage or a url
+	updateParams := &ShellyParams{}
+	if fromUrl {
+		updateParams.Url = params.Url
+	} else {
+		updateParams.Stage = &report.Stage
+	}
+
+	_, err = t.Update(ctx, updateParams)
+	if err != nil {
+		return finish(false, fmt.Sprintf("update was not accepted: %v", err))
+	}
+
+	ticker := time.NewTicker(RebootPollInterval)
+	defer ticker.Stop()
+
+	rebooted := false
+
+	for !rebooted {
+
+		select {
+
+		case <-ctx.Done():
+			return finish(false, "timeout waiting for the device to install the update")
+
+		case event := <-events:
+
+			if progress != nil {
+				progress(event)
+			}
+
+			switch event.Event {
+
+			case otaEventSuccess:
+				report.DownloadSeconds = time.Since(report.StartedAt).Seconds()
+
+			case otaEventError:
+				return finish(false, fmt.Sprintf("device reported %s: %s", event.Event, event.Msg))
+			}
+
+		case <-ticker.C:
+
+			current, err := t.System().GetStatus(ctx)
+			if err != nil {
+				zap.L().Debug(fmt.Sprintf("device not responding: %v", err))
+				continue
+			}
+
+			if current.Uptime < status.Uptime {
+				rebooted = true
+			}
+		}
+	}
+
+	report.RebootSeconds = time.Since(report.StartedAt).Seconds()
+
+	after, err := t.GetDeviceInfo(ctx)
+	if err != nil {
+		return finish(false, fmt.Sprintf("device rebooted but device info is not available: %v", err))
+	}
+
+	report.ToVersion = after.Version
+	report.ToFirmwareID = after.FirmwareID
+
+	if after.FirmwareID == before.FirmwareID {
+		return finish(false, "device rebooted with the same firmware")
+	}
+
+	if report.TargetVersion != "" && after.Version != report.TargetVersion {
+		return finish(false, fmt.Sprintf("device is running version %s; expected %s", after.Version, report.TargetVersion))
+	}
+
+	return finish(true, "")
+}
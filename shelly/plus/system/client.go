@@ -154,6 +154,35 @@ func (t *Client) SetConfig(ctx context.Context, config *Config) (*SetReport, err
 	}, nil
 }
 
+// SetTime sets the time of the device. This is useful for devices that can not reach an sntp server.
+func (t *Client) SetTime(ctx context.Context, params *TimeParams) (*SetReport, error) {
+
+	method := Component + ".SetTime"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: method,
+		Params: params,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SetConfigResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &SetReport{
+		Src: response.Src,
+	}, nil
+}
+
 func (t *Client) Close() {
 	if t._messageHandler != nil {
 		t._messageHandler.Close()
@@ -4,14 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/jodydadescott/shelly-manager/shelly/plus/debuglog"
 	"github.com/spf13/cobra"
 )
 
 type callback interface {
+	GetHostname() string
 	WriteObject(any) error
 	WriteStderr(s string)
 	ReadInput() ([]byte, error)
@@ -22,6 +29,12 @@ type callback interface {
 func NewCmd(callback callback) *cobra.Command {
 
 	var autorebootArg bool
+	var unixtimeArg int64
+	var followArg bool
+	var modeArg string
+	var udpPortArg int
+	var levelArg string
+	var grepArg string
 
 	rootCmd := &cobra.Command{
 		Use:   "sys",
@@ -105,6 +118,11 @@ func NewCmd(callback callback) *cobra.Command {
 				}
 			}
 
+			err = config.Validate()
+			if err != nil {
+				return err
+			}
+
 			report, err := client.SetConfig(cmd.Context(), config)
 
 			if autorebootArg {
@@ -124,6 +142,151 @@ func NewCmd(callback callback) *cobra.Command {
 
 	setConfigCmd.PersistentFlags().BoolVar(&autorebootArg, "autoreboot", false, "automatically reboot device is necessary")
 
-	rootCmd.AddCommand(getConfigCmd, getStatusCmd, getExampleConfigCmd, setConfigCmd)
+	setTimeCmd := &cobra.Command{
+		Use:   "set-time",
+		Short: "Sets the device time",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.System()
+			if err != nil {
+				return err
+			}
+
+			unixtime := unixtimeArg
+			if unixtime <= 0 {
+				unixtime = time.Now().Unix()
+			}
+
+			report, err := client.SetTime(cmd.Context(), &TimeParams{
+				Unixtime: unixtime,
+			})
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(report)
+		},
+	}
+
+	setTimeCmd.PersistentFlags().Int64Var(&unixtimeArg, "unixtime", 0, "unix timestamp (UTC); default is the current time of this host")
+
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Streams the debug logs of the device",
+		Long: "Without --follow the current debug config is returned. With --follow the debug logs are streamed " +
+			"over the device websocket or to a local UDP listener until interrupted; the debug config of the device " +
+			"is changed as needed and restored on exit.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.System()
+			if err != nil {
+				return err
+			}
+
+			config, err := client.GetConfig(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if !followArg {
+				return callback.WriteObject(config.Debug)
+			}
+
+			maxLevel, err := debuglog.LevelFromString(levelArg)
+			if err != nil {
+				return err
+			}
+
+			logClient := debuglog.New(&debuglog.Config{
+				Hostname: callback.GetHostname(),
+				Filter: &debuglog.Filter{
+					MaxLevel: maxLevel,
+					Contains: grepArg,
+				},
+			})
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			handler := func(entry *debuglog.Entry) {
+				fmt.Println(debuglog.Format(entry))
+			}
+
+			original := config.Debug
+			if original == nil {
+				original = &DebugConfig{}
+			}
+			debug := original.Clone()
+
+			changed := true
+			var follow func() error
+
+			switch strings.ToLower(modeArg) {
+
+			case "websocket", "ws", "":
+
+				if debug.Websocket != nil && debug.Websocket.Enable {
+					changed = false
+				} else {
+					debug.Websocket = &WebsocketDebug{Enable: true}
+				}
+
+				follow = func() error {
+					return logClient.FollowWebsocket(ctx, handler)
+				}
+
+			case "udp":
+
+				conn, addr, err := logClient.ListenUDP(udpPortArg)
+				if err != nil {
+					return err
+				}
+
+				debug.UDP = &UDP{Addr: &addr}
+				callback.WriteStderr(fmt.Sprintf("listening for debug logs on %s", addr))
+
+				follow = func() error {
+					return logClient.FollowUDP(ctx, conn, handler)
+				}
+
+			default:
+				return fmt.Errorf("mode %s is unknown; expect websocket or udp", modeArg)
+			}
+
+			if changed {
+
+				config.Debug = debug
+				_, err = client.SetConfig(ctx, config)
+				if err != nil {
+					return err
+				}
+
+				defer func() {
+					// The command context is done at this point
+					restoreCtx, restoreCancel := context.WithTimeout(context.Background(), RestoreTimeout)
+					defer restoreCancel()
+
+					config.Debug = original
+					_, err := client.SetConfig(restoreCtx, config)
+					if err != nil {
+						callback.WriteStderr(fmt.Sprintf("failed to restore debug config: %v", err))
+						return
+					}
+					callback.WriteStderr("debug config restored")
+				}()
+			}
+
+			return follow()
+		},
+	}
+
+	logsCmd.PersistentFlags().BoolVar(&followArg, "follow", false, "stream the logs until interrupted")
+	logsCmd.PersistentFlags().StringVar(&modeArg, "mode", "websocket", "how to receive the logs: websocket or udp")
+	logsCmd.PersistentFlags().IntVar(&udpPortArg, "udp-port", debuglog.DefaultUDPPort, "local UDP port; default selects a free port")
+	logsCmd.PersistentFlags().StringVar(&levelArg, "level", "info", "maximum level to show: error, warn, info, debug or verbose")
+	logsCmd.PersistentFlags().StringVar(&grepArg, "grep", "", "only show lines containing the string (case insensitive)")
+
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(getConfigCmd, getStatusCmd, getExampleConfigCmd, setConfigCmd, setTimeCmd)
 	return rootCmd
 }
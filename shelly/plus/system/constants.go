@@ -1,5 +1,12 @@
 package system
 
+import "time"
+
 const (
 	Component = "Sys"
 )
+
+var (
+	// RestoreTimeout is the maximum time allowed to restore the debug config when log streaming ends
+	RestoreTimeout = time.Duration(15) * time.Second
+)
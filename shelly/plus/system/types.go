@@ -18,6 +18,7 @@ type SntpConfig = types.SystemSntp
 type MqttDebug = types.SystemMqtt
 type WebsocketDebug = types.SystemWebsocket
 type UDP = types.SystemUDP
+type TimeParams = types.SystemTimeParams
 
 type SetReport = types.SetReport
 type Error = types.Error
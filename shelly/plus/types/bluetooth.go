@@ -26,12 +26,13 @@ type BluetoothConfig struct {
 	RPC *BluetoothRPC `json:"rpc" yaml:"rpc"`
 	// Observer configuration of the BT LE observer
 	Observer *BluetoothObserver `json:"observer" yaml:"observer"`
+	Extra    Extra              `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *BluetoothConfig) Clone() *BluetoothConfig {
 	c := &BluetoothConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -39,13 +40,14 @@ func (t *BluetoothConfig) Clone() *BluetoothConfig {
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/BLE#configuration
 type BluetoothRPC struct {
 	// Enable True if rpc service is enabled, false otherwise
-	Enable bool `json:"enable" yaml:"enable"`
+	Enable bool  `json:"enable" yaml:"enable"`
+	Extra  Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *BluetoothRPC) Clone() *BluetoothRPC {
 	c := &BluetoothRPC{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -53,12 +55,13 @@ func (t *BluetoothRPC) Clone() *BluetoothRPC {
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/BLE#configuration
 type BluetoothObserver struct {
 	// Enable true if BT LE observer is enabled, false otherwise
-	Enable bool `json:"enable" yaml:"enable"`
+	Enable bool  `json:"enable" yaml:"enable"`
+	Extra  Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *BluetoothObserver) Clone() *BluetoothObserver {
 	c := &BluetoothObserver{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
@@ -25,11 +25,12 @@ type CloudConfig struct {
 	Enable bool `json:"enable" yaml:"enable"`
 	// Server name of the server to which the device is connected
 	Server *string `json:"server" yaml:"server"`
+	Extra  Extra   `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *CloudConfig) Clone() *CloudConfig {
 	c := &CloudConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+
 	"github.com/jinzhu/copier"
 )
 
@@ -22,6 +24,7 @@ type SetReport struct {
 // Request generic request
 type Request struct {
 	ID     int           `json:"id"`
+	Src    string        `json:"src,omitempty"`
 	Method string        `json:"method,omitempty"`
 	Params interface{}   `json:"params,omitempty"`
 	Auth   *AuthResponse `json:"auth,omitempty"`
@@ -47,3 +50,37 @@ func (t *Response) Clone() *Response {
 	copier.Copy(&c, &t)
 	return c
 }
+
+// Notification is sent by the device without a request. The device only sends notifications to a
+// channel after a request with a src has been received on it.
+// https://shelly-api-docs.shelly.cloud/gen2/General/Notifications
+type Notification struct {
+	Src    string          `json:"src" yaml:"src"`
+	Dst    string          `json:"dst" yaml:"dst"`
+	Method string          `json:"method" yaml:"method"`
+	Params json.RawMessage `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// NotificationEvents params of a NotifyEvent notification
+// https://shelly-api-docs.shelly.cloud/gen2/General/Notifications#notifyevent
+type NotificationEvents struct {
+	Ts     float64             `json:"ts" yaml:"ts"`
+	Events []NotificationEvent `json:"events" yaml:"events"`
+}
+
+// NotificationEvent single event of a NotifyEvent notification
+// https://shelly-api-docs.shelly.cloud/gen2/General/Notifications#notifyevent
+type NotificationEvent struct {
+	// Component key of the component that emitted the event, for example sys or switch:0
+	Component string `json:"component" yaml:"component"`
+	// ID of the component instance, if applicable
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
+	// Event name, for example ota_progress
+	Event string `json:"event" yaml:"event"`
+	// Msg optional message of the event
+	Msg string `json:"msg,omitempty" yaml:"msg,omitempty"`
+	// ProgressPercent progress of an ota_progress event
+	ProgressPercent *int `json:"progress_percent,omitempty" yaml:"progress_percent,omitempty"`
+	// Ts time of the event
+	Ts float64 `json:"ts" yaml:"ts"`
+}
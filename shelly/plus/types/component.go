@@ -0,0 +1,662 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ComponentKey is the key of a component in the form <type>:<id>, or <type> for components that
+// only have a single instance such as sys or wifi
+type ComponentKey struct {
+	Type string
+	ID   int
+	// HasID true if the key has an id
+	HasID bool
+}
+
+// NewComponentKey returns the key <type>:<id>
+func NewComponentKey(componentType string, id int) *ComponentKey {
+	return &ComponentKey{
+		Type:  componentType,
+		ID:    id,
+		HasID: true,
+	}
+}
+
+// ParseComponentKey parses a key in the form <type>:<id> or <type>
+func ParseComponentKey(key string) (*ComponentKey, error) {
+
+	componentType, idString, hasID := strings.Cut(key, ":")
+
+	if componentType == "" {
+		return nil, fmt.Errorf("component key %s is not valid, type is missing", key)
+	}
+
+	if !hasID {
+		return &ComponentKey{Type: componentType}, nil
+	}
+
+	id, err := strconv.Atoi(idString)
+	if err != nil {
+		return nil, fmt.Errorf("component key %s is not valid, id is not integer", key)
+	}
+
+	return NewComponentKey(componentType, id), nil
+}
+
+func (t *ComponentKey) String() string {
+	if t.HasID {
+		return t.Type + ":" + strconv.Itoa(t.ID)
+	}
+	return t.Type
+}
+
+// componentEntry is a single component of a keyed component model
+type componentEntry struct {
+	key   string
+	value any
+}
+
+// appendComponents appends the components of a type to entries ordered by id
+func appendComponents[T any](entries []componentEntry, componentType string, components map[int]T) []componentEntry {
+
+	var ids []int
+	for id := range components {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		entries = append(entries, componentEntry{
+			key:   NewComponentKey(componentType, id).String(),
+			value: components[id],
+		})
+	}
+
+	return entries
+}
+
+// appendKeyed appends components that are already keyed to entries ordered by key
+func appendKeyed[T any](entries []componentEntry, components map[string]T) []componentEntry {
+
+	var keys []string
+	for key := range components {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entries = append(entries, componentEntry{
+			key:   key,
+			value: components[key],
+		})
+	}
+
+	return entries
+}
+
+// marshalComponents marshals the fields of plain followed by the entries as one JSON object. Keys are
+// the keys of the decoded object; if not nil the object keeps their order and fields of plain that are
+// not in keys are only written if they are set so that fields absent from the input are not added.
+// Values are the decoded JSON of the fields of plain; a field that did not change since is written as
+// it was decoded.
+func marshalComponents(plain any, entries []componentEntry, keys []string, values map[string]json.RawMessage) ([]byte, error) {
+
+	b, err := json.Marshal(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := decodeObject(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, field := range fields {
+
+		decoded, ok := values[field.key]
+		if !ok {
+			continue
+		}
+
+		unchanged, err := isUnchanged(plain, field.key, decoded)
+		if err != nil {
+			return nil, err
+		}
+
+		if unchanged {
+			fields[i].value = decoded
+		}
+	}
+
+	if keys != nil {
+
+		present := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			present[key] = true
+		}
+
+		unset := unsetFields(plain)
+
+		var kept []componentEntry
+		for _, field := range fields {
+			if present[field.key] || !unset[field.key] {
+				kept = append(kept, field)
+			}
+		}
+		fields = kept
+	}
+
+	entries = append(fields, entries...)
+
+	if keys != nil {
+		entries = orderEntries(entries, keys)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, entry := range entries {
+
+		value, ok := entry.value.(json.RawMessage)
+		if !ok {
+			value, err = json.Marshal(entry.value)
+			if err != nil {
+				return nil, fmt.Errorf("%s :: %w", entry.key, err)
+			}
+		}
+
+		key, err := json.Marshal(entry.key)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// unmarshalComponents unmarshals b into the fields of plain and passes every other key to decode.
+// Keys that decode does not handle are returned as raw JSON. All keys of b are returned in their order.
+func unmarshalComponents(b []byte, plain any, decode func(key *ComponentKey, raw json.RawMessage) (bool, error)) (map[string]json.RawMessage, []string, error) {
+
+	err := json.Unmarshal(b, plain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := decodeObject(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := jsonFieldNames(reflect.TypeOf(plain))
+
+	var unknown map[string]json.RawMessage
+	keys := []string{}
+
+	for _, entry := range raw {
+
+		name := entry.key
+		value := entry.value.(json.RawMessage)
+
+		keys = append(keys, name)
+
+		if fields[name] {
+			continue
+		}
+
+		handled := false
+
+		key, err := ParseComponentKey(name)
+		if err == nil {
+			handled, err = decode(key, value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s :: %w", name, err)
+			}
+		}
+
+		if !handled {
+			if unknown == nil {
+				unknown = make(map[string]json.RawMessage)
+			}
+			unknown[name] = value
+		}
+	}
+
+	return unknown, keys, nil
+}
+
+// marshalExtra marshals the fields of plain followed by the extra fields
+func marshalExtra(plain any, extra Extra) ([]byte, error) {
+	return marshalComponents(plain, appendKeyed(nil, extra.Fields), extra.Keys, extra.Values)
+}
+
+// unmarshalExtra unmarshals b into the fields of plain and returns the fields that plain does not have,
+// the keys of b and the JSON of the fields of plain
+func unmarshalExtra(b []byte, plain any) (Extra, error) {
+
+	fields, keys, err := unmarshalComponents(b, plain, func(key *ComponentKey, raw json.RawMessage) (bool, error) {
+		return false, nil
+	})
+	if err != nil {
+		return Extra{}, err
+	}
+
+	entries, err := decodeObject(b)
+	if err != nil {
+		return Extra{}, err
+	}
+
+	var values map[string]json.RawMessage
+
+	for _, entry := range entries {
+
+		if _, ok := fields[entry.key]; ok {
+			continue
+		}
+
+		if values == nil {
+			values = make(map[string]json.RawMessage)
+		}
+
+		values[entry.key] = entry.value.(json.RawMessage)
+	}
+
+	return Extra{
+		Fields: fields,
+		Keys:   keys,
+		Values: values,
+	}, nil
+}
+
+// isUnchanged returns true if the field of the struct plain with the JSON name has the value that
+// decoding the JSON gives
+func isUnchanged(plain any, name string, decoded json.RawMessage) (bool, error) {
+
+	value := reflect.ValueOf(plain)
+
+	for i := 0; i < value.NumField(); i++ {
+
+		field := value.Type().Field(i)
+
+		fieldName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+
+		if !field.IsExported() || fieldName != name {
+			continue
+		}
+
+		fresh := reflect.New(field.Type)
+
+		err := json.Unmarshal(decoded, fresh.Interface())
+		if err != nil {
+			return false, fmt.Errorf("%s :: %w", name, err)
+		}
+
+		return reflect.DeepEqual(fresh.Elem().Interface(), value.Field(i).Interface()), nil
+	}
+
+	return false, nil
+}
+
+// decodeObject returns the members of the JSON object b in their order. The values are json.RawMessage.
+// null is decoded as an empty object.
+func decodeObject(b []byte) ([]componentEntry, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		return nil, nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected JSON object")
+	}
+
+	var entries []componentEntry
+
+	for decoder.More() {
+
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var value json.RawMessage
+		err = decoder.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, componentEntry{
+			key:   token.(string),
+			value: value,
+		})
+	}
+
+	return entries, nil
+}
+
+// orderEntries orders the entries by the position of their key in keys. Entries whose key is not in
+// keys follow in their current order.
+func orderEntries(entries []componentEntry, keys []string) []componentEntry {
+
+	position := make(map[string]int, len(keys))
+	for i, key := range keys {
+		position[key] = i
+	}
+
+	positionOf := func(entry componentEntry) int {
+		if i, ok := position[entry.key]; ok {
+			return i
+		}
+		return len(keys)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return positionOf(entries[i]) < positionOf(entries[j])
+	})
+
+	return entries
+}
+
+// unsetFields returns the JSON names of the fields of the struct v that have their zero value
+func unsetFields(v any) map[string]bool {
+
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	unset := make(map[string]bool)
+
+	for i := 0; i < value.NumField(); i++ {
+
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		if value.Field(i).IsZero() {
+			unset[name] = true
+		}
+	}
+
+	return unset
+}
+
+// decodeComponent unmarshals raw into a new component with the id of the key
+func decodeComponent[T any](components *map[int]*T, key *ComponentKey, raw json.RawMessage) (bool, error) {
+
+	if !key.HasID {
+		return false, nil
+	}
+
+	component := new(T)
+
+	err := json.Unmarshal(raw, component)
+	if err != nil {
+		return false, err
+	}
+
+	if *components == nil {
+		*components = make(map[int]*T)
+	}
+
+	(*components)[key.ID] = component
+
+	return true, nil
+}
+
+var jsonFieldNamesCache sync.Map
+
+// jsonFieldNames returns the JSON names of the fields of the struct type t
+func jsonFieldNames(t reflect.Type) map[string]bool {
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if cached, ok := jsonFieldNamesCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	names := make(map[string]bool)
+
+	for i := 0; i < t.NumField(); i++ {
+
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		switch name {
+
+		case "-":
+			continue
+
+		case "":
+			name = field.Name
+
+		}
+
+		names[name] = true
+	}
+
+	jsonFieldNamesCache.Store(t, names)
+
+	return names
+}
+
+// marshalYAML returns the JSON encoding of v as a yaml.MapSlice so that custom JSON marshalling also
+// applies to YAML and the order of the keys is kept
+func marshalYAML(v json.Marshaler) (any, error) {
+
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	return decodeOrdered(decoder)
+}
+
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+
+	case json.Delim:
+
+		switch value {
+
+		case '{':
+			result := yaml.MapSlice{}
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				item, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, yaml.MapItem{Key: key, Value: item})
+			}
+			_, err = decoder.Token()
+			return result, err
+
+		case '[':
+			result := []any{}
+			for decoder.More() {
+				item, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, item)
+			}
+			_, err = decoder.Token()
+			return result, err
+
+		}
+
+		return nil, fmt.Errorf("unexpected delimiter %v", value)
+
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i, nil
+		}
+		return value.Float64()
+
+	}
+
+	return token, nil
+}
+
+// unmarshalYAML unmarshals a YAML mapping and unmarshals its JSON encoding into v. The order of the keys
+// is kept.
+func unmarshalYAML(unmarshal func(any) error, v any) error {
+
+	// Nested mappings are decoded as yaml.MapSlice as well
+	var generic yaml.MapSlice
+
+	err := unmarshal(&generic)
+	if err != nil {
+		return err
+	}
+
+	b, err := orderedJSON(generic)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// orderedJSON returns the JSON encoding of a value decoded by yaml.v2 keeping the order of yaml.MapSlice
+func orderedJSON(v any) ([]byte, error) {
+
+	var buf bytes.Buffer
+
+	switch value := v.(type) {
+
+	case yaml.MapSlice:
+		buf.WriteByte('{')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(fmt.Sprint(item.Key))
+			if err != nil {
+				return nil, err
+			}
+			b, err := orderedJSON(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(b)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			b, err := orderedJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(b)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+
+	}
+
+	converted, err := yamlToJSON(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(converted)
+}
+
+// yamlToJSON converts the map[interface{}]interface{} values produced by yaml.v2 to map[string]any
+func yamlToJSON(v any) (any, error) {
+
+	switch value := v.(type) {
+
+	case map[any]any:
+		result := make(map[string]any, len(value))
+		for k, item := range value {
+			converted, err := yamlToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(k)] = converted
+		}
+		return result, nil
+
+	case []any:
+		result := make([]any, len(value))
+		for i, item := range value {
+			converted, err := yamlToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+
+	}
+
+	return v, nil
+}
+
+// YAMLToJSON converts a value decoded by yaml.v2 to the generic values produced by encoding/json
+func YAMLToJSON(v any) (any, error) {
+	return yamlToJSON(v)
+}
@@ -0,0 +1,188 @@
+package types
+
+import "encoding/json"
+
+const (
+	ComponentLight  = "light"
+	ComponentInput  = "input"
+	ComponentSwitch = "switch"
+)
+
+func (t ShellyConfig) MarshalJSON() ([]byte, error) {
+
+	type plain ShellyConfig
+
+	var entries []componentEntry
+	entries = appendComponents(entries, ComponentLight, t.Light)
+	entries = appendComponents(entries, ComponentInput, t.Input)
+	entries = appendComponents(entries, ComponentSwitch, t.Switch)
+	entries = appendKeyed(entries, t.Unknown)
+
+	return marshalComponents(plain(t), entries, t.Keys, nil)
+}
+
+func (t *ShellyConfig) UnmarshalJSON(b []byte) error {
+
+	type plain ShellyConfig
+
+	c := ShellyConfig{}
+
+	unknown, keys, err := unmarshalComponents(b, (*plain)(&c), func(key *ComponentKey, raw json.RawMessage) (bool, error) {
+
+		switch key.Type {
+
+		case ComponentLight:
+			return decodeComponent(&c.Light, key, raw)
+
+		case ComponentInput:
+			return decodeComponent(&c.Input, key, raw)
+
+		case ComponentSwitch:
+			return decodeComponent(&c.Switch, key, raw)
+
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Unknown = unknown
+	c.Keys = keys
+	*t = c
+
+	return nil
+}
+
+func (t ShellyConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *ShellyConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t ShellyStatus) MarshalJSON() ([]byte, error) {
+
+	type plain ShellyStatus
+
+	var entries []componentEntry
+	entries = appendComponents(entries, ComponentLight, t.Light)
+	entries = appendComponents(entries, ComponentInput, t.Input)
+	entries = appendComponents(entries, ComponentSwitch, t.Switch)
+	entries = appendKeyed(entries, t.Unknown)
+
+	return marshalComponents(plain(t), entries, t.Keys, nil)
+}
+
+func (t *ShellyStatus) UnmarshalJSON(b []byte) error {
+
+	type plain ShellyStatus
+
+	c := ShellyStatus{}
+
+	unknown, keys, err := unmarshalComponents(b, (*plain)(&c), func(key *ComponentKey, raw json.RawMessage) (bool, error) {
+
+		switch key.Type {
+
+		case ComponentLight:
+			return decodeComponent(&c.Light, key, raw)
+
+		case ComponentInput:
+			return decodeComponent(&c.Input, key, raw)
+
+		case ComponentSwitch:
+			return decodeComponent(&c.Switch, key, raw)
+
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Unknown = unknown
+	c.Keys = keys
+	*t = c
+
+	return nil
+}
+
+func (t ShellyStatus) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *ShellyStatus) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t ShellyReport) MarshalJSON() ([]byte, error) {
+
+	type plain ShellyReport
+
+	var entries []componentEntry
+	entries = appendComponents(entries, ComponentLight, t.Light)
+	entries = appendComponents(entries, ComponentInput, t.Input)
+	entries = appendComponents(entries, ComponentSwitch, t.Switch)
+	entries = appendKeyed(entries, t.Unknown)
+
+	return marshalComponents(plain(t), entries, nil, nil)
+}
+
+func (t *ShellyReport) UnmarshalJSON(b []byte) error {
+
+	type plain ShellyReport
+
+	c := ShellyReport{}
+
+	unknown, _, err := unmarshalComponents(b, (*plain)(&c), func(key *ComponentKey, raw json.RawMessage) (bool, error) {
+
+		switch key.Type {
+
+		case ComponentLight:
+			return decodeComponent(&c.Light, key, raw)
+
+		case ComponentInput:
+			return decodeComponent(&c.Input, key, raw)
+
+		case ComponentSwitch:
+			return decodeComponent(&c.Switch, key, raw)
+
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, raw := range unknown {
+
+		report := &SetReport{}
+
+		err = json.Unmarshal(raw, report)
+		if err != nil {
+			return err
+		}
+
+		if c.Unknown == nil {
+			c.Unknown = make(map[string]*SetReport)
+		}
+
+		c.Unknown[key] = report
+	}
+
+	*t = c
+
+	return nil
+}
+
+func (t ShellyReport) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *ShellyReport) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseComponentKey(t *testing.T) {
+
+	key, err := ParseComponentKey("temperature:100")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Type != "temperature" || !key.HasID || key.ID != 100 || key.String() != "temperature:100" {
+		t.Errorf("unexpected key %+v", key)
+	}
+
+	key, err = ParseComponentKey("sys")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Type != "sys" || key.HasID || key.String() != "sys" {
+		t.Errorf("unexpected key %+v", key)
+	}
+
+	for _, invalid := range []string{"", "switch:", "switch:x", ":0"} {
+		_, err = ParseComponentKey(invalid)
+		if err == nil {
+			t.Errorf("key %q is accepted", invalid)
+		}
+	}
+}
+
+func TestShellyConfigKeyedComponents(t *testing.T) {
+
+	config := &ShellyConfig{}
+
+	err := json.Unmarshal([]byte(`{"switch:8":{"id":8,"name":"pump"},"cover:0":{"id":0},"temperature:100":{"id":100}}`), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Switch[8] == nil || config.Switch[8].Name == nil || *config.Switch[8].Name != "pump" {
+		t.Errorf("switch:8 is not decoded: %+v", config.Switch)
+	}
+
+	if len(config.Unknown) != 2 || config.Unknown["cover:0"] == nil || config.Unknown["temperature:100"] == nil {
+		t.Errorf("unknown components are not kept: %v", config.Unknown)
+	}
+}
+
+func TestShellyConfigMarshalComponents(t *testing.T) {
+
+	name := "pump"
+
+	// Without the keys of a decoded object the components follow the fields ordered by type and id
+	config := &ShellyConfig{
+		Switch: map[int]*SwitchConfig{
+			8: {Name: &name},
+		},
+		Input: map[int]*InputConfig{
+			1: {},
+		},
+		Unknown: map[string]json.RawMessage{
+			"temperature:100": json.RawMessage(`{"id":100}`),
+			"cover:0":         json.RawMessage(`{"id":0}`),
+		},
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &ShellyConfig{}
+
+	err = json.Unmarshal(b, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"input:1", "switch:8", "cover:0", "temperature:100"}
+	if len(decoded.Keys) != len(expected) {
+		t.Fatalf("expected keys %v, actual %v in %s", expected, decoded.Keys, string(b))
+	}
+
+	for i, key := range expected {
+		if decoded.Keys[i] != key {
+			t.Errorf("expected keys %v, actual %v", expected, decoded.Keys)
+			break
+		}
+	}
+
+	if decoded.Switch[8] == nil || decoded.Switch[8].Name == nil || *decoded.Switch[8].Name != name {
+		t.Errorf("switch:8 is not decoded from %s", string(b))
+	}
+}
+
+func TestShellyReportKeyedComponents(t *testing.T) {
+
+	report := &ShellyReport{
+		Switch: map[int]*SetReport{
+			1: {RestartRequired: true},
+		},
+		Unknown: map[string]*SetReport{
+			"cover:0": {},
+		},
+		Skipped:         []string{"foo:0"},
+		RestartRequired: true,
+	}
+
+	b, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &ShellyReport{}
+
+	err = json.Unmarshal(b, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Switch[1] == nil || !decoded.Switch[1].RestartRequired {
+		t.Errorf("switch:1 is not decoded from %s", string(b))
+	}
+
+	if decoded.Unknown["cover:0"] == nil {
+		t.Errorf("cover:0 is not decoded from %s", string(b))
+	}
+
+	if len(decoded.Skipped) != 1 || decoded.Skipped[0] != "foo:0" || !decoded.RestartRequired {
+		t.Errorf("fields are not decoded from %s", string(b))
+	}
+
+	y, err := yaml.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded = &ShellyReport{}
+
+	err = yaml.Unmarshal(y, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Switch[1] == nil || decoded.Unknown["cover:0"] == nil {
+		t.Errorf("components are not decoded from YAML %s", string(y))
+	}
+}
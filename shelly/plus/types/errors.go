@@ -21,6 +21,18 @@ func (t *Error) Error() string {
 	return fmt.Sprintf("status %d: err %s", t.Code, t.Message)
 }
 
+// ConflictError is returned when a revision of the device has moved since a plan was made
+type ConflictError struct {
+	// Name of the revision, for example cfg_rev
+	Name    string `json:"name" yaml:"name"`
+	Planned int    `json:"planned" yaml:"planned"`
+	Current int    `json:"current" yaml:"current"`
+}
+
+func (t *ConflictError) Error() string {
+	return fmt.Sprintf("conflict: %s is %d but the plan was made at %d; the device was changed by someone else, plan again or force", t.Name, t.Current, t.Planned)
+}
+
 type ErrorCode int
 
 var getErrorCodeMap = func() map[int]string {
@@ -46,3 +58,14 @@ const (
 )
 
 var errorCodeMap = getErrorCodeMap()
+
+// FieldError is a validation error of a single config field
+type FieldError struct {
+	// Path of the field, for example switch:0.in_mode or wifi.sta.ip
+	Path    string `json:"path" yaml:"path"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (t *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", t.Path, t.Message)
+}
@@ -33,11 +33,12 @@ type EthernetConfig struct {
 	Gateway *string `json:"gw" yaml:"gw"`
 	// Nameserver to use when ipv4mode is static
 	Nameserver *string `json:"nameserver" yaml:"nameserver"`
+	Extra      Extra   `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *EthernetConfig) Clone() *EthernetConfig {
 	c := &EthernetConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
@@ -0,0 +1,21 @@
+package types
+
+//go:generate go run ./gen . extra_gen.go
+
+import "encoding/json"
+
+// Extra is kept by the config types so that a get-config, edit, set-config cycle is lossless. Fields holds
+// the fields returned by the device that are not modeled, for example fields added by newer firmware; they
+// are sent back unchanged. Keys holds the keys of the decoded object in their order. The output keeps that
+// order and fields of the struct that were absent from the input are only written if they were set. Values
+// holds the JSON of the modeled fields as they were decoded; a field that was not changed since is written
+// as it was decoded so that null of a field without a null value and number formats such as 60.00 are kept.
+// A config that was not decoded has no Keys and is written with all the fields of the struct followed by
+// Fields ordered by name. YAML is encoded through JSON.
+//
+// The JSON and YAML methods of the types with an Extra field are generated, see gen.
+type Extra struct {
+	Fields map[string]json.RawMessage
+	Keys   []string
+	Values map[string]json.RawMessage
+}
@@ -0,0 +1,628 @@
+// Code generated by gen from the types with an Extra field; DO NOT EDIT.
+
+package types
+
+func (t BluetoothConfig) MarshalJSON() ([]byte, error) {
+	type plain BluetoothConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *BluetoothConfig) UnmarshalJSON(b []byte) error {
+	type plain BluetoothConfig
+	c := BluetoothConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t BluetoothConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *BluetoothConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t BluetoothObserver) MarshalJSON() ([]byte, error) {
+	type plain BluetoothObserver
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *BluetoothObserver) UnmarshalJSON(b []byte) error {
+	type plain BluetoothObserver
+	c := BluetoothObserver{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t BluetoothObserver) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *BluetoothObserver) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t BluetoothRPC) MarshalJSON() ([]byte, error) {
+	type plain BluetoothRPC
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *BluetoothRPC) UnmarshalJSON(b []byte) error {
+	type plain BluetoothRPC
+	c := BluetoothRPC{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t BluetoothRPC) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *BluetoothRPC) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t CloudConfig) MarshalJSON() ([]byte, error) {
+	type plain CloudConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *CloudConfig) UnmarshalJSON(b []byte) error {
+	type plain CloudConfig
+	c := CloudConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t CloudConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *CloudConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t EthernetConfig) MarshalJSON() ([]byte, error) {
+	type plain EthernetConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *EthernetConfig) UnmarshalJSON(b []byte) error {
+	type plain EthernetConfig
+	c := EthernetConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t EthernetConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *EthernetConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t InputConfig) MarshalJSON() ([]byte, error) {
+	type plain InputConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *InputConfig) UnmarshalJSON(b []byte) error {
+	type plain InputConfig
+	c := InputConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t InputConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *InputConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t LightConfig) MarshalJSON() ([]byte, error) {
+	type plain LightConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *LightConfig) UnmarshalJSON(b []byte) error {
+	type plain LightConfig
+	c := LightConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t LightConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *LightConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t MqttConfig) MarshalJSON() ([]byte, error) {
+	type plain MqttConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *MqttConfig) UnmarshalJSON(b []byte) error {
+	type plain MqttConfig
+	c := MqttConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t MqttConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *MqttConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SwitchConfig) MarshalJSON() ([]byte, error) {
+	type plain SwitchConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SwitchConfig) UnmarshalJSON(b []byte) error {
+	type plain SwitchConfig
+	c := SwitchConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SwitchConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SwitchConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemConfig) MarshalJSON() ([]byte, error) {
+	type plain SystemConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemConfig) UnmarshalJSON(b []byte) error {
+	type plain SystemConfig
+	c := SystemConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemDebug) MarshalJSON() ([]byte, error) {
+	type plain SystemDebug
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemDebug) UnmarshalJSON(b []byte) error {
+	type plain SystemDebug
+	c := SystemDebug{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemDebug) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemDebug) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemDevice) MarshalJSON() ([]byte, error) {
+	type plain SystemDevice
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemDevice) UnmarshalJSON(b []byte) error {
+	type plain SystemDevice
+	c := SystemDevice{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemDevice) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemDevice) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemLocation) MarshalJSON() ([]byte, error) {
+	type plain SystemLocation
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemLocation) UnmarshalJSON(b []byte) error {
+	type plain SystemLocation
+	c := SystemLocation{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemLocation) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemLocation) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemMqtt) MarshalJSON() ([]byte, error) {
+	type plain SystemMqtt
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemMqtt) UnmarshalJSON(b []byte) error {
+	type plain SystemMqtt
+	c := SystemMqtt{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemMqtt) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemMqtt) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemRPCUDP) MarshalJSON() ([]byte, error) {
+	type plain SystemRPCUDP
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemRPCUDP) UnmarshalJSON(b []byte) error {
+	type plain SystemRPCUDP
+	c := SystemRPCUDP{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemRPCUDP) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemRPCUDP) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemSntp) MarshalJSON() ([]byte, error) {
+	type plain SystemSntp
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemSntp) UnmarshalJSON(b []byte) error {
+	type plain SystemSntp
+	c := SystemSntp{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemSntp) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemSntp) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemUDP) MarshalJSON() ([]byte, error) {
+	type plain SystemUDP
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemUDP) UnmarshalJSON(b []byte) error {
+	type plain SystemUDP
+	c := SystemUDP{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemUDP) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemUDP) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemUIData) MarshalJSON() ([]byte, error) {
+	type plain SystemUIData
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemUIData) UnmarshalJSON(b []byte) error {
+	type plain SystemUIData
+	c := SystemUIData{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemUIData) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemUIData) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t SystemWebsocket) MarshalJSON() ([]byte, error) {
+	type plain SystemWebsocket
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *SystemWebsocket) UnmarshalJSON(b []byte) error {
+	type plain SystemWebsocket
+	c := SystemWebsocket{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t SystemWebsocket) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *SystemWebsocket) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t WebsocketConfig) MarshalJSON() ([]byte, error) {
+	type plain WebsocketConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *WebsocketConfig) UnmarshalJSON(b []byte) error {
+	type plain WebsocketConfig
+	c := WebsocketConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t WebsocketConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *WebsocketConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t WifiAP) MarshalJSON() ([]byte, error) {
+	type plain WifiAP
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *WifiAP) UnmarshalJSON(b []byte) error {
+	type plain WifiAP
+	c := WifiAP{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t WifiAP) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *WifiAP) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t WifiConfig) MarshalJSON() ([]byte, error) {
+	type plain WifiConfig
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *WifiConfig) UnmarshalJSON(b []byte) error {
+	type plain WifiConfig
+	c := WifiConfig{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t WifiConfig) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *WifiConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t WifiRangeExtender) MarshalJSON() ([]byte, error) {
+	type plain WifiRangeExtender
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *WifiRangeExtender) UnmarshalJSON(b []byte) error {
+	type plain WifiRangeExtender
+	c := WifiRangeExtender{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t WifiRangeExtender) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *WifiRangeExtender) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t WifiRoam) MarshalJSON() ([]byte, error) {
+	type plain WifiRoam
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *WifiRoam) UnmarshalJSON(b []byte) error {
+	type plain WifiRoam
+	c := WifiRoam{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t WifiRoam) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *WifiRoam) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+
+func (t WifiSTA) MarshalJSON() ([]byte, error) {
+	type plain WifiSTA
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *WifiSTA) UnmarshalJSON(b []byte) error {
+	type plain WifiSTA
+	c := WifiSTA{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t WifiSTA) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *WifiSTA) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
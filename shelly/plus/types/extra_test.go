@@ -0,0 +1,233 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// testdata/config holds Shelly.GetConfig results of the supported models as the device returns them:
+// compact JSON in the key order of the firmware, number formats such as 60.00 and fields and component
+// types that are not modeled.
+func readConfigs(t *testing.T) map[string][]byte {
+
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "config", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatal("testdata/config has no configs")
+	}
+
+	configs := make(map[string][]byte)
+
+	for _, path := range paths {
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		configs[filepath.Base(path)] = bytes.TrimSpace(b)
+	}
+
+	return configs
+}
+
+func decodeConfig(t *testing.T, b []byte) *ShellyConfig {
+
+	t.Helper()
+
+	config := &ShellyConfig{}
+
+	err := json.Unmarshal(b, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return config
+}
+
+func TestShellyConfigJSONRoundTrip(t *testing.T) {
+
+	for name, input := range readConfigs(t) {
+
+		b, err := json.Marshal(decodeConfig(t, input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(b, input) {
+			t.Errorf("%s: round trip is not lossless\nexpected %s\nactual   %s", name, string(input), string(b))
+		}
+	}
+}
+
+// YAML has no number formats; the config is kept apart from them
+func TestShellyConfigYAMLRoundTrip(t *testing.T) {
+
+	for name, input := range readConfigs(t) {
+
+		first, err := yaml.Marshal(decodeConfig(t, input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := &ShellyConfig{}
+
+		err = yaml.Unmarshal(first, decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		second, err := yaml.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(first) != string(second) {
+			t.Errorf("%s: YAML round trip is not lossless\nexpected %s\nactual   %s", name, string(first), string(second))
+		}
+
+		b, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expected, actual any
+
+		err = json.Unmarshal(input, &expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = json.Unmarshal(b, &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%s: YAML to JSON is not lossless\nexpected %s\nactual   %s", name, string(input), string(b))
+		}
+	}
+}
+
+func TestChangedFieldsAreWritten(t *testing.T) {
+
+	config := decodeConfig(t, readConfigs(t)["plus1pm.json"])
+
+	addon := "sensor"
+
+	config.Switch[0].AutoOnDelay = 30
+	config.System.Device.AddonType = &addon
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{`"auto_on_delay":30,"auto_off":false,"auto_off_delay":60.00`, `"addon_type":"sensor"`, `"current_limit":16.000`} {
+		if !bytes.Contains(b, []byte(expected)) {
+			t.Errorf("%s is missing from %s", expected, string(b))
+		}
+	}
+}
+
+func TestPartialConfigRoundTrip(t *testing.T) {
+
+	input := `{"switch:0":{"auto_off":true},"sys":{"device":{"name":"kitchen"}}}`
+
+	config := &ShellyConfig{}
+
+	err := json.Unmarshal([]byte(input), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != input {
+		t.Errorf("absent fields were added\nexpected %s\nactual   %s", input, string(b))
+	}
+
+	// Fields set after decoding are written
+	config.Switch[0].AutoOffDelay = 30
+
+	b, err = json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"switch:0":{"auto_off":true,"auto_off_delay":30},"sys":{"device":{"name":"kitchen"}}}`
+	if string(b) != expected {
+		t.Errorf("set field was not written\nexpected %s\nactual   %s", expected, string(b))
+	}
+}
+
+func TestConfigWithoutKeys(t *testing.T) {
+
+	config := &SwitchConfig{
+		Extra: Extra{
+			Fields: map[string]json.RawMessage{
+				"b": json.RawMessage(`2`),
+				"a": json.RawMessage(`1`),
+			},
+		},
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generic := make(map[string]any)
+
+	err = json.Unmarshal(b, &generic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"id", "in_mode", "auto_off_delay", "a", "b"} {
+		if _, ok := generic[key]; !ok {
+			t.Errorf("%s is missing from %s", key, string(b))
+		}
+	}
+}
+
+func TestCloneCopiesExtra(t *testing.T) {
+
+	config := decodeConfig(t, readConfigs(t)["plus1.json"])
+
+	before, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := config.Clone()
+	clone.Input[0].Extra.Fields["enable"][0] = '['
+	clone.Switch[0].Extra.Fields = map[string]json.RawMessage{"other": json.RawMessage(`true`)}
+	clone.Switch[0].Extra.Keys[0] = "changed"
+	clone.Switch[0].Extra.Values["auto_on_delay"][0] = '['
+	clone.System.Device.Extra.Values["mac"] = json.RawMessage(`"changed"`)
+	clone.Unknown["bthome"] = json.RawMessage(`[]`)
+
+	after, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(before) != string(after) {
+		t.Errorf("changes of the clone changed the original\nbefore %s\nafter  %s", string(before), string(after))
+	}
+}
@@ -0,0 +1,141 @@
+// Command gen writes the JSON and YAML methods of the types that keep the fields the device returns
+// that are not modeled, see Extra. Every struct type of the package with an Extra field gets the same
+// four methods. Run with go generate from the types package.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const extraType = "Extra"
+
+const methods = `
+func (t %[1]s) MarshalJSON() ([]byte, error) {
+	type plain %[1]s
+	return marshalExtra(plain(t), t.Extra)
+}
+
+func (t *%[1]s) UnmarshalJSON(b []byte) error {
+	type plain %[1]s
+	c := %[1]s{}
+	extra, err := unmarshalExtra(b, (*plain)(&c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	*t = c
+	return nil
+}
+
+func (t %[1]s) MarshalYAML() (any, error) {
+	return marshalYAML(t)
+}
+
+func (t *%[1]s) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAML(unmarshal, t)
+}
+`
+
+func main() {
+
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: gen <types dir> <output file>")
+		os.Exit(1)
+	}
+
+	err := run(os.Args[1], os.Args[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string) error {
+
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != filepath.Base(output)
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	packageName := ""
+
+	for _, pkg := range pkgs {
+
+		packageName = pkg.Name
+
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range gen.Specs {
+
+					typeSpec := spec.(*ast.TypeSpec)
+
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok || !hasExtra(structType) {
+						continue
+					}
+
+					names = append(names, typeSpec.Name.Name)
+				}
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	var b bytes.Buffer
+
+	fmt.Fprintln(&b, "// Code generated by gen from the types with an Extra field; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "package %s\n", packageName)
+
+	for _, name := range names {
+		fmt.Fprintf(&b, methods, name)
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, src, 0644)
+}
+
+// hasExtra returns true if the struct has the field Extra of the type Extra
+func hasExtra(structType *ast.StructType) bool {
+
+	for _, field := range structType.Fields.List {
+
+		ident, ok := field.Type.(*ast.Ident)
+		if !ok || ident.Name != extraType {
+			continue
+		}
+
+		for _, name := range field.Names {
+			if name.Name == extraType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
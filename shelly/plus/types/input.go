@@ -43,11 +43,12 @@ type InputConfig struct {
 	// ReportThreshold (only for type analog) Analog input report threshold in percent.
 	// Accepted range is device-specific, default [1.0..50.0]% unless specified otherwise
 	ReportThreshold *float64 `json:"report_thr" yaml:"report_thr"`
+	Extra           Extra    `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *InputConfig) Clone() *InputConfig {
 	c := &InputConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"github.com/jinzhu/copier"
+)
+
+// KVSItem value of a key in the Key-Value Store
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/KVS
+type KVSItem struct {
+	// Etag of the value; changes whenever the value changes
+	Etag string `json:"etag,omitempty" yaml:"etag,omitempty"`
+	// Value of the key. Any JSON value is accepted.
+	Value any `json:"value" yaml:"value"`
+}
+
+// Clone return copy
+func (t *KVSItem) Clone() *KVSItem {
+	c := &KVSItem{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// KVSItems result of KVS.GetMany
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/KVS#kvsgetmany
+type KVSItems struct {
+	// Items keyed by key
+	Items map[string]*KVSItem `json:"items" yaml:"items"`
+	// Offset of the first item; present on firmware that pages the result
+	Offset *int `json:"offset,omitempty" yaml:"offset,omitempty"`
+	// Total number of matching items; present on firmware that pages the result
+	Total *int `json:"total,omitempty" yaml:"total,omitempty"`
+}
+
+// Clone return copy
+func (t *KVSItems) Clone() *KVSItems {
+	c := &KVSItems{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// KVSParams parameters for the KVS methods
+type KVSParams struct {
+	// Key used by Set, Get and Delete
+	Key *string `json:"key,omitempty" yaml:"key,omitempty"`
+	// Value used by Set
+	Value any `json:"value,omitempty" yaml:"value,omitempty"`
+	// Match pattern used by GetMany and List, for example *
+	Match *string `json:"match,omitempty" yaml:"match,omitempty"`
+	// Offset used by GetMany
+	Offset *int `json:"offset,omitempty" yaml:"offset,omitempty"`
+}
+
+// Clone return copy
+func (t *KVSParams) Clone() *KVSParams {
+	c := &KVSParams{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// KVSReport result of KVS.Set and KVS.Delete
+type KVSReport struct {
+	Etag string `json:"etag,omitempty" yaml:"etag,omitempty"`
+	Rev  int    `json:"rev" yaml:"rev"`
+}
+
+// Clone return copy
+func (t *KVSReport) Clone() *KVSReport {
+	c := &KVSReport{}
+	copier.Copy(&c, &t)
+	return c
+}
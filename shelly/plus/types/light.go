@@ -55,12 +55,13 @@ type LightConfig struct {
 	// Both start and end are strings in the format HH:MM, where HH and MM are hours and minutes with optinal
 	// leading zeros
 	NightModeActiveBetween []string `json:"night_mode.active_between" yaml:"night_mode.active_between"`
+	Extra                  Extra    `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *LightConfig) Clone() *LightConfig {
 	c := &LightConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
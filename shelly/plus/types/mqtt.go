@@ -29,6 +29,9 @@ type MqttConfig struct {
 	ClientID *string `json:"client_id" yaml:"client_id"`
 	// User username
 	User *string `json:"user" yaml:"user"`
+	// Pass password, writeonly. Not returned by the device; omitted when not set so the password on the
+	// device is kept.
+	Pass *string `json:"pass,omitempty" yaml:"pass,omitempty"`
 	// SslCa type of the TCP sockets:
 	// null : Plain TCP connection
 	// user_ca.pem : TLS connection verified by the user-provided CA
@@ -51,12 +54,13 @@ type MqttConfig struct {
 	// EnableRPC enable RPC
 	EnableRPC bool `json:"enable_rpc" yaml:"enable_rpc"`
 	// EnableControl enable the MQTT control feature. Defalut value: true
-	EnableControl bool `json:"enable_control" yaml:"enable_control"`
+	EnableControl bool  `json:"enable_control" yaml:"enable_control"`
+	Extra         Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *MqttConfig) Clone() *MqttConfig {
 	c := &MqttConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
@@ -4,8 +4,13 @@ import (
 	"context"
 )
 
+// NotificationHandler is called for every notification received from the device
+type NotificationHandler func(*Notification)
+
 type MessageHandlerFactory interface {
 	NewHandle() MessageHandler
+	// Subscribe registers a handler for notifications. The returned function removes the handler.
+	Subscribe(handler NotificationHandler) func()
 	Close()
 }
 
@@ -0,0 +1,170 @@
+package types
+
+import (
+	"github.com/jinzhu/copier"
+)
+
+// ShellyPlan is the difference between the config of the device and a desired config. Only the
+// fields that SetConfig would change are listed.
+type ShellyPlan struct {
+	// Components that have at least one change, ordered by key
+	Components []*ShellyPlanComponent `json:"components,omitempty" yaml:"components,omitempty"`
+	// Unchanged keys of the components in the desired config that have no change
+	Unchanged []string `json:"unchanged,omitempty" yaml:"unchanged,omitempty"`
+	// Unsupported keys of the components in the desired config that the device does not have
+	Unsupported []string `json:"unsupported,omitempty" yaml:"unsupported,omitempty"`
+	// Auth true if the desired config sets auth. The current auth can not be read so it is always set.
+	Auth bool `json:"auth,omitempty" yaml:"auth,omitempty"`
+	// Changes total number of changed fields
+	Changes int `json:"changes" yaml:"changes"`
+	// RestartRequired true if any change is known to require a restart
+	RestartRequired bool `json:"restart_required" yaml:"restart_required"`
+	// Revisions of the device when the plan was made. Applying the plan fails if they have moved.
+	Revisions *ShellyRevisions `json:"revisions,omitempty" yaml:"revisions,omitempty"`
+}
+
+// Clone return copy
+func (t *ShellyPlan) Clone() *ShellyPlan {
+	c := &ShellyPlan{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// MaskSecrets returns a copy of the plan with the secrets of the changes masked. Secret references are
+// kept so that the plan can be applied.
+func (t *ShellyPlan) MaskSecrets() any {
+
+	c := *t
+	c.Components = nil
+
+	for _, component := range t.Components {
+
+		masked := *component
+		masked.Changes = nil
+
+		for _, change := range component.Changes {
+			maskedChange := *change
+			if IsSecretPath(change.Path) {
+				maskedChange.From = MaskSecretValue(change.From)
+				maskedChange.To = MaskSecretValue(change.To)
+			}
+			masked.Changes = append(masked.Changes, &maskedChange)
+		}
+
+		c.Components = append(c.Components, &masked)
+	}
+
+	return &c
+}
+
+// UnmarshalYAML decodes through JSON so that From and To of the changes can be sent to the device
+func (t *ShellyPlan) UnmarshalYAML(unmarshal func(any) error) error {
+	type plain ShellyPlan
+	return unmarshalYAML(unmarshal, (*plain)(t))
+}
+
+// ShellyRevisions are revision numbers of the device that are incremented on every change. A nil
+// revision is not checked.
+type ShellyRevisions struct {
+	// CfgRev revision of the config of the components
+	CfgRev *int `json:"cfg_rev,omitempty" yaml:"cfg_rev,omitempty"`
+	// WebhookRev revision of the webhooks
+	WebhookRev *int `json:"webhook_rev,omitempty" yaml:"webhook_rev,omitempty"`
+	// ScheduleRev revision of the schedules
+	ScheduleRev *int `json:"schedule_rev,omitempty" yaml:"schedule_rev,omitempty"`
+}
+
+// Clone return copy
+func (t *ShellyRevisions) Clone() *ShellyRevisions {
+	c := &ShellyRevisions{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ShellyPlanComponent is the list of changes of a single component
+type ShellyPlanComponent struct {
+	// Key of the component, for example sys or switch:0
+	Key     string              `json:"key" yaml:"key"`
+	Changes []*ShellyPlanChange `json:"changes" yaml:"changes"`
+	// RestartRequired true if any change of the component is known to require a restart
+	RestartRequired bool `json:"restart_required" yaml:"restart_required"`
+}
+
+// ShellyPlanChange is a change of a single field
+type ShellyPlanChange struct {
+	// Path of the field within the component, for example device.name
+	Path string `json:"path" yaml:"path"`
+	From any    `json:"from" yaml:"from"`
+	To   any    `json:"to" yaml:"to"`
+	// RestartRequired true if the change is known to require a restart
+	RestartRequired bool `json:"restart_required,omitempty" yaml:"restart_required,omitempty"`
+}
+
+// ShellyApplyReport is the report returned by Shelly.Apply. Components are either unchanged, changed
+// or failed.
+type ShellyApplyReport struct {
+	// Unchanged keys of the components that already had the desired config
+	Unchanged []string `json:"unchanged,omitempty" yaml:"unchanged,omitempty"`
+	// Changed components in the order they were written
+	Changed []*ShellyApplyComponent `json:"changed,omitempty" yaml:"changed,omitempty"`
+	// Failed components
+	Failed []*ShellyApplyComponent `json:"failed,omitempty" yaml:"failed,omitempty"`
+	// Unsupported keys of the components that the device does not have; these are not written
+	Unsupported []string `json:"unsupported,omitempty" yaml:"unsupported,omitempty"`
+	// Transactional true if the apply was transactional
+	Transactional bool `json:"transactional,omitempty" yaml:"transactional,omitempty"`
+	// RolledBack true if a transactional apply failed and the changed components were restored
+	RolledBack bool `json:"rolled_back,omitempty" yaml:"rolled_back,omitempty"`
+	// Reverted components that were changed and then restored, in the order they were restored
+	Reverted []*ShellyApplyComponent `json:"reverted,omitempty" yaml:"reverted,omitempty"`
+	// RevertFailed components that were changed and could not be restored
+	RevertFailed []*ShellyApplyComponent `json:"revert_failed,omitempty" yaml:"revert_failed,omitempty"`
+	// Network is set if the plan changed the network config of the device
+	Network *ShellyNetworkReport `json:"network,omitempty" yaml:"network,omitempty"`
+	// RestartRequired true if any component reported that a restart is required
+	RestartRequired bool `json:"restart_required" yaml:"restart_required"`
+}
+
+// Clone return copy
+func (t *ShellyApplyReport) Clone() *ShellyApplyReport {
+	c := &ShellyApplyReport{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ShellyApplyComponent is the result of writing the changes of a single component
+type ShellyApplyComponent struct {
+	// Key of the component, for example sys or switch:0
+	Key string `json:"key" yaml:"key"`
+	// Fields paths of the fields that were sent
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Error of a failed component
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	// Unconfirmed true if the connection was lost while writing a network component. The change is
+	// confirmed by reaching the device again.
+	Unconfirmed     bool `json:"unconfirmed,omitempty" yaml:"unconfirmed,omitempty"`
+	RestartRequired bool `json:"restart_required" yaml:"restart_required"`
+}
+
+// ShellyNetworkReport describes how the device was reached after its network config was changed
+type ShellyNetworkReport struct {
+	DeviceID string `json:"device_id" yaml:"device_id"`
+	MAC      string `json:"mac" yaml:"mac"`
+	// Previous address used to reach the device
+	Previous string `json:"previous" yaml:"previous"`
+	// Predicted static addresses taken from the plan
+	Predicted []string `json:"predicted,omitempty" yaml:"predicted,omitempty"`
+	// Address the device was reached at after the change
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+	// Method used to find the address; one of previous, static, mdns or arp
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	// Verified true if the device was reached at Address and reported the same device id
+	Verified bool `json:"verified" yaml:"verified"`
+}
+
+// Clone return copy
+func (t *ShellyNetworkReport) Clone() *ShellyNetworkReport {
+	c := &ShellyNetworkReport{}
+	copier.Copy(&c, &t)
+	return c
+}
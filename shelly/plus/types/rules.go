@@ -0,0 +1,162 @@
+package types
+
+// FieldRule describes the values accepted for a field. The rules are used to generate JSON schemas and
+// follow the checks done by Validate.
+type FieldRule struct {
+	// Enum values accepted for the field
+	Enum []string
+	// Minimum and Maximum inclusive range of a numeric field
+	Minimum *float64
+	Maximum *float64
+	// MinItems and MaxItems number of elements of an array field
+	MinItems *int
+	MaxItems *int
+	// MaxLength maximum length of a string field
+	MaxLength *int
+	// Pattern regular expression a string field must match
+	Pattern string
+	// Format of a string field, for example ipv4
+	Format string
+	// Items rule for the elements of an array field
+	Items *FieldRule
+}
+
+const (
+	formatIPv4          = "ipv4"
+	websocketServerExpr = "^wss?://"
+	topicPrefixExpr     = "^[^$#+%?][^#+%?]*$"
+	webhookURLsMax      = 5
+	webhookURLMaxLength = 300
+)
+
+var (
+	updateStages      = []string{"stable", "beta"}
+	componentsInclude = []string{"status", "config"}
+)
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func integer(v int) *int {
+	return &v
+}
+
+var percentRule = &FieldRule{
+	Minimum: float(percentMin),
+	Maximum: float(percentMax),
+}
+
+var notNegativeRule = &FieldRule{
+	Minimum: float(0),
+}
+
+var activeBetweenRule = &FieldRule{
+	MinItems: integer(activeBetweenLength),
+	MaxItems: integer(activeBetweenLength),
+	Items: &FieldRule{
+		Pattern: hourMinuteExpr,
+	},
+}
+
+var ipv4ModeRule = &FieldRule{
+	Enum: ipv4Modes,
+}
+
+var ipv4Rule = &FieldRule{
+	Format: formatIPv4,
+}
+
+var sslCaRule = &FieldRule{
+	Enum: sslCas,
+}
+
+var initialStateRule = &FieldRule{
+	Enum: initialStates,
+}
+
+// fieldRules keyed by <type name>.<json field name>
+var fieldRules = map[string]*FieldRule{
+
+	"AuthConfig.user": {Enum: []string{ShellyUser}},
+
+	"SwitchConfig.in_mode":            {Enum: switchInModes},
+	"SwitchConfig.initial_state":      initialStateRule,
+	"SwitchConfig.auto_on_delay":      notNegativeRule,
+	"SwitchConfig.auto_off_delay":     notNegativeRule,
+	"SwitchConfig.input_id":           notNegativeRule,
+	"SwitchConfig.power_limit":        notNegativeRule,
+	"SwitchConfig.voltage_limit":      notNegativeRule,
+	"SwitchConfig.undervoltage_limit": notNegativeRule,
+	"SwitchConfig.current_limit":      notNegativeRule,
+
+	"LightConfig.initial_state":             initialStateRule,
+	"LightConfig.auto_on_delay":             notNegativeRule,
+	"LightConfig.auto_off_delay":            notNegativeRule,
+	"LightConfig.default.brightness":        percentRule,
+	"LightConfig.night_mode.brightness":     percentRule,
+	"LightConfig.night_mode.active_between": activeBetweenRule,
+
+	"LightParams.brightness": percentRule,
+
+	"InputConfig.type":       {Enum: inputTypes},
+	"InputConfig.report_thr": percentRule,
+
+	"EthernetConfig.ipv4mode":   ipv4ModeRule,
+	"EthernetConfig.ip":         ipv4Rule,
+	"EthernetConfig.netmask":    ipv4Rule,
+	"EthernetConfig.gw":         ipv4Rule,
+	"EthernetConfig.nameserver": ipv4Rule,
+
+	"WifiSTA.ipv4mode":   ipv4ModeRule,
+	"WifiSTA.ip":         ipv4Rule,
+	"WifiSTA.netmask":    ipv4Rule,
+	"WifiSTA.gw":         ipv4Rule,
+	"WifiSTA.nameserver": ipv4Rule,
+
+	"WifiRoam.rssi_thr": {Maximum: float(0)},
+	"WifiRoam.interval": notNegativeRule,
+
+	"MqttConfig.ssl_ca": sslCaRule,
+	"MqttConfig.topic_prefix": {
+		MaxLength: integer(topicPrefixMaxLen),
+		Pattern:   topicPrefixExpr,
+	},
+
+	"WebsocketConfig.ssl_ca": sslCaRule,
+	"WebsocketConfig.server": {Pattern: websocketServerExpr},
+
+	"SystemDevice.addon_type": {Enum: addonTypes},
+	"SystemLocation.lat": {
+		Minimum: float(-latitudeMax),
+		Maximum: float(latitudeMax),
+	},
+	"SystemLocation.lon": {
+		Minimum: float(-longitudeMax),
+		Maximum: float(longitudeMax),
+	},
+
+	"WebhookParams.ssl_ca":         sslCaRule,
+	"WebhookParams.active_between": activeBetweenRule,
+	"WebhookParams.urls": {
+		MinItems: integer(1),
+		MaxItems: integer(webhookURLsMax),
+		Items: &FieldRule{
+			MaxLength: integer(webhookURLMaxLength),
+		},
+	},
+
+	"ShellyParams.stage": {Enum: updateStages},
+
+	"ShellyComponentsParams.include": {
+		MinItems: integer(1),
+		Items: &FieldRule{
+			Enum: componentsInclude,
+		},
+	},
+}
+
+// GetFieldRule returns the rule of the field or nil if there is none. Field is the JSON name of the field.
+func GetFieldRule(typeName, field string) *FieldRule {
+	return fieldRules[typeName+"."+field]
+}
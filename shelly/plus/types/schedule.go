@@ -0,0 +1,72 @@
+package types
+
+import (
+	"github.com/jinzhu/copier"
+)
+
+// ScheduleJob a schedule job. Jobs call one or more RPC methods at the times given by the timespec.
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Schedule
+type ScheduleJob struct {
+	// ID of the job. Assigned by the device; ignored by Schedule.Create
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
+	// Enable true to enable the execution of this job, false otherwise
+	Enable bool `json:"enable" yaml:"enable"`
+	// Timespec as defined by cron. Note that leading 0s are not supported (e.g.: for 8 AM you should set 8 instead of 08).
+	Timespec string `json:"timespec" yaml:"timespec"`
+	// Calls RPC methods and arguments to be invoked when the job gets executed
+	Calls []*ScheduleCall `json:"calls" yaml:"calls"`
+}
+
+// Clone return copy
+func (t *ScheduleJob) Clone() *ScheduleJob {
+	c := &ScheduleJob{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ScheduleCall RPC method and arguments invoked by a job
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Schedule
+type ScheduleCall struct {
+	// Method name of the RPC method
+	Method string `json:"method" yaml:"method"`
+	// Params of the method. Optional
+	Params map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// Clone return copy
+func (t *ScheduleCall) Clone() *ScheduleCall {
+	c := &ScheduleCall{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ScheduleJobs result of Schedule.List
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Schedule#schedulelist
+type ScheduleJobs struct {
+	// Jobs list of jobs
+	Jobs []*ScheduleJob `json:"jobs" yaml:"jobs"`
+	// Rev current revision number of the schedule instances
+	Rev int `json:"rev" yaml:"rev"`
+}
+
+// Clone return copy
+func (t *ScheduleJobs) Clone() *ScheduleJobs {
+	c := &ScheduleJobs{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ScheduleReport result of Schedule.Create, Schedule.Delete and Schedule.DeleteAll
+type ScheduleReport struct {
+	// ID of the created job
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
+	// Rev revision number of the schedule instances after the change
+	Rev int `json:"rev" yaml:"rev"`
+}
+
+// Clone return copy
+func (t *ScheduleReport) Clone() *ScheduleReport {
+	c := &ScheduleReport{}
+	copier.Copy(&c, &t)
+	return c
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"github.com/jinzhu/copier"
+)
+
+// ScriptConfig configuration of a Script component
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Script#configuration
+type ScriptConfig struct {
+	// ID of the script
+	ID int `json:"id" yaml:"id"`
+	// Name of the script
+	Name *string `json:"name" yaml:"name"`
+	// Enable true if the script runs by default on boot, false otherwise
+	Enable bool `json:"enable" yaml:"enable"`
+}
+
+// Clone return copy
+func (t *ScriptConfig) Clone() *ScriptConfig {
+	c := &ScriptConfig{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ScriptInfo item of the result of Script.List
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Script#scriptlist
+type ScriptInfo struct {
+	// ID of the script
+	ID int `json:"id" yaml:"id"`
+	// Name of the script
+	Name *string `json:"name" yaml:"name"`
+	// Enable true if the script runs by default on boot, false otherwise
+	Enable bool `json:"enable" yaml:"enable"`
+	// Running true if the script is currently running, false otherwise
+	Running bool `json:"running" yaml:"running"`
+}
+
+// Clone return copy
+func (t *ScriptInfo) Clone() *ScriptInfo {
+	c := &ScriptInfo{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ScriptList result of Script.List
+type ScriptList struct {
+	Scripts []*ScriptInfo `json:"scripts" yaml:"scripts"`
+}
+
+// Clone return copy
+func (t *ScriptList) Clone() *ScriptList {
+	c := &ScriptList{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ScriptParams parameters for the Script methods
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Script
+type ScriptParams struct {
+	// ID of the script. Required by all methods but Create
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
+	// Name of the script. Used by Create
+	Name *string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Config used by SetConfig
+	Config *ScriptConfig `json:"config,omitempty" yaml:"config,omitempty"`
+	// Code used by PutCode
+	Code *string `json:"code,omitempty" yaml:"code,omitempty"`
+	// Append used by PutCode; true to append the code to the existing code
+	Append bool `json:"append,omitempty" yaml:"append,omitempty"`
+	// Offset used by GetCode; byte offset of the first byte to return
+	Offset *int `json:"offset,omitempty" yaml:"offset,omitempty"`
+	// Len used by GetCode; number of bytes to return
+	Len *int `json:"len,omitempty" yaml:"len,omitempty"`
+}
+
+// Clone return copy
+func (t *ScriptParams) Clone() *ScriptParams {
+	c := &ScriptParams{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ScriptCode result of Script.GetCode
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Script#scriptgetcode
+type ScriptCode struct {
+	// Data the requested code
+	Data string `json:"data" yaml:"data"`
+	// Left number of bytes remaining after the returned data
+	Left int `json:"left" yaml:"left"`
+}
+
+// Clone return copy
+func (t *ScriptCode) Clone() *ScriptCode {
+	c := &ScriptCode{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ScriptReport result of Script.Create, Script.PutCode, Script.Start and Script.Stop
+type ScriptReport struct {
+	// ID of the created script
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
+	// Len total length of the code after PutCode
+	Len *int `json:"len,omitempty" yaml:"len,omitempty"`
+	// WasRunning true if the script was running before Start or Stop
+	WasRunning *bool `json:"was_running,omitempty" yaml:"was_running,omitempty"`
+	// RestartRequired true if a restart is required after SetConfig
+	RestartRequired bool `json:"restart_required,omitempty" yaml:"restart_required,omitempty"`
+}
+
+// Clone return copy
+func (t *ScriptReport) Clone() *ScriptReport {
+	c := &ScriptReport{}
+	copier.Copy(&c, &t)
+	return c
+}
@@ -0,0 +1,169 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/jodydadescott/shelly-manager/shelly/util"
+)
+
+// SecretFields are the JSON names of fields that hold secrets. They are masked in output.
+var SecretFields = []string{"pass", "ha1"}
+
+// SecretReferenceFields are the fields that may hold a secret reference, by component type and path; see
+// ResolveSecrets
+var SecretReferenceFields = map[string]bool{
+	"auth.pass":      true,
+	"mqtt.user":      true,
+	"mqtt.pass":      true,
+	"wifi.ap.pass":   true,
+	"wifi.sta.pass":  true,
+	"wifi.sta1.pass": true,
+}
+
+// SecretMasker is implemented by objects that hold secrets in fields that are not named like SecretFields,
+// for example the changes of a plan
+type SecretMasker interface {
+	// MaskSecrets returns a copy with the secrets masked
+	MaskSecrets() any
+}
+
+// IsSecretPath returns true if the last key of the dot separated path is one of SecretFields
+func IsSecretPath(path string) bool {
+
+	parts := strings.Split(path, ".")
+	last := parts[len(parts)-1]
+
+	for _, field := range SecretFields {
+		if last == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaskSecretValue returns SecretMask for a non empty string that is not a secret reference and the value
+// unchanged otherwise
+func MaskSecretValue(value any) any {
+
+	s, ok := value.(string)
+	if !ok || s == "" || util.IsSecretReference(s) {
+		return value
+	}
+
+	return util.SecretMask
+}
+
+// SecretResolver returns the value of a secret reference such as env:WIFI_PASS. Values that are not
+// references are returned unchanged.
+type SecretResolver func(value string) (string, error)
+
+// RawObject is a JSON object that keeps the order of its keys when written as YAML
+type RawObject json.RawMessage
+
+// MarshalJSON returns the object unchanged
+func (t RawObject) MarshalJSON() ([]byte, error) {
+	return t, nil
+}
+
+// MarshalYAML returns the object as ordered YAML
+func (t RawObject) MarshalYAML() (any, error) {
+	return marshalYAML(json.RawMessage(t))
+}
+
+func resolveSecret(field string, value *string, resolve SecretResolver) error {
+
+	if value == nil {
+		return nil
+	}
+
+	secret, err := resolve(*value)
+	if err != nil {
+		return &FieldError{Path: field, Message: err.Error()}
+	}
+
+	*value = secret
+	return nil
+}
+
+// ResolveSecrets replaces the secret references of the auth, wifi and mqtt configs with their values
+func (t *ShellyConfig) ResolveSecrets(resolve SecretResolver) error {
+
+	if t == nil {
+		return nil
+	}
+
+	var errors *multierror.Error
+
+	if t.Auth != nil {
+		errors = multierror.Append(errors, resolveSecret("auth.pass", t.Auth.Pass, resolve))
+	}
+
+	if t.Mqtt != nil {
+		errors = multierror.Append(errors, prefixErrors("mqtt", t.Mqtt.ResolveSecrets(resolve)))
+	}
+
+	if t.Wifi != nil {
+		errors = multierror.Append(errors, prefixErrors("wifi", t.Wifi.ResolveSecrets(resolve)))
+	}
+
+	return errors.ErrorOrNil()
+}
+
+// ResolveSecrets replaces the secret references of the user and password with their values
+func (t *MqttConfig) ResolveSecrets(resolve SecretResolver) error {
+
+	if t == nil {
+		return nil
+	}
+
+	var errors *multierror.Error
+	errors = multierror.Append(errors, resolveSecret("user", t.User, resolve))
+	errors = multierror.Append(errors, resolveSecret("pass", t.Pass, resolve))
+
+	return errors.ErrorOrNil()
+}
+
+// ResolveSecrets replaces the secret references of the access point and station passwords with their values
+func (t *WifiConfig) ResolveSecrets(resolve SecretResolver) error {
+
+	if t == nil {
+		return nil
+	}
+
+	var errors *multierror.Error
+
+	if t.Ap != nil {
+		errors = multierror.Append(errors, resolveSecret("ap.pass", t.Ap.Pass, resolve))
+	}
+
+	if t.Sta != nil {
+		errors = multierror.Append(errors, resolveSecret("sta.pass", t.Sta.Pass, resolve))
+	}
+
+	if t.Sta1 != nil {
+		errors = multierror.Append(errors, resolveSecret("sta1.pass", t.Sta1.Pass, resolve))
+	}
+
+	return errors.ErrorOrNil()
+}
+
+// prefixErrors adds the prefix to the paths of the field errors
+func prefixErrors(prefix string, err error) error {
+
+	merr, ok := err.(*multierror.Error)
+	if !ok {
+		return err
+	}
+
+	for _, e := range merr.Errors {
+		if fieldErr, ok := e.(*FieldError); ok {
+			fieldErr.Path = prefix + "." + fieldErr.Path
+		}
+	}
+
+	return merr
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/jinzhu/copier"
 )
 
@@ -14,30 +17,16 @@ type ShellyStatus struct {
 	System    *SystemStatus    `json:"sys,omitempty" yaml:"sys,omitempty"`
 	Wifi      *WifiStatus      `json:"wifi,omitempty" yaml:"wifi,omitempty"`
 	Websocket *WebsocketStatus `json:"ws,omitempty" yaml:"ws,omitempty"`
-	Light0    *LightStatus     `json:"light:0,omitempty" yaml:"light:0,omitempty"`
-	Light1    *LightStatus     `json:"light:1,omitempty" yaml:"light:1,omitempty"`
-	Light2    *LightStatus     `json:"light:2,omitempty" yaml:"light:2,omitempty"`
-	Light3    *LightStatus     `json:"light:3,omitempty" yaml:"light:3,omitempty"`
-	Light4    *LightStatus     `json:"light:4,omitempty" yaml:"light:4,omitempty"`
-	Light5    *LightStatus     `json:"light:5,omitempty" yaml:"light:5,omitempty"`
-	Light6    *LightStatus     `json:"light:6,omitempty" yaml:"light:6,omitempty"`
-	Light7    *LightStatus     `json:"light:7,omitempty" yaml:"light:7,omitempty"`
-	Input0    *InputStatus     `json:"input:0,omitempty" yaml:"input:0,omitempty"`
-	Input1    *InputStatus     `json:"input:1,omitempty" yaml:"input:1,omitempty"`
-	Input2    *InputStatus     `json:"input:2,omitempty" yaml:"input:2,omitempty"`
-	Input3    *InputStatus     `json:"input:3,omitempty" yaml:"input:3,omitempty"`
-	Input4    *InputStatus     `json:"input:4,omitempty" yaml:"input:4,omitempty"`
-	Input5    *InputStatus     `json:"input:5,omitempty" yaml:"input:5,omitempty"`
-	Input6    *InputStatus     `json:"input:6,omitempty" yaml:"input:6,omitempty"`
-	Input7    *InputStatus     `json:"input:7,omitempty" yaml:"input:7,omitempty"`
-	Switch0   *SwitchStatus    `json:"switch:0,omitempty" yaml:"switch:0,omitempty"`
-	Switch1   *SwitchStatus    `json:"switch:1,omitempty" yaml:"switch:1,omitempty"`
-	Switch2   *SwitchStatus    `json:"switch:2,omitempty" yaml:"switch:2,omitempty"`
-	Switch3   *SwitchStatus    `json:"switch:3,omitempty" yaml:"switch:3,omitempty"`
-	Switch4   *SwitchStatus    `json:"switch:4,omitempty" yaml:"switch:4,omitempty"`
-	Switch5   *SwitchStatus    `json:"switch:5,omitempty" yaml:"switch:5,omitempty"`
-	Switch6   *SwitchStatus    `json:"switch:6,omitempty" yaml:"switch:6,omitempty"`
-	Switch7   *SwitchStatus    `json:"switch:7,omitempty" yaml:"switch:7,omitempty"`
+	// Light components keyed by id
+	Light map[int]*LightStatus `json:"-" yaml:"-"`
+	// Input components keyed by id
+	Input map[int]*InputStatus `json:"-" yaml:"-"`
+	// Switch components keyed by id
+	Switch map[int]*SwitchStatus `json:"-" yaml:"-"`
+	// Unknown components keyed by <type>:<id>. The status is kept as raw JSON so that nothing is lost.
+	Unknown map[string]json.RawMessage `json:"-" yaml:"-"`
+	// Keys of the decoded object in their order; see Extra
+	Keys []string `json:"-" yaml:"-"`
 }
 
 // ShellyRPCMethods lists of all available RPC methods. It takes into account both ACL and authentication
@@ -56,11 +45,9 @@ func (t *ShellyRPCMethods) Clone() *ShellyRPCMethods {
 }
 
 // ShellyConfig Shelly component config. The config is composed of each components config.
-// Shelly devices can have zero or more 'Light', 'Input' and 'Switch' types. Because these
-// are explicity named and not members of a JSON array we have statically created them.
-// This seemed to be a cleaner solution then a customized JSON/YAML encoder/decoder. We have
-// created 8 for each which is currently more then enough as the max for any Shelly product as
-// of today is 4.
+// Shelly devices can have zero or more 'Light', 'Input' and 'Switch' types. These are keyed
+// <type>:<id> in JSON and YAML and kept in maps by id. Components of other types are kept in
+// Unknown as raw JSON.
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration
 type ShellyConfig struct {
 	Auth      *AuthConfig      `json:"auth,omitempty" yaml:"auth,omitempty"`
@@ -71,36 +58,22 @@ type ShellyConfig struct {
 	System    *SystemConfig    `json:"sys,omitempty" yaml:"sys,omitempty"`
 	Wifi      *WifiConfig      `json:"wifi,omitempty" yaml:"wifi,omitempty"`
 	Websocket *WebsocketConfig `json:"ws,omitempty" yaml:"ws,omitempty"`
-	Light0    *LightConfig     `json:"light:0,omitempty" yaml:"light:0,omitempty"`
-	Light1    *LightConfig     `json:"light:1,omitempty" yaml:"light:1,omitempty"`
-	Light2    *LightConfig     `json:"light:2,omitempty" yaml:"light:2,omitempty"`
-	Light3    *LightConfig     `json:"light:3,omitempty" yaml:"light:3,omitempty"`
-	Light4    *LightConfig     `json:"light:4,omitempty" yaml:"light:4,omitempty"`
-	Light5    *LightConfig     `json:"light:5,omitempty" yaml:"light:5,omitempty"`
-	Light6    *LightConfig     `json:"light:6,omitempty" yaml:"light:6,omitempty"`
-	Light7    *LightConfig     `json:"light:7,omitempty" yaml:"light:7,omitempty"`
-	Input0    *InputConfig     `json:"input:0,omitempty" yaml:"input:0,omitempty"`
-	Input1    *InputConfig     `json:"input:1,omitempty" yaml:"input:1,omitempty"`
-	Input2    *InputConfig     `json:"input:2,omitempty" yaml:"input:2,omitempty"`
-	Input3    *InputConfig     `json:"input:3,omitempty" yaml:"input:3,omitempty"`
-	Input4    *InputConfig     `json:"input:4,omitempty" yaml:"input:4,omitempty"`
-	Input5    *InputConfig     `json:"input:5,omitempty" yaml:"input:5,omitempty"`
-	Input6    *InputConfig     `json:"input:6,omitempty" yaml:"input:6,omitempty"`
-	Input7    *InputConfig     `json:"input:7,omitempty" yaml:"input:7,omitempty"`
-	Switch0   *SwitchConfig    `json:"switch:0,omitempty" yaml:"switch:0,omitempty"`
-	Switch1   *SwitchConfig    `json:"switch:1,omitempty" yaml:"switch:1,omitempty"`
-	Switch2   *SwitchConfig    `json:"switch:2,omitempty" yaml:"switch:2,omitempty"`
-	Switch3   *SwitchConfig    `json:"switch:3,omitempty" yaml:"switch:3,omitempty"`
-	Switch4   *SwitchConfig    `json:"switch:4,omitempty" yaml:"switch:4,omitempty"`
-	Switch5   *SwitchConfig    `json:"switch:5,omitempty" yaml:"switch:5,omitempty"`
-	Switch6   *SwitchConfig    `json:"switch:6,omitempty" yaml:"switch:6,omitempty"`
-	Switch7   *SwitchConfig    `json:"switch:7,omitempty" yaml:"switch:7,omitempty"`
+	// Light components keyed by id
+	Light map[int]*LightConfig `json:"-" yaml:"-"`
+	// Input components keyed by id
+	Input map[int]*InputConfig `json:"-" yaml:"-"`
+	// Switch components keyed by id
+	Switch map[int]*SwitchConfig `json:"-" yaml:"-"`
+	// Unknown components keyed by <type>:<id>. The config is kept as raw JSON so that nothing is lost.
+	Unknown map[string]json.RawMessage `json:"-" yaml:"-"`
+	// Keys of the decoded object in their order; see Extra
+	Keys []string `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *ShellyConfig) Clone() *ShellyConfig {
 	c := &ShellyConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -197,38 +170,24 @@ func (t *ShellyParams) Clone() *ShellyParams {
 
 // ShellyReport is the report returned by Shelly.SetConfig
 type ShellyReport struct {
-	Bluetooth       *SetReport `json:"ble,omitempty" yaml:"ble,omitempty"`
-	Cloud           *SetReport `json:"cloud,omitempty" yaml:"cloud,omitempty"`
-	Mqtt            *SetReport `json:"mqtt,omitempty" yaml:"mqtt,omitempty"`
-	Ethernet        *SetReport `json:"eth,omitempty" yaml:"eth,omitempty"`
-	System          *SetReport `json:"sys,omitempty" yaml:"sys,omitempty"`
-	Wifi            *SetReport `json:"wifi,omitempty" yaml:"wifi,omitempty"`
-	Websocket       *SetReport `json:"ws,omitempty" yaml:"ws,omitempty"`
-	Light0          *SetReport `json:"light:0,omitempty" yaml:"light:0,omitempty"`
-	Light1          *SetReport `json:"light:1,omitempty" yaml:"light:1,omitempty"`
-	Light2          *SetReport `json:"light:2,omitempty" yaml:"light:2,omitempty"`
-	Light3          *SetReport `json:"light:3,omitempty" yaml:"light:3,omitempty"`
-	Light4          *SetReport `json:"light:4,omitempty" yaml:"light:4,omitempty"`
-	Light5          *SetReport `json:"light:5,omitempty" yaml:"light:5,omitempty"`
-	Light6          *SetReport `json:"light:6,omitempty" yaml:"light:6,omitempty"`
-	Light7          *SetReport `json:"light:7,omitempty" yaml:"light:7,omitempty"`
-	Input0          *SetReport `json:"input:0,omitempty" yaml:"input:0,omitempty"`
-	Input1          *SetReport `json:"input:1,omitempty" yaml:"input:1,omitempty"`
-	Input2          *SetReport `json:"input:2,omitempty" yaml:"input:2,omitempty"`
-	Input3          *SetReport `json:"input:3,omitempty" yaml:"input:3,omitempty"`
-	Input4          *SetReport `json:"input:4,omitempty" yaml:"input:4,omitempty"`
-	Input5          *SetReport `json:"input:5,omitempty" yaml:"input:5,omitempty"`
-	Input6          *SetReport `json:"input:6,omitempty" yaml:"input:6,omitempty"`
-	Input7          *SetReport `json:"input:7,omitempty" yaml:"input:7,omitempty"`
-	Switch0         *SetReport `json:"switch:0,omitempty" yaml:"switch:0,omitempty"`
-	Switch1         *SetReport `json:"switch:1,omitempty" yaml:"switch:1,omitempty"`
-	Switch2         *SetReport `json:"switch:2,omitempty" yaml:"switch:2,omitempty"`
-	Switch3         *SetReport `json:"switch:3,omitempty" yaml:"switch:3,omitempty"`
-	Switch4         *SetReport `json:"switch:4,omitempty" yaml:"switch:4,omitempty"`
-	Switch5         *SetReport `json:"switch:5,omitempty" yaml:"switch:5,omitempty"`
-	Switch6         *SetReport `json:"switch:6,omitempty" yaml:"switch:6,omitempty"`
-	Switch7         *SetReport `json:"switch:7,omitempty" yaml:"switch:7,omitempty"`
-	RestartRequired bool       `json:"restart_required" yaml:"restart_required"`
+	Bluetooth *SetReport `json:"ble,omitempty" yaml:"ble,omitempty"`
+	Cloud     *SetReport `json:"cloud,omitempty" yaml:"cloud,omitempty"`
+	Mqtt      *SetReport `json:"mqtt,omitempty" yaml:"mqtt,omitempty"`
+	Ethernet  *SetReport `json:"eth,omitempty" yaml:"eth,omitempty"`
+	System    *SetReport `json:"sys,omitempty" yaml:"sys,omitempty"`
+	Wifi      *SetReport `json:"wifi,omitempty" yaml:"wifi,omitempty"`
+	Websocket *SetReport `json:"ws,omitempty" yaml:"ws,omitempty"`
+	// Light components keyed by id
+	Light map[int]*SetReport `json:"-" yaml:"-"`
+	// Input components keyed by id
+	Input map[int]*SetReport `json:"-" yaml:"-"`
+	// Switch components keyed by id
+	Switch map[int]*SetReport `json:"-" yaml:"-"`
+	// Unknown components keyed by <type>:<id>
+	Unknown map[string]*SetReport `json:"-" yaml:"-"`
+	// Skipped keys of the unknown components that were not sent because their SetConfig method is not known
+	Skipped         []string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	RestartRequired bool     `json:"restart_required" yaml:"restart_required"`
 }
 
 // Clone return copy
@@ -252,3 +211,180 @@ func (t *UpdatesReport) Clone() *UpdatesReport {
 	copier.Copy(&c, &t)
 	return c
 }
+
+// ShellyProfiles lists the device profiles supported by multi-profile devices and the components each
+// profile provides.
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellylistprofiles
+type ShellyProfiles struct {
+	// Profiles keyed by profile name
+	Profiles map[string]*ShellyProfile `json:"profiles" yaml:"profiles"`
+}
+
+// Clone return copy
+func (t *ShellyProfiles) Clone() *ShellyProfiles {
+	c := &ShellyProfiles{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ShellyProfile components that are available when the profile is active
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellylistprofiles
+type ShellyProfile struct {
+	// Components list of component types and count
+	Components []ShellyProfileComponent `json:"components" yaml:"components"`
+}
+
+// ShellyProfileComponent component type and the number of instances of the type
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellylistprofiles
+type ShellyProfileComponent struct {
+	// Type of component
+	Type string `json:"type" yaml:"type"`
+	// Count number of instances of the component type
+	Count int `json:"count" yaml:"count"`
+}
+
+// ShellyProfileReport is the report returned by Shelly.SetProfile. Setting a profile causes the device to reboot.
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellysetprofile
+type ShellyProfileReport struct {
+	Src string `json:"src,omitempty" yaml:"src"`
+	// ProfileWas name of the profile that was active before the change
+	ProfileWas string `json:"profile_was" yaml:"profile_was"`
+	// Rebooted true if the device was observed coming back after the reboot
+	Rebooted bool `json:"rebooted" yaml:"rebooted"`
+}
+
+// Clone return copy
+func (t *ShellyProfileReport) Clone() *ShellyProfileReport {
+	c := &ShellyProfileReport{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ShellyTimezones list of all timezones known to the device
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellylisttimezones
+type ShellyTimezones struct {
+	Timezones []string `json:"timezones" yaml:"timezones"`
+}
+
+// Clone return copy
+func (t *ShellyTimezones) Clone() *ShellyTimezones {
+	c := &ShellyTimezones{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ShellyComponentsParams parameters for Shelly.GetComponents
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellygetcomponents
+type ShellyComponentsParams struct {
+	// Offset index of the component from which to start generating the result. Optional
+	Offset *int `json:"offset,omitempty" yaml:"offset,omitempty"`
+	// DynamicOnly if true only dynamic components will be included. Optional
+	DynamicOnly *bool `json:"dynamic_only,omitempty" yaml:"dynamic_only,omitempty"`
+	// Include properties of the component to include in the result. Range of values: status, config. Optional
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	// Keys components to include in the result in the form <type>:<id>. Optional
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+}
+
+// Clone return copy
+func (t *ShellyComponentsParams) Clone() *ShellyComponentsParams {
+	c := &ShellyComponentsParams{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ShellyComponents result of Shelly.GetComponents. The result is paginated; Offset and Total
+// can be used to request the remaining components.
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellygetcomponents
+type ShellyComponents struct {
+	// Components list of components
+	Components []ShellyComponent `json:"components" yaml:"components"`
+	// CfgRev configuration revision of the device
+	CfgRev int `json:"cfg_rev" yaml:"cfg_rev"`
+	// Offset index of the first component in the result
+	Offset int `json:"offset" yaml:"offset"`
+	// Total number of components matching the request
+	Total int `json:"total" yaml:"total"`
+}
+
+// Clone return copy
+func (t *ShellyComponents) Clone() *ShellyComponents {
+	c := &ShellyComponents{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ShellyComponent single component returned by Shelly.GetComponents. Status and Config are only present
+// when requested with the include parameter.
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellygetcomponents
+type ShellyComponent struct {
+	// Key of the component in the form <type>:<id> or <type>
+	Key string `json:"key" yaml:"key"`
+	// Status of the component
+	Status any `json:"status,omitempty" yaml:"status,omitempty"`
+	// Config of the component
+	Config any `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// ShellyUpgradeReport is the report of an orchestrated firmware upgrade
+type ShellyUpgradeReport struct {
+	// Success true if the device came back with the new firmware
+	Success bool `json:"success" yaml:"success"`
+	// UpToDate true if no update was available and nothing was done
+	UpToDate bool `json:"up_to_date,omitempty" yaml:"up_to_date,omitempty"`
+	// Message describes the failure, if any
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Stage of the update; stable or beta
+	Stage string `json:"stage,omitempty" yaml:"stage,omitempty"`
+	// TargetVersion version advertised by CheckForUpdate, if known
+	TargetVersion string `json:"target_version,omitempty" yaml:"target_version,omitempty"`
+	// FromVersion version before the upgrade
+	FromVersion string `json:"from_version" yaml:"from_version"`
+	// FromFirmwareID firmware ID before the upgrade
+	FromFirmwareID string `json:"from_fw_id" yaml:"from_fw_id"`
+	// ToVersion version after the upgrade
+	ToVersion string `json:"to_version,omitempty" yaml:"to_version,omitempty"`
+	// ToFirmwareID firmware ID after the upgrade
+	ToFirmwareID string `json:"to_fw_id,omitempty" yaml:"to_fw_id,omitempty"`
+	// StartedAt time the upgrade was started
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+	// DownloadSeconds time from the start until the device reported ota_success; zero if not observed
+	DownloadSeconds float64 `json:"download_seconds,omitempty" yaml:"download_seconds,omitempty"`
+	// RebootSeconds time from the start until the device was back after the reboot
+	RebootSeconds float64 `json:"reboot_seconds,omitempty" yaml:"reboot_seconds,omitempty"`
+	// TotalSeconds total duration of the upgrade
+	TotalSeconds float64 `json:"total_seconds" yaml:"total_seconds"`
+}
+
+// Clone return copy
+func (t *ShellyUpgradeReport) Clone() *ShellyUpgradeReport {
+	c := &ShellyUpgradeReport{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// ShellyCertReport is the report of a certificate or key upload. Large uploads are split into chunks.
+type ShellyCertReport struct {
+	Src string `json:"src,omitempty" yaml:"src"`
+	// Deleted true if the existing data was deleted
+	Deleted bool `json:"deleted,omitempty" yaml:"deleted,omitempty"`
+	// Chunks number of requests used to send the data
+	Chunks int `json:"chunks,omitempty" yaml:"chunks,omitempty"`
+	// Bytes number of bytes sent
+	Bytes int `json:"bytes,omitempty" yaml:"bytes,omitempty"`
+	// Subjects of the certificates that were sent
+	Subjects []string `json:"subjects,omitempty" yaml:"subjects,omitempty"`
+	// NotAfter earliest expiry of the certificates that were sent
+	NotAfter *time.Time `json:"not_after,omitempty" yaml:"not_after,omitempty"`
+	// Warnings about the data, for example certificates that expire soon
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	// RestartRequired true if the device must be restarted for the change to apply
+	RestartRequired bool `json:"restart_required" yaml:"restart_required"`
+}
+
+// Clone return copy
+func (t *ShellyCertReport) Clone() *ShellyCertReport {
+	c := &ShellyCertReport{}
+	copier.Copy(&c, &t)
+	return c
+}
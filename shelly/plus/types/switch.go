@@ -110,12 +110,13 @@ type SwitchConfig struct {
 	UndervoltageLimit *float64 `json:"undervoltage_limit" yaml:"undervoltage_limit"`
 	// CurrentLimit Number, limit (in Amperes) over which overcurrent condition occurs (shown if applicable)
 	CurrentLimit *float64 `json:"current_limit" yaml:"current_limit"`
+	Extra        Extra    `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SwitchConfig) Clone() *SwitchConfig {
 	c := &SwitchConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
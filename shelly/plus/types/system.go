@@ -105,13 +105,14 @@ type SystemConfig struct {
 	// CfgRev Configuration revision. This number will be incremented for every configuration change of a device component.
 	// If the new config value is the same as the old one there will be no change of this property. Can not be modified
 	// explicitly by a call to Sys.SetConfig
-	CfgRev *int `json:"cfg_rev" yaml:"cfg_rev"`
+	CfgRev *int  `json:"cfg_rev" yaml:"cfg_rev"`
+	Extra  Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemConfig) Clone() *SystemConfig {
 	c := &SystemConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -132,12 +133,13 @@ type SystemDevice struct {
 	Discoverable bool `json:"discoverable" yaml:"discoverable"`
 	// AddonType enable/disable addon board (if supported). Range of values: sensor; null to disable.
 	AddonType *string `json:"addon_type" yaml:"addon_type"`
+	Extra     Extra   `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemDevice) Clone() *SystemDevice {
 	c := &SystemDevice{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -149,13 +151,14 @@ type SystemLocation struct {
 	// Lat latitude in degrees (null if unavailable)
 	Lat *float64 `json:"lat" yaml:"lat"`
 	// Lon longitude in degrees (null if unavailable)
-	Lon *float64 `json:"lon" yaml:"lon"`
+	Lon   *float64 `json:"lon" yaml:"lon"`
+	Extra Extra    `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemLocation) Clone() *SystemLocation {
 	c := &SystemLocation{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -169,26 +172,28 @@ type SystemDebug struct {
 	// websocket is not restricted, even when authentication is enabled!
 	Websocket *SystemWebsocket `json:"websocket" yaml:"websocket"`
 	// UDP Configuration of logs streamed over UDP
-	UDP *SystemUDP `json:"udp" yaml:"udp"`
+	UDP   *SystemUDP `json:"udp" yaml:"udp"`
+	Extra Extra      `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemDebug) Clone() *SystemDebug {
 	c := &SystemDebug{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
 // SystemMqtt Configuration of logs streamed over MQTT
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration
 type SystemMqtt struct {
-	Enable bool `json:"enable" yaml:"enable"`
+	Enable bool  `json:"enable" yaml:"enable"`
+	Extra  Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemMqtt) Clone() *SystemMqtt {
 	c := &SystemMqtt{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -197,38 +202,41 @@ func (t *SystemMqtt) Clone() *SystemMqtt {
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration
 type SystemWebsocket struct {
 	// True if enabled, false otherwise
-	Enable bool `json:"enable" yaml:"enable"`
+	Enable bool  `json:"enable" yaml:"enable"`
+	Extra  Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemWebsocket) Clone() *SystemWebsocket {
 	c := &SystemWebsocket{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
 // SystemUDP Configuration of logs streamed over UDP. Used by component System.
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration
 type SystemUDP struct {
-	Addr *string `json:"addr" yaml:"addr"`
+	Addr  *string `json:"addr" yaml:"addr"`
+	Extra Extra   `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemUDP) Clone() *SystemUDP {
 	c := &SystemUDP{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
 // SystemUIData user interface data. Used by component System.
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration
 type SystemUIData struct {
+	Extra Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemUIData) Clone() *SystemUIData {
 	c := &SystemUIData{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -239,12 +247,13 @@ type SystemRPCUDP struct {
 	DstAddr string `json:"dst_addr" yaml:"dst_addr"`
 	// ListenPort port number for inbound UDP RPC channel, null disables. Restart is required for changes to apply
 	ListenPort *string `json:"listen_port" yaml:"listen_port"`
+	Extra      Extra   `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemRPCUDP) Clone() *SystemRPCUDP {
 	c := &SystemRPCUDP{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -253,11 +262,26 @@ func (t *SystemRPCUDP) Clone() *SystemRPCUDP {
 type SystemSntp struct {
 	// Server name of the sntp server
 	Server string `json:"server" yaml:"server"`
+	Extra  Extra  `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *SystemSntp) Clone() *SystemSntp {
 	c := &SystemSntp{}
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
+	return c
+}
+
+// SystemTimeParams parameters for Sys.SetTime
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#syssettime
+type SystemTimeParams struct {
+	// Unixtime Unix timestamp (in UTC) to set the device time to
+	Unixtime int64 `json:"unixtime" yaml:"unixtime"`
+}
+
+// Clone return copy
+func (t *SystemTimeParams) Clone() *SystemTimeParams {
+	c := &SystemTimeParams{}
 	copier.Copy(&c, &t)
 	return c
 }
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"github.com/jinzhu/copier"
+)
+
+// TemplateData is the data available to config templates, for example {{ .Device.Name }},
+// {{ .Vars.room }} and {{ .DeviceInfo.MAC }}
+type TemplateData struct {
+	// Device identity of the device the config is rendered for
+	Device *TemplateDevice `json:"device,omitempty" yaml:"device,omitempty"`
+	// DeviceInfo as returned by the device. Nil when rendering without a device.
+	DeviceInfo *DeviceInfo `json:"device_info,omitempty" yaml:"device_info,omitempty"`
+	// Vars user defined variables
+	Vars map[string]any `json:"vars,omitempty" yaml:"vars,omitempty"`
+}
+
+// Clone return copy
+func (t *TemplateData) Clone() *TemplateData {
+	c := &TemplateData{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// TemplateDevice identity of the device a config is rendered for. Values from a vars file take
+// precedence over the values read from the device.
+type TemplateDevice struct {
+	// Name of the device
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// ID of the device, for example shellyplus1-a8032ab12345
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+	// Hostname used to reach the device
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+}
+
+// Clone return copy
+func (t *TemplateDevice) Clone() *TemplateDevice {
+	c := &TemplateDevice{}
+	copier.Copy(&c, &t)
+	return c
+}
@@ -0,0 +1,502 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Values accepted by the device. Empty values are not checked; they are either unset or rejected by
+// the device itself.
+var (
+	switchInModes     = []string{"momentary", "follow", "flip", "detached"}
+	initialStates     = []string{"off", "on", "restore_last", "match_input"}
+	inputTypes        = []string{"switch", "button", "analog"}
+	ipv4Modes         = []string{"dhcp", "static"}
+	sslCas            = []string{"*", "user_ca.pem", "ca.pem"}
+	addonTypes        = []string{"sensor"}
+	websocketSchemes  = []string{"ws://", "wss://"}
+	hourMinutePattern = regexp.MustCompile(hourMinuteExpr)
+)
+
+const (
+	ipv4ModeStatic      = "static"
+	topicPrefixInvalid  = "#+%?"
+	topicPrefixMaxLen   = 300
+	activeBetweenLength = 2
+	hourMinuteExpr      = `^([01]?[0-9]|2[0-3]):[0-5]?[0-9]$`
+	percentMin          = 0
+	percentMax          = 100
+	latitudeMax         = 90
+	longitudeMax        = 180
+)
+
+// validatable is implemented by the config types. Nested types are validated with the path of the
+// parent as prefix.
+type validatable interface {
+	validate(v *validator)
+}
+
+type validator struct {
+	prefix string
+	errors *[]error
+}
+
+func newValidator() *validator {
+	return &validator{
+		errors: &[]error{},
+	}
+}
+
+// validate returns a multierror of *FieldError or nil if the config is valid
+func validate(config validatable) error {
+
+	v := newValidator()
+	config.validate(v)
+
+	var errors *multierror.Error
+	for _, err := range *v.errors {
+		errors = multierror.Append(errors, err)
+	}
+
+	return errors.ErrorOrNil()
+}
+
+func (t *validator) path(field string) string {
+	if t.prefix == "" {
+		return field
+	}
+	return t.prefix + "." + field
+}
+
+func (t *validator) fail(field, format string, args ...any) {
+	*t.errors = append(*t.errors, &FieldError{
+		Path:    t.path(field),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (t *validator) nested(field string, config validatable) {
+	config.validate(&validator{
+		prefix: t.path(field),
+		errors: t.errors,
+	})
+}
+
+func (t *validator) oneOf(field, value string, allowed []string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	t.fail(field, "invalid value %q; expect one of %s", value, strings.Join(allowed, ", "))
+}
+
+func (t *validator) oneOfPtr(field string, value *string, allowed []string) {
+	if value != nil {
+		t.oneOf(field, *value, allowed)
+	}
+}
+
+func (t *validator) between(field string, value, min, max float64) {
+	if value < min || value > max {
+		t.fail(field, "value %v is out of range [%v..%v]", value, min, max)
+	}
+}
+
+func (t *validator) notNegative(field string, value *float64) {
+	if value != nil && *value < 0 {
+		t.fail(field, "value %v must not be negative", *value)
+	}
+}
+
+func (t *validator) ipv4(field string, value *string) {
+	if value == nil || *value == "" {
+		return
+	}
+	ip := net.ParseIP(*value)
+	if ip == nil || ip.To4() == nil {
+		t.fail(field, "invalid IPv4 address %q", *value)
+	}
+}
+
+func (t *validator) port(field, value string) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		t.fail(field, "invalid port %q", value)
+	}
+}
+
+func (t *validator) hostPort(field string, value *string) {
+	if value == nil || *value == "" {
+		return
+	}
+	_, port, err := net.SplitHostPort(*value)
+	if err != nil {
+		t.fail(field, "invalid address %q; expect host:port", *value)
+		return
+	}
+	t.port(field, port)
+}
+
+func (t *validator) activeBetween(field string, value []string) {
+
+	if len(value) == 0 {
+		return
+	}
+
+	if len(value) != activeBetweenLength {
+		t.fail(field, "expect %d elements, start and end, got %d", activeBetweenLength, len(value))
+		return
+	}
+
+	for i, s := range value {
+		if !hourMinutePattern.MatchString(s) {
+			t.fail(fmt.Sprintf("%s[%d]", field, i), "invalid time %q; expect HH:MM", s)
+		}
+	}
+}
+
+// ipv4Config validates the fields shared by the ethernet and wifi station configs
+func (t *validator) ipv4Config(mode string, ip, netmask, gateway, nameserver *string) {
+
+	t.oneOf("ipv4mode", mode, ipv4Modes)
+
+	if mode == ipv4ModeStatic {
+		required := func(field string, value *string) {
+			if value == nil || *value == "" {
+				t.fail(field, "is required when ipv4mode is static")
+			}
+		}
+		required("ip", ip)
+		required("netmask", netmask)
+		required("gw", gateway)
+	}
+
+	t.ipv4("ip", ip)
+	t.ipv4("netmask", netmask)
+	t.ipv4("gw", gateway)
+	t.ipv4("nameserver", nameserver)
+}
+
+// Validate returns the field errors of the config or nil
+func (t *ShellyConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *ShellyConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.nested("auth", t.Auth)
+	v.nested("ble", t.Bluetooth)
+	v.nested("cloud", t.Cloud)
+	v.nested("mqtt", t.Mqtt)
+	v.nested("eth", t.Ethernet)
+	v.nested("sys", t.System)
+	v.nested("wifi", t.Wifi)
+	v.nested("ws", t.Websocket)
+
+	for _, id := range sortedIDs(t.Light) {
+		v.nested(NewComponentKey(ComponentLight, id).String(), t.Light[id])
+	}
+
+	for _, id := range sortedIDs(t.Input) {
+		v.nested(NewComponentKey(ComponentInput, id).String(), t.Input[id])
+	}
+
+	for _, id := range sortedIDs(t.Switch) {
+		v.nested(NewComponentKey(ComponentSwitch, id).String(), t.Switch[id])
+	}
+}
+
+func sortedIDs[T any](components map[int]T) []int {
+	ids := make([]int, 0, len(components))
+	for id := range components {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Validate returns the field errors of the config or nil
+func (t *AuthConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *AuthConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	if t.User != "" && t.User != ShellyUser {
+		v.fail("user", "invalid user %q; only %s is supported", t.User, ShellyUser)
+	}
+}
+
+// Validate returns the field errors of the config or nil
+func (t *BluetoothConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *BluetoothConfig) validate(v *validator) {}
+
+// Validate returns the field errors of the config or nil
+func (t *CloudConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *CloudConfig) validate(v *validator) {}
+
+// Validate returns the field errors of the config or nil
+func (t *MqttConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *MqttConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.oneOfPtr("ssl_ca", t.SslCa, sslCas)
+
+	if t.Enable && (t.Server == nil || *t.Server == "") {
+		v.fail("server", "is required when enabled")
+	}
+
+	if t.TopicPrefix != nil {
+		prefix := *t.TopicPrefix
+		if len(prefix) > topicPrefixMaxLen {
+			v.fail("topic_prefix", "is limited to %d characters", topicPrefixMaxLen)
+		}
+		if strings.HasPrefix(prefix, "$") {
+			v.fail("topic_prefix", "must not start with $")
+		}
+		if strings.ContainsAny(prefix, topicPrefixInvalid) {
+			v.fail("topic_prefix", "must not contain any of %s", topicPrefixInvalid)
+		}
+	}
+
+	if t.UseClientCert && (t.SslCa == nil || *t.SslCa == "") {
+		v.fail("use_client_cert", "requires ssl_ca to be set")
+	}
+}
+
+// Validate returns the field errors of the config or nil
+func (t *EthernetConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *EthernetConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.ipv4Config(t.Ipv4Mode, t.IP, t.Netmask, t.Gateway, t.Nameserver)
+}
+
+// Validate returns the field errors of the config or nil
+func (t *SystemConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *SystemConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.nested("device", t.Device)
+	v.nested("location", t.Location)
+	v.nested("debug", t.Debug)
+	v.nested("rpc_udp", t.RPCUDP)
+}
+
+func (t *SystemDevice) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.oneOfPtr("addon_type", t.AddonType, addonTypes)
+}
+
+func (t *SystemLocation) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	if t.Lat != nil {
+		v.between("lat", *t.Lat, -latitudeMax, latitudeMax)
+	}
+
+	if t.Lon != nil {
+		v.between("lon", *t.Lon, -longitudeMax, longitudeMax)
+	}
+}
+
+func (t *SystemDebug) validate(v *validator) {
+
+	if t == nil || t.UDP == nil {
+		return
+	}
+
+	v.hostPort("udp.addr", t.UDP.Addr)
+}
+
+func (t *SystemRPCUDP) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	if t.ListenPort != nil && *t.ListenPort != "" {
+		v.port("listen_port", *t.ListenPort)
+	}
+}
+
+// Validate returns the field errors of the config or nil
+func (t *WifiConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *WifiConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.nested("sta", t.Sta)
+	v.nested("sta1", t.Sta1)
+	v.nested("roam", t.Roam)
+}
+
+func (t *WifiSTA) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.ipv4Config(t.Ipv4Mode, t.IP, t.Netmask, t.Gateway, t.Nameserver)
+}
+
+func (t *WifiRoam) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	if t.RSSIThreshold > 0 {
+		v.fail("rssi_thr", "value %d must not be positive", t.RSSIThreshold)
+	}
+
+	if t.Interval < 0 {
+		v.fail("interval", "value %d must not be negative", t.Interval)
+	}
+}
+
+// Validate returns the field errors of the config or nil
+func (t *WebsocketConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *WebsocketConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.oneOfPtr("ssl_ca", t.SslCa, sslCas)
+
+	if t.Server == "" {
+		if t.Enable {
+			v.fail("server", "is required when enabled")
+		}
+		return
+	}
+
+	for _, scheme := range websocketSchemes {
+		if strings.HasPrefix(t.Server, scheme) {
+			return
+		}
+	}
+
+	v.fail("server", "invalid server %q; expect prefix %s", t.Server, strings.Join(websocketSchemes, " or "))
+}
+
+// Validate returns the field errors of the config or nil
+func (t *LightConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *LightConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.oneOf("initial_state", t.InitialState, initialStates)
+	v.notNegative("auto_on_delay", &t.AutoOnDelay)
+	v.notNegative("auto_off_delay", &t.AutoOffDelay)
+	v.between("default.brightness", t.DefaultBrightness, percentMin, percentMax)
+	v.between("night_mode.brightness", t.NightModeBrightness, percentMin, percentMax)
+	v.activeBetween("night_mode.active_between", t.NightModeActiveBetween)
+}
+
+// Validate returns the field errors of the config or nil
+func (t *InputConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *InputConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.oneOf("type", t.Type, inputTypes)
+
+	if t.ReportThreshold != nil {
+		v.between("report_thr", *t.ReportThreshold, percentMin, percentMax)
+	}
+}
+
+// Validate returns the field errors of the config or nil
+func (t *SwitchConfig) Validate() error {
+	return validate(t)
+}
+
+func (t *SwitchConfig) validate(v *validator) {
+
+	if t == nil {
+		return
+	}
+
+	v.oneOf("in_mode", t.InMode, switchInModes)
+	v.oneOf("initial_state", t.InitialState, initialStates)
+	v.notNegative("auto_on_delay", &t.AutoOnDelay)
+	v.notNegative("auto_off_delay", &t.AutoOffDelay)
+
+	if t.InputID < 0 {
+		v.fail("input_id", "value %d must not be negative", t.InputID)
+	}
+
+	v.notNegative("power_limit", t.PowerLimit)
+	v.notNegative("voltage_limit", t.VoltageLimit)
+	v.notNegative("undervoltage_limit", t.UndervoltageLimit)
+	v.notNegative("current_limit", t.CurrentLimit)
+}
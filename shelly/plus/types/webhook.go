@@ -88,6 +88,8 @@ type WebhookHook struct {
 	SslCa *string `json:"ssl_ca" yaml:"ssl_ca"`
 	// URLs Containing url addresses that will be called when the webhook event occurs
 	URLs []string `json:"urls" yaml:"urls"`
+	// ActiveBetween period during which the webhook will be active. Not present if the webhook is always active.
+	ActiveBetween []string `json:"active_between,omitempty" yaml:"active_between,omitempty"`
 	// Condition hook trigger condition associated with event.
 	Condition any `json:"condition" yaml:"condition"`
 	// RepeatPeriod minimum interval for invocations of the hook.
@@ -98,6 +100,8 @@ type WebhookHook struct {
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Webhook/#webhookcreate &
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Webhook/#webhookupdate
 type WebhookParams struct {
+	// ID of the webhook to update. Required for Update, ignored by Create
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
 	// Event which will trigger the execution of the webhook. Valid events are listed by Webhook.ListSupported.
 	// Example values: switch.on, input.toggle_off. Required
 	Event string `json:"event" yaml:"event"`
@@ -206,3 +210,27 @@ type Webhooks struct {
 	// Types of events
 	Types WebhookTypes `json:"types,omitempty" yaml:"types,omitempty"`
 }
+
+// WebhookDeleteParams parameters for Webhook.Delete
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Webhook/#webhookdelete
+type WebhookDeleteParams struct {
+	// ID of the webhook to delete. Required
+	ID int `json:"id" yaml:"id"`
+}
+
+// WebhookSyncReport is the report returned by a declarative sync of webhooks. Hooks are identified
+// by their name.
+type WebhookSyncReport struct {
+	// Created names of the hooks that were created
+	Created []string `json:"created,omitempty" yaml:"created,omitempty"`
+	// Updated names of the hooks that were updated
+	Updated []string `json:"updated,omitempty" yaml:"updated,omitempty"`
+	// Deleted names (or IDs of unnamed hooks) of the hooks that were deleted
+	Deleted []string `json:"deleted,omitempty" yaml:"deleted,omitempty"`
+	// Unchanged names of the hooks that already matched
+	Unchanged []string `json:"unchanged,omitempty" yaml:"unchanged,omitempty"`
+	// DryRun true if no changes were sent to the device
+	DryRun bool `json:"dry_run" yaml:"dry_run"`
+	// Rev revision of the webhooks after the sync
+	Rev int `json:"rev" yaml:"rev"`
+}
@@ -27,11 +27,12 @@ type WebsocketConfig struct {
 	Server string `json:"server" yaml:"server"`
 	// SslCa type of the TCP sockets
 	SslCa *string `json:"ssl_ca" yaml:"ssl_ca"`
+	Extra Extra   `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *WebsocketConfig) Clone() *WebsocketConfig {
 	c := &WebsocketConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
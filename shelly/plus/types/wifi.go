@@ -75,13 +75,14 @@ type WifiConfig struct {
 	// Sta1 information about the sta configuration
 	Sta1 *WifiSTA `json:"sta1" yaml:"sta1"`
 	// Roam WiFi roaming configuration
-	Roam *WifiRoam `json:"roam" yaml:"roam"`
+	Roam  *WifiRoam `json:"roam" yaml:"roam"`
+	Extra Extra     `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *WifiConfig) Clone() *WifiConfig {
 	c := &WifiConfig{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -98,25 +99,27 @@ type WifiAP struct {
 	Enable bool `json:"enable" yaml:"enable"`
 	// RangeExtender range extender configuration object, available only when range extender functionality is present.
 	RangeExtender *WifiRangeExtender `json:"range_extender" yaml:"range_extender"`
+	Extra         Extra              `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *WifiAP) Clone() *WifiAP {
 	c := &WifiAP{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
 // WifiRangeExtender Range extender configuration object, available only when range extender functionality is present.
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration
 type WifiRangeExtender struct {
-	Enable bool `json:"enable" yaml:"enable"`
+	Enable bool  `json:"enable" yaml:"enable"`
+	Extra  Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *WifiRangeExtender) Clone() *WifiRangeExtender {
 	c := &WifiRangeExtender{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -141,12 +144,13 @@ type WifiSTA struct {
 	Gateway *string `json:"gw" yaml:"gw"`
 	// Nameserver to use when ipv4mode is static
 	Nameserver *string `json:"nameserver" yaml:"nameserver"`
+	Extra      Extra   `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *WifiSTA) Clone() *WifiSTA {
 	c := &WifiSTA{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
@@ -157,13 +161,14 @@ type WifiRoam struct {
 	RSSIThreshold int `json:"rssi_thr" yaml:"rssi_thr"`
 	// Interval at which to scan for better access points. Enabled if set to positive number,
 	// disabled if set to 0. Default value: 60
-	Interval int `json:"interval" yaml:"interval"`
+	Interval int   `json:"interval" yaml:"interval"`
+	Extra    Extra `json:"-" yaml:"-"`
 }
 
 // Clone return copy
 func (t *WifiRoam) Clone() *WifiRoam {
 	c := &WifiRoam{}
-	copier.Copy(&c, &t)
+	copier.CopyWithOption(&c, &t, copier.Option{DeepCopy: true})
 	return c
 }
 
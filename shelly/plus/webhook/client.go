@@ -123,11 +123,15 @@ func (t *Client) Create(ctx context.Context, params *Params) (*Webhooks, error)
 	return response.Result, nil
 }
 
-// Update updates an existing Webhook instance
+// Update updates an existing Webhook instance. The ID of the params is required.
 func (t *Client) Update(ctx context.Context, params *Params) (*Webhooks, error) {
 
 	method := Component + ".Update"
 
+	if params.ID == nil {
+		return nil, fmt.Errorf("ID is required")
+	}
+
 	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
 		Method: method,
 		Params: params,
@@ -155,13 +159,15 @@ func (t *Client) Update(ctx context.Context, params *Params) (*Webhooks, error)
 }
 
 // Delete deletes an existing Webhook instance
-func (t *Client) Delete(ctx context.Context, params *Webhooks) (*Webhooks, error) {
+func (t *Client) Delete(ctx context.Context, id int) (*Webhooks, error) {
 
-	method := Component + ".Update"
+	method := Component + ".Delete"
 
 	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
 		Method: method,
-		Params: params,
+		Params: &DeleteParams{
+			ID: id,
+		},
 	})
 
 	if err != nil {
@@ -185,7 +191,7 @@ func (t *Client) Delete(ctx context.Context, params *Webhooks) (*Webhooks, error
 	return response.Result, nil
 }
 
-// Delete deletes all existing Webhooks
+// DeleteAll deletes all existing Webhooks
 func (t *Client) DeleteAll(ctx context.Context) (*Webhooks, error) {
 
 	method := Component + ".DeleteAll"
@@ -21,9 +21,14 @@ type callback interface {
 
 func NewCmd(callback callback) *cobra.Command {
 
+	var dryRunArg bool
+	var allowEmptyArg bool
+	var forceArg bool
+	var revArg int
+
 	rootCmd := &cobra.Command{
-		Use:   "proto",
-		Short: "Proto Component",
+		Use:   "webhook",
+		Short: "Webhook Service",
 	}
 
 	listCmd := &cobra.Command{
@@ -172,9 +177,7 @@ func NewCmd(callback callback) *cobra.Command {
 				return fmt.Errorf("ID must be an integer, %s is not valid", arg)
 			}
 
-			report, err := client.Delete(cmd.Context(), &Webhooks{
-				ID: id,
-			})
+			report, err := client.Delete(cmd.Context(), id)
 			if err != nil {
 				return err
 			}
@@ -202,6 +205,97 @@ func NewCmd(callback callback) *cobra.Command {
 		},
 	}
 
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Makes the WebHooks of the device match the desired list of hooks",
+		Long: "Reads the desired list of hooks either as a list or as an object with the attribute hooks. " +
+			"Hooks are matched by name; differing hooks are updated, missing hooks are created and hooks " +
+			"that are not in the list are deleted. An empty list deletes all hooks and requires --allow-empty. The sync " +
+			"is refused if the webhooks were changed since the revision given with --rev or the rev of the input, " +
+			"for example the output of list or of a dry run, unless --force is set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := callback.WebHook()
+			if err != nil {
+				return err
+			}
+
+			b, err := callback.ReadInput()
+			if err != nil {
+				return err
+			}
+
+			desired, rev, err := unmarshalHooks(b)
+			if err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("rev") {
+				rev = &revArg
+			}
+
+			if forceArg {
+				rev = nil
+			}
+
+			if len(desired) == 0 && !allowEmptyArg {
+				return fmt.Errorf("desired list of hooks is empty; use --allow-empty to delete all hooks")
+			}
+
+			report, err := client.Sync(cmd.Context(), desired, &SyncOptions{
+				DryRun: dryRunArg,
+				Rev:    rev,
+			})
+			if err != nil {
+				return err
+			}
+
+			return callback.WriteObject(report)
+		},
+	}
+
+	syncCmd.PersistentFlags().BoolVar(&dryRunArg, "dry-run", false, "report the changes without applying them")
+	syncCmd.PersistentFlags().BoolVar(&allowEmptyArg, "allow-empty", false, "allow an empty list which deletes all hooks")
+	syncCmd.PersistentFlags().IntVar(&revArg, "rev", 0, "expected revision of the webhooks, for example the rev of a dry run")
+	syncCmd.PersistentFlags().BoolVar(&forceArg, "force", false, "sync even if the webhooks were changed since the expected revision")
+
+	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(listCmd, listSupportedCmd, getExampleConfigCmd, createCmd, updateCmd, deleteCmd, deleteAllCmd)
 	return rootCmd
 }
+
+// unmarshalHooks decodes a JSON or YAML list of hooks and returns them with the revision of the input if it
+// has one. The list may also be wrapped in an object with the attribute hooks and optionally rev, for
+// example the output of list. An object without the attribute hooks is rejected.
+func unmarshalHooks(b []byte) ([]Params, *int, error) {
+
+	var hooks []Params
+
+	var wrapper struct {
+		Hooks *[]Params `json:"hooks" yaml:"hooks"`
+		Rev   *int      `json:"rev" yaml:"rev"`
+	}
+
+	var errors *multierror.Error
+
+	for _, unmarshal := range []func([]byte, any) error{json.Unmarshal, yaml.Unmarshal} {
+
+		err := unmarshal(b, &hooks)
+		if err == nil {
+			return hooks, nil, nil
+		}
+		errors = multierror.Append(errors, err)
+
+		err = unmarshal(b, &wrapper)
+		if err == nil {
+			if wrapper.Hooks == nil {
+				return nil, nil, fmt.Errorf("object does not have the attribute hooks")
+			}
+			return *wrapper.Hooks, wrapper.Rev, nil
+		}
+		errors = multierror.Append(errors, err)
+	}
+
+	errors = multierror.Append(errors, fmt.Errorf("Invalid format. Expect JSON or YAML"))
+	return nil, nil, errors.ErrorOrNil()
+}
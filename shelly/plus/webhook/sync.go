@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+// SyncOptions controls a sync
+type SyncOptions struct {
+	// DryRun computes the report without sending changes to the device
+	DryRun bool
+	// Rev expected revision of the webhooks, for example the revision reported by a dry run. The sync is
+	// refused with a *ConflictError if the webhooks were changed since. Nil is not checked.
+	Rev *int
+}
+
+// Sync makes the webhooks of the device match the desired hooks. Hooks are matched by name; a hook
+// that exists on the device but differs from the desired one is updated, missing hooks are created and
+// hooks on the device that are not desired are deleted; the deletes are sent first. Every desired hook
+// must have a unique name. The report only lists the hooks that were written. If options.DryRun is set
+// the report is computed but no changes are sent to the device.
+func (t *Client) Sync(ctx context.Context, desired []Params, options *SyncOptions) (*SyncReport, error) {
+
+	if options == nil {
+		options = &SyncOptions{}
+	}
+
+	dryRun := options.DryRun
+
+	names := make(map[string]bool)
+
+	for i, params := range desired {
+		if params.Name == nil || *params.Name == "" {
+			return nil, fmt.Errorf("hook %d: name is required", i)
+		}
+		if names[*params.Name] {
+			return nil, fmt.Errorf("hook %s: name is not unique", *params.Name)
+		}
+		names[*params.Name] = true
+	}
+
+	current, err := t.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Rev != nil && *options.Rev != current.Rev {
+		return nil, &types.ConflictError{
+			Name:    "webhook_rev",
+			Planned: *options.Rev,
+			Current: current.Rev,
+		}
+	}
+
+	existing := make(map[string]Webhook)
+	var unwanted []Webhook
+
+	for _, hook := range current.Hooks {
+
+		if hook.Name == nil || !names[*hook.Name] {
+			unwanted = append(unwanted, hook)
+			continue
+		}
+
+		if _, ok := existing[*hook.Name]; ok {
+			// Duplicate name; keep the first one
+			unwanted = append(unwanted, hook)
+			continue
+		}
+
+		existing[*hook.Name] = hook
+	}
+
+	report := &SyncReport{
+		DryRun: dryRun,
+		Rev:    current.Rev,
+	}
+
+	// Unwanted hooks are deleted first so that renamed hooks do not exceed the hook limit of the device.
+	// A hook is added to the report once it was written.
+
+	for _, hook := range unwanted {
+
+		if hook.ID == nil {
+			continue
+		}
+
+		name := strconv.Itoa(*hook.ID)
+		if hook.Name != nil && *hook.Name != "" {
+			name = *hook.Name
+		}
+
+		if !dryRun {
+			result, err := t.Delete(ctx, *hook.ID)
+			if err != nil {
+				return report, fmt.Errorf("delete %s :: %w", name, err)
+			}
+			report.Rev = result.Rev
+		}
+
+		report.Deleted = append(report.Deleted, name)
+	}
+
+	for _, params := range desired {
+
+		name := *params.Name
+		params := params
+
+		hook, ok := existing[name]
+
+		if !ok {
+
+			if !dryRun {
+				params.ID = nil
+				result, err := t.Create(ctx, &params)
+				if err != nil {
+					return report, fmt.Errorf("create %s :: %w", name, err)
+				}
+				report.Rev = result.Rev
+			}
+
+			report.Created = append(report.Created, name)
+			continue
+		}
+
+		if hookMatches(&hook, &params) {
+			report.Unchanged = append(report.Unchanged, name)
+			continue
+		}
+
+		if !dryRun {
+			params.ID = hook.ID
+			result, err := t.Update(ctx, &params)
+			if err != nil {
+				return report, fmt.Errorf("update %s :: %w", name, err)
+			}
+			report.Rev = result.Rev
+		}
+
+		report.Updated = append(report.Updated, name)
+	}
+
+	return report, nil
+}
+
+// hookMatches returns true if the existing hook has the same settings as the params
+func hookMatches(hook *Webhook, params *Params) bool {
+
+	if hook.Event != params.Event || hook.Cid != params.Cid || hook.Enable != params.Enable {
+		return false
+	}
+
+	if hook.RepeatPeriod != params.RepeatPeriod {
+		return false
+	}
+
+	if stringValue(hook.SslCa) != stringValue(params.SslCa) {
+		return false
+	}
+
+	condition := ""
+	if s, ok := hook.Condition.(string); ok {
+		condition = s
+	}
+
+	if condition != stringValue(params.Condition) {
+		return false
+	}
+
+	if !sliceMatches(hook.URLs, params.URLs) {
+		return false
+	}
+
+	return sliceMatches(hook.ActiveBetween, params.ActiveBetween)
+}
+
+func sliceMatches(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
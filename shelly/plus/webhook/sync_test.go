@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jodydadescott/shelly-manager/shelly/plus/types"
+)
+
+// testHandler answers Webhook.List with the hooks and records the methods that were called
+type testHandler struct {
+	list    string
+	methods []string
+}
+
+func (t *testHandler) Send(ctx context.Context, request *Request) ([]byte, error) {
+
+	t.methods = append(t.methods, request.Method)
+
+	if request.Method == "Webhook.List" {
+		return []byte(`{"id":1,"result":` + t.list + `}`), nil
+	}
+
+	return []byte(`{"id":1,"result":{"rev":6}}`), nil
+}
+
+func (t *testHandler) Close()                                             {}
+func (t *testHandler) NewHandle() MessageHandler                          { return t }
+func (t *testHandler) Subscribe(handler types.NotificationHandler) func() { return func() {} }
+
+func TestSyncRefusesChangedWebhooks(t *testing.T) {
+
+	handler := &testHandler{
+		list: `{"hooks":[{"id":1,"name":"old","event":"switch.on","enable":true}],"rev":5}`,
+	}
+
+	client := New(handler)
+
+	name := "new"
+	desired := []Params{{Name: &name, Event: "switch.off", Enable: true}}
+
+	planned := 4
+
+	_, err := client.Sync(context.Background(), desired, &SyncOptions{Rev: &planned})
+
+	var conflict *types.ConflictError
+	if !errors.As(err, &conflict) || conflict.Name != "webhook_rev" {
+		t.Fatalf("expected a webhook_rev conflict, got %v", err)
+	}
+
+	if len(handler.methods) != 1 {
+		t.Errorf("changes were sent after the conflict: %v", handler.methods)
+	}
+
+	planned = 5
+
+	_, err = client.Sync(context.Background(), desired, &SyncOptions{Rev: &planned})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// failingHandler answers Webhook.List with a hook and fails every write
+type failingHandler struct {
+	methods []string
+}
+
+func (t *failingHandler) Send(ctx context.Context, request *Request) ([]byte, error) {
+
+	t.methods = append(t.methods, request.Method)
+
+	if request.Method == "Webhook.List" {
+		return []byte(`{"id":1,"result":{"hooks":[{"id":1,"name":"old","event":"switch.on","enable":true}],"rev":5}}`), nil
+	}
+
+	if request.Method == "Webhook.Create" {
+		return []byte(`{"id":1,"error":{"code":-109,"message":"resource exhausted"}}`), nil
+	}
+
+	return []byte(`{"id":1,"result":{"rev":6}}`), nil
+}
+
+func (t *failingHandler) Close()                                             {}
+func (t *failingHandler) NewHandle() MessageHandler                          { return t }
+func (t *failingHandler) Subscribe(handler types.NotificationHandler) func() { return func() {} }
+
+func TestSyncDeletesFirstAndReportsWrites(t *testing.T) {
+
+	handler := &failingHandler{}
+	client := New(handler)
+
+	name := "new"
+	desired := []Params{{Name: &name, Event: "switch.off", Enable: true}}
+
+	report, err := client.Sync(context.Background(), desired, nil)
+	if err == nil {
+		t.Fatal("expected the create to fail")
+	}
+
+	expected := []string{"Webhook.List", "Webhook.Delete", "Webhook.Create"}
+	if len(handler.methods) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, handler.methods)
+	}
+	for i := range expected {
+		if handler.methods[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, handler.methods)
+		}
+	}
+
+	if len(report.Created) != 0 {
+		t.Errorf("failed create is reported: %v", report.Created)
+	}
+
+	if len(report.Deleted) != 1 {
+		t.Errorf("delete is not reported: %v", report.Deleted)
+	}
+}
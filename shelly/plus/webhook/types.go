@@ -8,6 +8,8 @@ type Webhook = types.WebhookHook
 type Webhooks = types.Webhooks
 type WebhookParams = types.WebhookParams
 type Params = types.WebhookParams
+type DeleteParams = types.WebhookDeleteParams
+type SyncReport = types.WebhookSyncReport
 type WebhookEventInputToggleOn = types.WebhookEventInputToggleOn
 type WebhookEventInputToggleOff = types.WebhookEventInputToggleOff
 type WebhookEventInputButtonPush = types.WebhookEventInputButtonPush
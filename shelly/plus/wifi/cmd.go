@@ -8,6 +8,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/jodydadescott/shelly-manager/shelly/util"
 	"github.com/spf13/cobra"
 )
 
@@ -105,6 +106,16 @@ func NewCmd(callback callback) *cobra.Command {
 				}
 			}
 
+			err = config.ResolveSecrets(util.ResolveSecret)
+			if err != nil {
+				return err
+			}
+
+			err = config.Validate()
+			if err != nil {
+				return err
+			}
+
 			report, err := client.SetConfig(cmd.Context(), config)
 
 			if autorebootArg {
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// LocalIP returns the IP address of the local interface that is used to reach the host. The host
+// may include a port. No packets are sent.
+func LocalIP(hostname string) (net.IP, error) {
+
+	host := hostname
+	if h, _, err := net.SplitHostPort(hostname); err == nil {
+		host = h
+	}
+
+	conn, err := net.Dial("udp4", net.JoinHostPort(host, "80"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// ARPTable is the kernel ARP table. It is only available on Linux.
+var ARPTable = "/proc/net/arp"
+
+// LookupARP returns the IPv4 address of the MAC address from the ARP table. The MAC may be written
+// with or without separators and in any case.
+func LookupARP(mac string) (net.IP, error) {
+
+	b, err := os.ReadFile(ARPTable)
+	if err != nil {
+		return nil, err
+	}
+
+	want := normalizeMAC(mac)
+
+	// IP address       HW type     Flags       HW address            Mask     Device
+	for _, line := range strings.Split(string(b), "\n")[1:] {
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		if normalizeMAC(fields[3]) != want {
+			continue
+		}
+
+		ip := net.ParseIP(fields[0])
+		if ip != nil {
+			return ip, nil
+		}
+	}
+
+	return nil, fmt.Errorf("MAC %s not found in ARP table", mac)
+}
+
+func normalizeMAC(mac string) string {
+	mac = strings.ReplaceAll(mac, ":", "")
+	mac = strings.ReplaceAll(mac, "-", "")
+	return strings.ToLower(mac)
+}
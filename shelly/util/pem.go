@@ -0,0 +1,126 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// ParseCertificates parses PEM data that must contain one or more certificates and nothing else
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+
+	var certs []*x509.Certificate
+
+	rest := data
+
+	for {
+
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block %s; expect CERTIFICATE", block.Type)
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+
+	return certs, nil
+}
+
+// ParsePrivateKey parses PEM data that must contain a single PKCS1, PKCS8 or EC private key
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM private key found")
+	}
+
+	if next, _ := pem.Decode(rest); next != nil {
+		return nil, fmt.Errorf("unexpected PEM block %s after private key", next.Type)
+	}
+
+	switch block.Type {
+
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("private key type %T is not supported", key)
+		}
+		return signer, nil
+
+	}
+
+	return nil, fmt.Errorf("unexpected PEM block %s; expect a private key", block.Type)
+}
+
+// KeyMatchesCertificate returns true if the public key of the certificate belongs to the private key
+func KeyMatchesCertificate(cert *x509.Certificate, key crypto.Signer) bool {
+
+	type equaler interface {
+		Equal(crypto.PublicKey) bool
+	}
+
+	switch pub := key.Public().(type) {
+
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return pub.(equaler).Equal(cert.PublicKey)
+
+	}
+
+	return false
+}
+
+// CertificateWarnings returns warnings for certificates that are expired, not yet valid or expire within
+// the warn duration
+func CertificateWarnings(certs []*x509.Certificate, now time.Time, warn time.Duration) []string {
+
+	var warnings []string
+
+	for _, cert := range certs {
+
+		name := cert.Subject.String()
+
+		switch {
+
+		case now.After(cert.NotAfter):
+			warnings = append(warnings, fmt.Sprintf("certificate %s expired on %s", name, cert.NotAfter.Format(time.RFC3339)))
+
+		case now.Before(cert.NotBefore):
+			warnings = append(warnings, fmt.Sprintf("certificate %s is not valid before %s", name, cert.NotBefore.Format(time.RFC3339)))
+
+		case now.Add(warn).After(cert.NotAfter):
+			warnings = append(warnings, fmt.Sprintf("certificate %s expires on %s", name, cert.NotAfter.Format(time.RFC3339)))
+
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// SecretEnvPrefix references an environment variable, for example env:WIFI_PASS
+	SecretEnvPrefix = "env:"
+	// SecretFilePrefix references a file, for example file:/run/secrets/wifi_pass
+	SecretFilePrefix = "file:"
+	// SecretCmdPrefix references the output of a shell command, for example cmd:pass show wifi
+	SecretCmdPrefix = "cmd:"
+	// SecretMask replaces secrets in output
+	SecretMask = "********"
+)
+
+// ResolveSecret returns the value of a secret reference. References start with env:, file: or cmd:.
+// A trailing newline is removed from file contents and command output. Values without a known prefix
+// are returned unchanged.
+func ResolveSecret(value string) (string, error) {
+
+	switch {
+
+	case value == SecretMask:
+		return "", fmt.Errorf("value is a masked secret; write the config with --show-secrets or use a reference")
+
+	case strings.HasPrefix(value, SecretEnvPrefix):
+		name := strings.TrimPrefix(value, SecretEnvPrefix)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return secret, nil
+
+	case strings.HasPrefix(value, SecretFilePrefix):
+		name := strings.TrimPrefix(value, SecretFilePrefix)
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+
+	case strings.HasPrefix(value, SecretCmdPrefix):
+		command := strings.TrimPrefix(value, SecretCmdPrefix)
+		var stderr bytes.Buffer
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Stderr = &stderr
+		b, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("command %q failed: %w; %s", command, err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+
+	return value, nil
+}
+
+// IsSecretReference returns true if the value is a reference to a secret rather than the secret itself
+func IsSecretReference(value string) bool {
+	for _, prefix := range []string{SecretEnvPrefix, SecretFilePrefix, SecretCmdPrefix} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskJSON replaces the non empty string values of the keys with SecretMask at any depth. Secret
+// references are kept. The order of the keys is kept. Masked is true if any value was replaced.
+func MaskJSON(data []byte, keys []string) (result []byte, masked bool, err error) {
+
+	secret := make(map[string]bool)
+	for _, key := range keys {
+		secret[key] = true
+	}
+
+	type frame struct {
+		object    bool
+		expectKey bool
+		count     int
+	}
+
+	var stack []*frame
+	var out bytes.Buffer
+	maskNext := false
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	// beginValue writes the separator of array elements
+	beginValue := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if !top.object {
+			if top.count > 0 {
+				out.WriteByte(',')
+			}
+			top.count++
+		}
+	}
+
+	// endValue expects the next key if the value was a member of an object
+	endValue := func() {
+		maskNext = false
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.object {
+			top.expectKey = true
+		}
+	}
+
+	for {
+
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				beginValue()
+				out.WriteByte(byte(delim))
+				stack = append(stack, &frame{object: delim == '{', expectKey: delim == '{'})
+				maskNext = false
+			case '}', ']':
+				out.WriteByte(byte(delim))
+				stack = stack[:len(stack)-1]
+				endValue()
+			}
+			continue
+		}
+
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.object && top.expectKey {
+				key := token.(string)
+				if top.count > 0 {
+					out.WriteByte(',')
+				}
+				top.count++
+				top.expectKey = false
+				b, err := json.Marshal(key)
+				if err != nil {
+					return nil, false, err
+				}
+				out.Write(b)
+				out.WriteByte(':')
+				maskNext = secret[key]
+				continue
+			}
+		}
+
+		beginValue()
+
+		if s, ok := token.(string); ok && maskNext && s != "" && !IsSecretReference(s) {
+			token = SecretMask
+			masked = true
+		}
+
+		b, err := json.Marshal(token)
+		if err != nil {
+			return nil, false, err
+		}
+		out.Write(b)
+		endValue()
+	}
+
+	return out.Bytes(), masked, nil
+}